@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/k8s"
 
@@ -24,14 +25,28 @@ func MakeInstallArgoCD() *cobra.Command {
 		SilenceUsage: true,
 	}
 
+	apps.AddIngressFlags(command)
+
 	command.RunE = func(command *cobra.Command, args []string) error {
+		ingress := apps.IngressFromFlags(command, apps.Ingress{
+			App:          "argocd",
+			Name:         "argocd-server",
+			Namespace:    "argocd",
+			Service:      "argocd-server",
+			Port:         443,
+			BackendHTTPS: true,
+		})
+
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
 		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
+		if ingress.Enabled() {
+			if err := apps.PreflightIngress("argocd", ingress); err != nil {
+				return err
+			}
+		}
 
 		if _, err := k8s.KubectlTask("create", "ns",
 			"argocd"); err != nil {
@@ -52,6 +67,12 @@ func MakeInstallArgoCD() *cobra.Command {
 			return err
 		}
 
+		if ingress.Enabled() {
+			if err := apps.InstallIngress("argocd", ingress); err != nil {
+				return err
+			}
+		}
+
 		fmt.Println(ArgoCDInfoMsgInstallMsg)
 
 		return nil
@@ -4,14 +4,12 @@
 package apps
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/alexellis/arkade/pkg/config"
 
 	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -62,11 +60,6 @@ func MakeInstallCassandra() *cobra.Command {
 			return fmt.Errorf("error with --set usage: %s", err)
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		if arch != IntelArch {
-			return errors.New(OnlyIntelArch)
-		}
-
 		return nil
 	}
 
@@ -97,7 +90,8 @@ func MakeInstallCassandra() *cobra.Command {
 			WithOverrides(overrides).
 			WithHelmUpdateRepo(updateRepo).
 			WithKubeconfigPath(kubeConfigPath).
-			WithWait(wait)
+			WithWait(wait).
+			WithRequirements(types.Requirements{Architectures: []string{IntelArch}})
 
 		_, err := apps.MakeInstallChart(cassandraAppOptions)
 		if err != nil {
@@ -5,9 +5,9 @@ package apps
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"path"
 	"strings"
 
 	"github.com/alexellis/arkade/pkg"
@@ -15,6 +15,7 @@ import (
 	"github.com/alexellis/arkade/pkg/env"
 	"github.com/alexellis/arkade/pkg/helm"
 	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -30,18 +31,37 @@ before using the generic helm chart installer command.`,
 		Example: `  arkade install chart --repo-name stable/nginx-ingress \
      --set controller.service.type=NodePort
   arkade install chart --repo-name inlets/inlets-operator \
-     --repo-url https://inlets.github.io/inlets-operator/`,
+     --repo-url https://inlets.github.io/inlets-operator/
+
+  # A private chart repository
+  echo $PASSWORD | arkade install chart --repo-name internal/api \
+     --repo-url https://charts.example.com \
+     --username ci --password-stdin --ca-file ./ca.crt
+
+  # A private OCI registry, after "arkade oci login"
+  arkade install chart --repo-name oci://registry.example.com/charts/api
+
+  # A local chart directory, packaged chart, or URL to a packaged chart
+  arkade install chart --repo-name ./chart/api --values ./dev.yaml
+  arkade install chart --repo-name ./api-0.1.0.tgz
+  arkade install chart --repo-name https://example.com/charts/api-0.1.0.tgz`,
 		SilenceUsage: true,
 	}
 
 	chartCmd.Flags().StringP("namespace", "n", "default", "The namespace to install the chart")
 	chartCmd.Flags().String("repo", "", "The chart repo to install from")
 	chartCmd.Flags().StringArrayP("values", "f", []string{"values.yaml"}, "Give the values.yaml file to use from the upstream chart repo")
-	chartCmd.Flags().String("repo-name", "", "Chart name")
+	chartCmd.Flags().String("repo-name", "", "Chart name, or a local chart directory, .tgz file or URL")
 	chartCmd.Flags().String("repo-url", "", "Chart repo")
 
 	chartCmd.Flags().StringArray("set", []string{}, "Set individual values in the helm chart")
 
+	chartCmd.Flags().String("username", "", "Username for a private chart repository")
+	chartCmd.Flags().Bool("password-stdin", false, "Read the password for a private chart repository from stdin")
+	chartCmd.Flags().String("ca-file", "", "Verify the chart repository's certificate with this CA bundle")
+	chartCmd.Flags().String("cert-file", "", "Client certificate for the chart repository")
+	chartCmd.Flags().String("key-file", "", "Client key for the chart repository")
+
 	chartCmd.RunE = func(command *cobra.Command, args []string) error {
 		chartRepoName, _ := command.Flags().GetString("repo-name")
 		chartRepoURL, _ := command.Flags().GetString("repo-url")
@@ -55,6 +75,11 @@ before using the generic helm chart installer command.`,
 			return fmt.Errorf("--repo-name required")
 		}
 
+		auth, err := chartRepoAuth(command, os.Stdin)
+		if err != nil {
+			return err
+		}
+
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
 		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
 			return err
@@ -73,15 +98,12 @@ before using the generic helm chart installer command.`,
 
 		log.Printf("User dir established as: %s\n", userPath)
 
-		os.Setenv("HELM_HOME", path.Join(userPath, ".helm"))
+		os.Setenv("HELM_HOME", config.GetHelmDir())
 
-		_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-		if err != nil {
-			return err
-		}
+		local := helm.IsLocalChart(chartRepoName)
 
-		if len(chartRepoURL) > 0 {
-			err = helm.AddHelmRepo(chartPrefix, chartRepoURL, true)
+		if len(chartRepoURL) > 0 && !helm.IsOCI(chartRepoName) && !local {
+			err = helm.AddHelmRepoWithAuth(chartPrefix, chartRepoURL, true, auth)
 			if err != nil {
 				return err
 			}
@@ -100,11 +122,6 @@ before using the generic helm chart installer command.`,
 			}
 		}
 
-		err = helm.FetchChart(chartRepoName, defaultVersion)
-		if err != nil {
-			return err
-		}
-
 		setMap := map[string]string{}
 		setVals, _ := chartCmd.Flags().GetStringArray("set")
 
@@ -121,11 +138,35 @@ before using the generic helm chart installer command.`,
 
 		valuesFlag, _ := chartCmd.Flags().GetStringArray("values")
 
-		err = helm.Helm3Upgrade(chartRepoName, namespace,
-			valuesFlag,
-			defaultVersion,
-			setMap,
-			false)
+		// The default values.yaml is read from within the chart, which
+		// is only pulled for a chart from a repository
+		if (local || helm.IsOCI(chartRepoName)) && !chartCmd.Flags().Changed("values") {
+			valuesFlag = nil
+		}
+
+		if local {
+			err = helm.Helm3LocalUpgrade(chartRepoName, namespace,
+				valuesFlag,
+				setMap,
+				false)
+		} else if helm.IsOCI(chartRepoName) {
+			err = helm.Helm3OCIUpgradeWithAuth(chartRepoName, namespace,
+				valuesFlag,
+				defaultVersion,
+				setMap,
+				false,
+				auth)
+		} else {
+			if err := helm.FetchChart(chartRepoName, defaultVersion); err != nil {
+				return err
+			}
+
+			err = helm.Helm3Upgrade(chartRepoName, namespace,
+				valuesFlag,
+				defaultVersion,
+				setMap,
+				false)
+		}
 
 		if err != nil {
 			return err
@@ -143,3 +184,31 @@ before using the generic helm chart installer command.`,
 
 	return chartCmd
 }
+
+// chartRepoAuth reads the credentials for a private chart repository,
+// with the password from stdin.
+func chartRepoAuth(command *cobra.Command, stdin io.Reader) (types.HelmRepoAuth, error) {
+	auth := types.HelmRepoAuth{}
+	auth.Username, _ = command.Flags().GetString("username")
+	auth.CAFile, _ = command.Flags().GetString("ca-file")
+	auth.CertFile, _ = command.Flags().GetString("cert-file")
+	auth.KeyFile, _ = command.Flags().GetString("key-file")
+
+	if (len(auth.CertFile) > 0) != (len(auth.KeyFile) > 0) {
+		return auth, fmt.Errorf("give both --cert-file and --key-file for a client certificate")
+	}
+
+	if passwordStdin, _ := command.Flags().GetBool("password-stdin"); passwordStdin {
+		if len(auth.Username) == 0 {
+			return auth, fmt.Errorf("--username is required with --password-stdin")
+		}
+
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return auth, fmt.Errorf("unable to read the password from stdin: %w", err)
+		}
+		auth.Password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return auth, nil
+}
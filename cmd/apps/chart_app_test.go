@@ -0,0 +1,40 @@
+package apps
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_chartRepoAuth_PasswordStdin(t *testing.T) {
+	command := MakeInstallChart()
+	command.Flags().Set("username", "ci")
+	command.Flags().Set("password-stdin", "true")
+	command.Flags().Set("ca-file", "ca.crt")
+
+	auth, err := chartRepoAuth(command, strings.NewReader("s3cr3t\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if auth.Username != "ci" || auth.Password != "s3cr3t" || auth.CAFile != "ca.crt" {
+		t.Errorf("unexpected auth: %+v", auth)
+	}
+}
+
+func Test_chartRepoAuth_PasswordStdinNeedsUsername(t *testing.T) {
+	command := MakeInstallChart()
+	command.Flags().Set("password-stdin", "true")
+
+	if _, err := chartRepoAuth(command, strings.NewReader("s3cr3t\n")); err == nil {
+		t.Fatal("want an error without --username")
+	}
+}
+
+func Test_chartRepoAuth_CertNeedsKey(t *testing.T) {
+	command := MakeInstallChart()
+	command.Flags().Set("cert-file", "client.crt")
+
+	if _, err := chartRepoAuth(command, strings.NewReader("")); err == nil {
+		t.Fatal("want an error without --key-file")
+	}
+}
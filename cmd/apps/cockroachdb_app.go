@@ -9,7 +9,6 @@ import (
 	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -45,13 +44,6 @@ func MakeInstallCockroachdb() *cobra.Command {
 		enableTls, _ := command.Flags().GetBool("enable-tls")
 		replicas, _ := command.Flags().GetInt64("replicas")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
-		if suffix := getValuesSuffix(arch); suffix == "-armhf" {
-			return fmt.Errorf(`CockroachDB is currently not supported on armhf architectures`)
-		}
-
 		updateRepo, _ := cockroachdb.Flags().GetBool("update-repo")
 
 		overrides := map[string]string{}
@@ -79,7 +71,8 @@ func MakeInstallCockroachdb() *cobra.Command {
 			WithHelmURL("https://charts.cockroachdb.com/").
 			WithHelmUpdateRepo(updateRepo).
 			WithOverrides(overrides).
-			WithKubeconfigPath(kubeConfigPath)
+			WithKubeconfigPath(kubeConfigPath).
+			WithRequirements(types.Requirements{Architectures: Archs64Bit})
 
 		_, err := apps.MakeInstallChart(cockroachdbOptions)
 		if err != nil {
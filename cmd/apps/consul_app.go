@@ -42,9 +42,6 @@ func MakeInstallConsul() *cobra.Command {
 	consul.RunE = func(command *cobra.Command, args []string) error {
 		updateRepo, _ := consul.Flags().GetBool("update-repo")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		namespace, _ := consul.Flags().GetString("namespace")
 
 		overrides := map[string]string{}
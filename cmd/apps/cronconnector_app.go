@@ -5,7 +5,6 @@ import (
 	"github.com/alexellis/arkade/pkg/config"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -43,9 +42,6 @@ func MakeInstallCronConnector() *cobra.Command {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		cronConnectorAppOptions := types.DefaultInstallOptions().
 			WithNamespace(namespace).
 			WithHelmRepo("openfaas/cron-connector").
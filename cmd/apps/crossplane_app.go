@@ -7,12 +7,13 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path"
 	"strings"
 
 	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/env"
 	"github.com/alexellis/arkade/pkg/helm"
@@ -47,11 +48,9 @@ schedule workloads to any Kubernetes cluster`,
 			return fmt.Errorf(`to override the namespace, install crossplane via helm manually`)
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		if !strings.Contains(arch, "64") {
-			return fmt.Errorf(`crossplane is currently only supported on 64-bit architectures`)
+		if err := apps.Preflight("crossplane", &types.Requirements{Architectures: Archs64Bit}); err != nil {
+			return err
 		}
-		fmt.Printf("Node architecture: %q\n", arch)
 
 		userPath, err := config.InitUserDir()
 		if err != nil {
@@ -73,12 +72,7 @@ schedule workloads to any Kubernetes cluster`,
 
 		log.Printf("User dir established as: %s\n", userPath)
 
-		os.Setenv("HELM_HOME", path.Join(userPath, ".helm"))
-
-		_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-		if err != nil {
-			return err
-		}
+		os.Setenv("HELM_HOME", config.GetHelmDir())
 
 		updateRepo, _ := crossplane.Flags().GetBool("update-repo")
 		err = helm.AddHelmRepo("crossplane-alpha", "https://charts.crossplane.io/alpha", updateRepo)
@@ -0,0 +1,200 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alexellis/arkade/pkg"
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/helm"
+	"github.com/alexellis/arkade/pkg/types"
+)
+
+//go:embed definitions/*.yaml
+var definitionFiles embed.FS
+
+// Definitions returns the apps which are built into arkade as YAML, see
+// the definitions directory.
+func Definitions() ([]pkgapps.Definition, error) {
+	fsys, err := fs.Sub(definitionFiles, "definitions")
+	if err != nil {
+		return nil, err
+	}
+	return pkgapps.LoadDefinitions(fsys)
+}
+
+// MakeInstallDefinition creates the install command for an app which is
+// defined in YAML, rather than in Go.
+func MakeInstallDefinition(d pkgapps.Definition) func() *cobra.Command {
+	return func() *cobra.Command {
+		command := &cobra.Command{
+			Use:          d.Name,
+			Short:        d.Short,
+			Long:         d.Long,
+			Example:      d.Example,
+			SilenceUsage: true,
+		}
+		if len(command.Short) == 0 {
+			command.Short = "Install " + d.Name
+		}
+		if len(command.Long) == 0 {
+			command.Long = command.Short
+		}
+		if len(command.Example) == 0 {
+			command.Example = "  arkade install " + d.Name
+		}
+
+		namespace := d.Namespace
+		if len(namespace) == 0 {
+			namespace = "default"
+		}
+
+		command.Flags().StringP("namespace", "n", namespace, "The namespace used for installation")
+		command.Flags().Bool("update-repo", true, "Update the helm repo")
+		command.Flags().StringArrayP("values", "f", []string{}, "Values files to use along with the chart's own values.yaml")
+		command.Flags().StringArray("set", []string{}, "Set individual values in the helm chart")
+
+		// Defaults have already been checked by Validate
+		for _, f := range d.Flags {
+			switch f.Type {
+			case "bool":
+				v, _ := strconv.ParseBool(f.Default)
+				command.Flags().BoolP(f.Name, f.Shorthand, v, f.Description)
+			case "int":
+				v, _ := strconv.Atoi(f.Default)
+				command.Flags().IntP(f.Name, f.Shorthand, v, f.Description)
+			default:
+				command.Flags().StringP(f.Name, f.Shorthand, f.Default, f.Description)
+			}
+		}
+
+		command.RunE = func(command *cobra.Command, args []string) error {
+			kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
+			if err := config.SetKubeconfig(kubeConfigPath); err != nil {
+				return err
+			}
+
+			wait, _ := command.Flags().GetBool("wait")
+			namespace, _ := command.Flags().GetString("namespace")
+			updateRepo, _ := command.Flags().GetBool("update-repo")
+
+			overrides, err := definitionOverrides(d, command.Flags())
+			if err != nil {
+				return err
+			}
+
+			valuesFiles, err := definitionValuesFiles(d, command.Flags())
+			if err != nil {
+				return err
+			}
+
+			url := d.Repo.URL
+			if helm.IsOCI(d.Chart) {
+				url = d.Chart
+			}
+
+			options := types.DefaultInstallOptions().
+				WithNamespace(namespace).
+				WithHelmRepo(d.ChartRef()).
+				WithHelmURL(url).
+				WithHelmRepoVersion(d.Version).
+				WithHelmUpdateRepo(updateRepo).
+				WithValuesFiles(valuesFiles).
+				WithOverrides(overrides).
+				WithWait(wait).
+				WithKubeconfigPath(kubeConfigPath)
+
+			if _, err := pkgapps.MakeInstallChart(options); err != nil {
+				return err
+			}
+
+			info, err := pkgapps.RenderInfo(d.Info, pkgapps.InfoData{Flags: definitionFlags(command.Flags())})
+			if err != nil {
+				return err
+			}
+
+			line := strings.Repeat("=", 71)
+			fmt.Printf("%s\n= %-67s =\n%s\n\n%s\n%s\n", line, d.Name+" has been installed.", line, info, pkg.SupportMessageShort)
+			return nil
+		}
+
+		return command
+	}
+}
+
+// definitionOverrides returns the helm values for an install, from the
+// definition, then its flags, then --set.
+func definitionOverrides(d pkgapps.Definition, flags *pflag.FlagSet) (map[string]string, error) {
+	overrides := map[string]string{}
+	for k, v := range d.Set {
+		overrides[k] = v
+	}
+
+	for _, f := range d.Flags {
+		if len(f.Value) == 0 {
+			continue
+		}
+		// An unset flag without a default leaves the chart's own value
+		if flag := flags.Lookup(f.Name); flag.Changed || len(f.Default) > 0 {
+			overrides[f.Value] = flag.Value.String()
+		}
+	}
+
+	setVals, err := flags.GetStringArray("set")
+	if err != nil {
+		return nil, err
+	}
+	if err := config.MergeFlags(overrides, setVals); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// definitionValuesFiles adds the --values files to the chart's own
+// values.yaml. They are made absolute, as helm reads them from within
+// the chart, and so that "arkade upgrade" can be run from anywhere.
+func definitionValuesFiles(d pkgapps.Definition, flags *pflag.FlagSet) ([]string, error) {
+	files, _ := flags.GetStringArray("values")
+	for i, file := range files {
+		if strings.Contains(file, "://") {
+			continue
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		files[i] = abs
+	}
+	if f := flags.Lookup("values"); f.Changed {
+		if err := f.Value.(pflag.SliceValue).Replace(files); err != nil {
+			return nil, err
+		}
+	}
+
+	// An OCI chart is not pulled, helm reads its defaults itself
+	if helm.IsOCI(d.Chart) {
+		return files, nil
+	}
+	return append([]string{"values.yaml"}, files...), nil
+}
+
+// definitionFlags returns the value of each flag, for the info message.
+func definitionFlags(flags *pflag.FlagSet) map[string]string {
+	values := map[string]string{}
+	flags.VisitAll(func(f *pflag.Flag) {
+		values[f.Name] = f.Value.String()
+	})
+	return values
+}
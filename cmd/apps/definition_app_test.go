@@ -0,0 +1,88 @@
+package apps
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+)
+
+func Test_Definitions_BuiltIn(t *testing.T) {
+	definitions, err := Definitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[string]bool{}
+	for _, d := range definitions {
+		names[d.Name] = true
+	}
+	for _, want := range []string{"kube-state-metrics", "metrics-server"} {
+		if !names[want] {
+			t.Errorf("want built-in definition for %s, got %v", want, names)
+		}
+	}
+}
+
+func Test_MakeInstallDefinition_Overrides(t *testing.T) {
+	d := pkgapps.Definition{
+		Name:      "podinfo",
+		Namespace: "podinfo",
+		Repo:      pkgapps.DefinitionRepo{Name: "podinfo", URL: "https://stefanprodan.github.io/podinfo"},
+		Chart:     "podinfo",
+		Set:       map[string]string{"ui.color": "#34577c", "ui.message": "hello"},
+		Flags: []pkgapps.DefinitionFlag{
+			{Name: "replicas", Type: "int", Default: "2", Value: "replicaCount"},
+			{Name: "tag", Value: "image.tag"},
+			{Name: "hpa", Type: "bool", Value: "hpa.enabled"},
+			{Name: "message", Value: "ui.message"},
+		},
+	}
+
+	command := MakeInstallDefinition(d)()
+	if err := command.ParseFlags([]string{"--message", "hi", "--set", "ui.color=blue"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ns, _ := command.Flags().GetString("namespace"); ns != "podinfo" {
+		t.Errorf("want namespace podinfo, got %s", ns)
+	}
+
+	got, err := definitionOverrides(d, command.Flags())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"ui.color":     "blue",
+		"ui.message":   "hi",
+		"replicaCount": "2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want overrides %v, got %v", want, got)
+	}
+}
+
+func Test_definitionValuesFiles(t *testing.T) {
+	d := pkgapps.Definition{Name: "podinfo", Chart: "podinfo"}
+	command := MakeInstallDefinition(d)()
+	if err := command.ParseFlags([]string{"-f", "dev.yaml", "-f", "https://example.com/values.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := definitionValuesFiles(d, command.Flags())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	abs, _ := filepath.Abs("dev.yaml")
+	want := []string{"values.yaml", abs, "https://example.com/values.yaml"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want values files %v, got %v", want, got)
+	}
+
+	// The absolute path is recorded for "arkade upgrade"
+	if recorded, _ := command.Flags().GetStringArray("values"); !reflect.DeepEqual(recorded, want[1:]) {
+		t.Errorf("want --values %v, got %v", want[1:], recorded)
+	}
+}
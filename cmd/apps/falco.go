@@ -8,7 +8,6 @@ import (
 	"github.com/alexellis/arkade/pkg/config"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -62,9 +61,6 @@ func MakeInstallFalco() *cobra.Command {
 		sidekickEnabled, _ := command.Flags().GetBool("sidekick")
 		webUIEnabled, _ := command.Flags().GetBool("webui")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		overrides := map[string]string{}
 		if err := config.MergeFlags(overrides, customFlags); err != nil {
 			return err
@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -34,14 +33,28 @@ func MakeInstallGitea() *cobra.Command {
 	gitea.Flags().StringP("password", "p", "", "Overide the default random admin-password if this is set")
 	gitea.Flags().StringArray("set", []string{},
 		"Use custom flags or override existing flags \n(example --set persistence.enabled=true)")
+	apps.AddIngressFlags(gitea)
 
 	gitea.RunE = func(command *cobra.Command, args []string) error {
+		ingress := apps.IngressFromFlags(command, apps.Ingress{
+			App:     "gitea",
+			Name:    "gitea",
+			Service: "gitea-http",
+			Port:    3000,
+		})
+
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
+		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
+			return err
+		}
 
-		updateRepo, _ := gitea.Flags().GetBool("update-repo")
+		if ingress.Enabled() {
+			if err := apps.PreflightIngress("gitea", ingress); err != nil {
+				return err
+			}
+		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
+		updateRepo, _ := gitea.Flags().GetBool("update-repo")
 
 		ns, _ := gitea.Flags().GetString("namespace")
 
@@ -95,7 +108,13 @@ func MakeInstallGitea() *cobra.Command {
 			return err
 		}
 
-		fmt.Println(giteaInstallMsg)
+		if ingress.Enabled() {
+			if err := apps.InstallIngress("gitea", ingress); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println(infoMessage(giteaInstallMsg, command))
 		return nil
 	}
 
@@ -34,6 +34,7 @@ func MakeInstallGrafana() *cobra.Command {
 	grafana.Flags().Bool("persistence", false, "Make grafana persistent")
 	grafana.Flags().StringArray("set", []string{},
 		"Use custom flags or override existing flags \n(example --set persistence.enabled=true)")
+	apps.AddIngressFlags(grafana)
 
 	grafana.PreRunE = func(command *cobra.Command, args []string) error {
 		if _, err := command.Flags().GetString("kubeconfig"); err != nil {
@@ -64,6 +65,12 @@ func MakeInstallGrafana() *cobra.Command {
 	}
 
 	grafana.RunE = func(command *cobra.Command, args []string) error {
+		ingress := apps.IngressFromFlags(command, apps.Ingress{
+			App:     "grafana",
+			Name:    "grafana",
+			Service: "grafana",
+			Port:    80,
+		})
 
 		// Get all flags
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
@@ -73,6 +80,16 @@ func MakeInstallGrafana() *cobra.Command {
 		updateRepo, _ := command.Flags().GetBool("update-repo")
 		customFlags, _ := command.Flags().GetStringArray("set")
 
+		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
+			return err
+		}
+
+		if ingress.Enabled() {
+			if err := apps.PreflightIngress("grafana", ingress); err != nil {
+				return err
+			}
+		}
+
 		// define the values to override
 		// due the missing arm support. datasource and dashboard sidecars are not possible
 		overrides := map[string]string{
@@ -104,6 +121,12 @@ func MakeInstallGrafana() *cobra.Command {
 			return err
 		}
 
+		if ingress.Enabled() {
+			if err := apps.InstallIngress("grafana", ingress); err != nil {
+				return err
+			}
+		}
+
 		fmt.Println(grafanaInstallMsg)
 
 		return nil
@@ -10,7 +10,6 @@ import (
 	"io"
 	"log"
 	"os"
-	"path"
 	"strconv"
 	"strings"
 
@@ -81,9 +80,6 @@ IngressController`,
 		wait, _ := command.Flags().GetBool("wait")
 		namespace, _ := command.Flags().GetString("namespace")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		userPath, err := config.InitUserDir()
 		if err != nil {
 			return err
@@ -92,12 +88,7 @@ IngressController`,
 		clientArch, clientOS := env.GetClientArch()
 		fmt.Printf("Client: %q, %q\n", clientArch, clientOS)
 		log.Printf("User dir established as: %s\n", userPath)
-		os.Setenv("HELM_HOME", path.Join(userPath, ".helm"))
-
-		_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-		if err != nil {
-			return err
-		}
+		os.Setenv("HELM_HOME", config.GetHelmDir())
 
 		updateRepo, _ := inletsOperator.Flags().GetBool("update-repo")
 		err = helm.AddHelmRepo("inlets", "https://inlets.github.io/inlets-operator/", updateRepo)
@@ -12,10 +12,12 @@ import (
 	"os"
 
 	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/env"
 	"github.com/alexellis/arkade/pkg/get"
 	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
 	execute "github.com/alexellis/go-execute/v2"
 	"github.com/spf13/cobra"
 )
@@ -99,11 +101,8 @@ func MakeInstallIstio() *cobra.Command {
 		memory, _ := command.Flags().GetString("memory")
 		setOverrides, _ := command.Flags().GetStringArray("set")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
-		if suffix := getValuesSuffix(arch); suffix == "-armhf" {
-			return fmt.Errorf(`istio is currently not supported on armhf architectures`)
+		if err := apps.Preflight("istio", &types.Requirements{Architectures: Archs64Bit}); err != nil {
+			return err
 		}
 
 		userPath, err := config.InitUserDir()
@@ -223,7 +222,7 @@ func istioCli(parts ...string) (execute.ExecResult, error) {
 		StreamStdio: true,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := k8s.Run(context.Background(), task)
 
 	if err != nil {
 		return res, err
@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -82,9 +81,6 @@ func MakeInstallJenkins() *cobra.Command {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		jenkinsAppOptions := types.DefaultInstallOptions().
 			WithNamespace(ns).
 			WithHelmRepo("jenkins/jenkins").
@@ -8,7 +8,6 @@ import (
 	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +24,7 @@ func MakeInstallKubernetesDashboard() *cobra.Command {
 	kubeDashboard.Flags().StringP("namespace", "n", "kubernetes-dashboard", "The namespace to install the chart")
 	kubeDashboard.Flags().StringArray("set", []string{}, "Use custom flags or override existing flags \n(example --set image.tag=v2.5.0)")
 	kubeDashboard.Flags().Bool("update-repo", true, "Update the helm repo")
+	apps.AddIngressFlags(kubeDashboard)
 
 	kubeDashboard.PreRunE = func(cmd *cobra.Command, args []string) error {
 		_, err := cmd.Flags().GetString("namespace")
@@ -41,13 +41,24 @@ func MakeInstallKubernetesDashboard() *cobra.Command {
 	}
 
 	kubeDashboard.RunE = func(cmd *cobra.Command, args []string) error {
+		ingress := apps.IngressFromFlags(cmd, apps.Ingress{
+			App:          "kubernetes-dashboard",
+			Name:         "kubernetes-dashboard",
+			Service:      "kubernetes-dashboard-kong-proxy",
+			Port:         443,
+			BackendHTTPS: true,
+		})
+
 		kubeConfigPath, _ := cmd.Flags().GetString("kubeconfig")
 		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
+		if ingress.Enabled() {
+			if err := apps.PreflightIngress("kubernetes-dashboard", ingress); err != nil {
+				return err
+			}
+		}
 
 		customFlags, _ := cmd.Flags().GetStringArray("set")
 		namespace, _ := cmd.Flags().GetString("namespace")
@@ -72,6 +83,12 @@ func MakeInstallKubernetesDashboard() *cobra.Command {
 			return err
 		}
 
+		if ingress.Enabled() {
+			if err := apps.InstallIngress("kubernetes-dashboard", ingress); err != nil {
+				return err
+			}
+		}
+
 		fmt.Println(KubernetesDashboardInstallMsg)
 
 		return nil
@@ -6,7 +6,6 @@ import (
 	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -75,12 +74,6 @@ func MakeInstallKuma() *cobra.Command {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-		if suffix := getValuesSuffix(arch); suffix == "-armhf" {
-			return fmt.Errorf(`kuma is currently not supported on armhf architectures`)
-		}
-
 		namespace, _ := command.Flags().GetString("namespace")
 		controlPlaneMode, _ := command.Flags().GetString("control-plane-mode")
 		autoScale, _ := command.Flags().GetBool("auto-scale")
@@ -116,7 +109,8 @@ func MakeInstallKuma() *cobra.Command {
 			WithHelmURL("https://kumahq.github.io/charts").
 			WithHelmUpdateRepo(updateRepo).
 			WithOverrides(overrides).
-			WithKubeconfigPath(kubeConfigPath)
+			WithKubeconfigPath(kubeConfigPath).
+			WithRequirements(types.Requirements{Architectures: Archs64Bit})
 
 		_, err := apps.MakeInstallChart(kumaOptions)
 		if err != nil {
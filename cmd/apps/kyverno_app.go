@@ -8,7 +8,6 @@ import (
 	"github.com/alexellis/arkade/pkg/config"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -54,9 +53,6 @@ func MakeInstallKyverno() *cobra.Command {
 		customFlags, _ := command.Flags().GetStringArray("set")
 		namespace, _ := command.Flags().GetString("namespace")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		overrides := map[string]string{}
 		if err := config.MergeFlags(overrides, customFlags); err != nil {
 			return err
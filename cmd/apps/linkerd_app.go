@@ -54,9 +54,6 @@ func MakeInstallLinkerd() *cobra.Command {
 			return fmt.Errorf("you must give a value for the --version flag")
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		userPath, err := getUserPath()
 		if err != nil {
 			return err
@@ -176,7 +173,7 @@ func linkerdCli(parts ...string) (execute.ExecResult, error) {
 		StreamStdio: true,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := k8s.Run(context.Background(), task)
 
 	if err != nil {
 		return res, err
@@ -195,8 +192,7 @@ func getUserPath() (string, error) {
 }
 
 func getExportPath() string {
-	userPath := config.GetUserDir()
-	return path.Join(userPath, "bin/")
+	return config.GetPaths().Bin
 }
 
 var LinkerdInfoMsg = `# Find out more at:
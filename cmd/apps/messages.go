@@ -1,6 +1,32 @@
 package apps
 
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alexellis/arkade/pkg/apps"
+)
+
 var (
-	IntelArch     = "amd64"
-	OnlyIntelArch = `only Intel, i.e. PC architecture is supported for this app`
+	IntelArch = "amd64"
+
+	// Archs64Bit are the node architectures supported by apps which
+	// don't publish images for armhf.
+	Archs64Bit = []string{"amd64", "arm64", "ppc64le", "s390x"}
 )
+
+// infoMessage renders an app's info message with the flags given to
+// its install command. The message is returned as-is if it can't be
+// rendered.
+func infoMessage(message string, command *cobra.Command) string {
+	flags := map[string]string{}
+	command.Flags().VisitAll(func(f *pflag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+
+	out, err := apps.RenderInfo(message, apps.InfoData{Flags: flags})
+	if err != nil {
+		return message
+	}
+	return out
+}
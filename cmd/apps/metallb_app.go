@@ -53,9 +53,6 @@ func MakeInstallMetalLB() *cobra.Command {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		addressRange, _ := command.Flags().GetString("address-range")
 
 		if err := k8s.Kubectl("apply", "-f", MetalLBManifest); err != nil {
@@ -66,7 +63,8 @@ func MakeInstallMetalLB() *cobra.Command {
 
 		token, _ = command.Flags().GetString("memberlist-secretkey")
 
-		if len(token) < 1 {
+		generated := len(token) < 1
+		if generated {
 			randomToken := make([]byte, 32)
 			rand.Read(randomToken)
 			token = b64.StdEncoding.EncodeToString(randomToken)
@@ -81,6 +79,7 @@ func MakeInstallMetalLB() *cobra.Command {
 				Key:   "secretkey",
 				Value: token,
 			}},
+			Generated: generated,
 		}
 
 		if err := k8s.CreateSecret(secret); err != nil {
@@ -110,7 +109,7 @@ echo "Secret Key: $SECRET_KEY"
 
 # Review the generated configuration:
 
-kubectl get configmap -n metallb-system config --template={{.data.config}}
+kubectl get configmap -n metallb-system config -o jsonpath="{.data.config}"
 
 # Find out more at: https://metallb.universe.tf/
 `
@@ -11,7 +11,6 @@ import (
 	"github.com/alexellis/arkade/pkg/config"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -36,6 +35,7 @@ func MakeInstallMinio() *cobra.Command {
 	minio.Flags().Bool("persistence", false, "Enable persistence")
 	minio.Flags().StringArray("set", []string{},
 		"Use custom flags or override existing flags \n(example --set persistence.enabled=true)")
+	apps.AddIngressFlags(minio)
 
 	minio.PreRunE = func(command *cobra.Command, args []string) error {
 		_, err := command.Flags().GetString("kubeconfig")
@@ -86,6 +86,13 @@ func MakeInstallMinio() *cobra.Command {
 	}
 
 	minio.RunE = func(command *cobra.Command, args []string) error {
+		ingress := apps.IngressFromFlags(command, apps.Ingress{
+			App:     "minio",
+			Name:    "minio",
+			Service: "minio",
+			Port:    9000,
+		})
+
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
 		wait, _ := command.Flags().GetBool("wait")
 		updateRepo, _ := command.Flags().GetBool("update-repo")
@@ -96,8 +103,15 @@ func MakeInstallMinio() *cobra.Command {
 		dist, _ := command.Flags().GetBool("distributed")
 		customFlags, _ := command.Flags().GetStringArray("set")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
+		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
+			return err
+		}
+
+		if ingress.Enabled() {
+			if err := apps.PreflightIngress("minio", ingress); err != nil {
+				return err
+			}
+		}
 
 		overrides := map[string]string{}
 
@@ -148,6 +162,12 @@ func MakeInstallMinio() *cobra.Command {
 			return err
 		}
 
+		if ingress.Enabled() {
+			if err := apps.InstallIngress("minio", ingress); err != nil {
+				return err
+			}
+		}
+
 		fmt.Println(minioInstallMsg)
 		return nil
 	}
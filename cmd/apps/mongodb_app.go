@@ -4,16 +4,15 @@
 package apps
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"os"
-	"path"
 	"strconv"
 
-	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/env"
 	"github.com/alexellis/arkade/pkg/helm"
@@ -43,11 +42,8 @@ func MakeInstallMongoDB() *cobra.Command {
 
 		namespace, _ := command.Flags().GetString("namespace")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
-		if arch != IntelArch {
-			return errors.New(OnlyIntelArch)
+		if err := apps.Preflight("mongodb", &types.Requirements{Architectures: []string{IntelArch}}); err != nil {
+			return err
 		}
 
 		userPath, err := config.InitUserDir()
@@ -61,15 +57,10 @@ func MakeInstallMongoDB() *cobra.Command {
 
 		log.Printf("User dir established as: %s\n", userPath)
 
-		os.Setenv("HELM_HOME", path.Join(userPath, ".helm"))
+		os.Setenv("HELM_HOME", config.GetHelmDir())
 
 		persistence, _ := command.Flags().GetBool("persistence")
 
-		_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-		if err != nil {
-			return err
-		}
-
 		updateRepo, _ := command.Flags().GetBool("update-repo")
 		err = helm.AddHelmRepo("bitnami", "https://charts.bitnami.com/bitnami", updateRepo)
 		if err != nil {
@@ -100,7 +91,7 @@ func MakeInstallMongoDB() *cobra.Command {
 		if err != nil {
 			return fmt.Errorf("unable to mongodb chart with helm %s", err)
 		}
-		fmt.Println(mongoDBPostInstallMsg)
+		fmt.Println(infoMessage(mongoDBPostInstallMsg, command))
 		return nil
 	}
 	return command
@@ -114,19 +105,19 @@ var mongoDBPostInstallMsg = `===================================================
 var MongoDBInfoMsg = `
 # MongoDB can be accessed via port 27017 on the following DNS name from within your cluster:
 
-mongodb.{{namespace}}.svc.cluster.local
+mongodb.{{ namespace }}.svc.cluster.local
 
 # To get the root password run:
 
-export MONGODB_ROOT_PASSWORD=$(kubectl get secret --namespace {{namespace}} mongodb -o jsonpath="{.data.mongodb-root-password}" | base64 --decode)
+export MONGODB_ROOT_PASSWORD={{ secret "mongodb" "mongodb-root-password" }}
 
 # To connect to your database run the following command:
 
-kubectl run --namespace {{namespace}} mongodb-client --rm --tty -i --restart='Never' --image bitnami/mongodb --command -- mongo admin --host mongodb --authenticationDatabase admin -u root -p $MONGODB_ROOT_PASSWORD
+kubectl run --namespace {{ namespace }} mongodb-client --rm --tty -i --restart='Never' --image bitnami/mongodb --command -- mongo admin --host mongodb --authenticationDatabase admin -u root -p $MONGODB_ROOT_PASSWORD
 
 # To connect to your database from outside the cluster execute the following commands:
 
-kubectl port-forward --namespace {{namespace}} svc/mongodb 27017:27017 &
+kubectl port-forward --namespace {{ namespace }} svc/mongodb 27017:27017 &
 mongo --host 127.0.0.1 --authenticationDatabase admin -p $MONGODB_ROOT_PASSWORD
 
 # More on GitHub : https://github.com/helm/charts/tree/master/stable/mongodb`
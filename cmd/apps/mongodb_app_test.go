@@ -0,0 +1,31 @@
+package apps
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+func Test_MakeInstallMongoDB_Preflight(t *testing.T) {
+	fake := k8s.NewFakeClient()
+	fake.Arch = "arm64"
+	k8s.SetClient(fake)
+	defer k8s.SetClient(nil)
+
+	command := MakeInstallMongoDB()
+	err := command.RunE(command, nil)
+
+	var preflightErr *apps.PreflightError
+	if !errors.As(err, &preflightErr) {
+		t.Fatalf("want a *PreflightError, got %v", err)
+	}
+	if !strings.Contains(preflightErr.Error(), "arm64") {
+		t.Errorf("want the node architecture in the error, got %s", preflightErr)
+	}
+	if len(fake.Namespaces) > 0 {
+		t.Errorf("want nothing installed, got namespaces %v", fake.Namespaces)
+	}
+}
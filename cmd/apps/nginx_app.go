@@ -87,7 +87,7 @@ flag and the ingress-nginx docs for more info`,
 			return err
 		}
 
-		fmt.Println(nginxIngressInstallMsg)
+		fmt.Println(infoMessage(nginxIngressInstallMsg, command))
 
 		return nil
 	}
@@ -101,7 +101,12 @@ const NginxIngressInfoMsg = `# If you're using a local environment such as "mini
 # If you're using a managed Kubernetes service, then you'll find
 # your LoadBalancer's IP under "EXTERNAL-IP" via:
 
-kubectl get svc ingress-nginx-controller
+kubectl get svc -n {{ namespace }} ingress-nginx-controller
+{{- if live }}
+
+# The LoadBalancer's address is:
+{{ loadBalancer "ingress-nginx-controller" }}
+{{- end }}
 
 # Find out more at:
 # https://github.com/kubernetes/ingress-nginx/tree/master/charts/ingress-nginx`
@@ -8,7 +8,6 @@ import (
 	"github.com/alexellis/arkade/pkg/config"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -63,9 +62,6 @@ for the Kubernetes API.`,
 		updateRepo, _ := command.Flags().GetBool("update-repo")
 		customFlags, _ := command.Flags().GetStringArray("set")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		overrides := map[string]string{}
 		if err := config.MergeFlags(overrides, customFlags); err != nil {
 			return err
@@ -20,7 +20,6 @@ import (
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg/k8s"
-	"github.com/sethvargo/go-password/password"
 
 	"github.com/spf13/cobra"
 )
@@ -36,6 +35,7 @@ func MakeInstallOpenFaaS() *cobra.Command {
 
 	openfaas.Flags().BoolP("basic-auth", "a", true, "Enable authentication")
 	openfaas.Flags().String("basic-auth-password", "", "Overide the default random basic-auth-password if this is set")
+	openfaas.Flags().String("basic-auth-password-from", "", "Read the basic-auth-password from env:NAME, stdin, sops:FILE or file:FILE")
 	openfaas.Flags().BoolP("load-balancer", "l", false, "Add a loadbalancer")
 	openfaas.Flags().StringP("namespace", "n", "openfaas", "The namespace for the core services")
 	openfaas.Flags().Bool("update-repo", true, "Update the helm repo")
@@ -98,18 +98,13 @@ func MakeInstallOpenFaaS() *cobra.Command {
 		}
 
 		if basicAuthEnabled {
-			pass, _ := command.Flags().GetString("basic-auth-password")
-
-			if len(pass) == 0 {
-				var err error
-				pass, err = password.Generate(25, 10, 0, false, true)
-				if err != nil {
-					return err
-				}
+			pass, err := basicAuthPassword(command)
+			if err != nil {
+				return err
 			}
 			secretData := []types.SecretsData{
 				{Type: types.StringLiteralSecret, Key: "basic-auth-user", Value: "admin"},
-				{Type: types.StringLiteralSecret, Key: "basic-auth-password", Value: pass},
+				pass,
 			}
 
 			basicAuthSecret := types.NewGenericSecret("basic-auth", namespace, secretData)
@@ -143,17 +138,10 @@ func MakeInstallOpenFaaS() *cobra.Command {
 		}
 
 		if dashboard {
-			privateKey, publicKey, err := generateJWTKeyPair()
+			dashboardJWT, err := dashboardJWTSecret(namespace)
 			if err != nil {
-				return fmt.Errorf("failed to create JWT key-pair: %s", err)
-			}
-
-			secretData := []types.SecretsData{
-				{Type: types.StringLiteralSecret, Key: "key", Value: string(privateKey)},
-				{Type: types.StringLiteralSecret, Key: "key.pub", Value: string(publicKey)},
+				return err
 			}
-
-			dashboardJWT := types.NewGenericSecret("dashboard-jwt", namespace, secretData)
 			appOpts.WithSecret(dashboardJWT)
 		}
 
@@ -235,7 +223,7 @@ func MakeInstallOpenFaaS() *cobra.Command {
 			return err
 		}
 
-		fmt.Println(openfaasPostInstallMsg)
+		fmt.Println(infoMessage(openfaasPostInstallMsg, command))
 
 		if basicAuthEnabled == false {
 			fmt.Println(
@@ -333,6 +321,22 @@ func getValuesSuffix(arch string) string {
 	return valuesSuffix
 }
 
+// dashboardJWTSecret returns a new key pair for the dashboard's sessions.
+// It's only created once, so that a re-install keeps users logged in.
+func dashboardJWTSecret(namespace string) (types.K8sSecret, error) {
+	privateKey, publicKey, err := generateJWTKeyPair()
+	if err != nil {
+		return types.K8sSecret{}, fmt.Errorf("failed to create JWT key-pair: %s", err)
+	}
+
+	secretData := []types.SecretsData{
+		{Type: types.StringLiteralSecret, Key: "key", Value: string(privateKey)},
+		{Type: types.StringLiteralSecret, Key: "key.pub", Value: string(publicKey)},
+	}
+
+	return types.NewGeneratedSecret("dashboard-jwt", namespace, secretData), nil
+}
+
 func generateJWTKeyPair() ([]byte, []byte, error) {
 	// Private key
 	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
@@ -358,6 +362,27 @@ func generateJWTKeyPair() ([]byte, []byte, error) {
 	return privOut.Bytes(), pubOut.Bytes(), nil
 }
 
+// basicAuthPassword reads the password from --basic-auth-password or
+// --basic-auth-password-from. Otherwise a password is generated, which
+// is kept when OpenFaaS is installed again.
+func basicAuthPassword(command *cobra.Command) (types.SecretsData, error) {
+	const key = "basic-auth-password"
+
+	pass, _ := command.Flags().GetString("basic-auth-password")
+	from, _ := command.Flags().GetString("basic-auth-password-from")
+
+	switch {
+	case len(pass) > 0 && len(from) > 0:
+		return types.SecretsData{}, fmt.Errorf("give either --basic-auth-password or --basic-auth-password-from")
+	case len(pass) > 0:
+		return types.SecretsData{Type: types.StringLiteralSecret, Key: key, Value: pass}, nil
+	case len(from) > 0:
+		return types.ParseSecretSource(key, from)
+	}
+
+	return types.SecretsData{Type: types.GeneratedSecret, Key: key}, nil
+}
+
 const OpenFaaSInfoMsg = `# You've installed OpenFaaS Pro 👍
 # OpenFaaS Standard or for Enterprises is now running (depending on your license)
 
@@ -368,11 +393,16 @@ https://github.com/openfaas/faas/blob/master/pro/EULA.md
 arkade get faas-cli
 
 # Forward the gateway to your machine
-kubectl rollout status -n openfaas deploy/gateway
-kubectl port-forward -n openfaas svc/gateway 8080:8080 &
+kubectl rollout status -n {{ namespace }} deploy/gateway
+kubectl port-forward -n {{ namespace }} svc/gateway 8080:8080 &
+{{- if and live (eq (flag "load-balancer") "true") }}
+
+# The gateway's LoadBalancer is available at:
+http://{{ loadBalancer "gateway-external" }}:8080
+{{- end }}
 
 # If basic auth is enabled, you can now log into your gateway:
-PASSWORD=$(kubectl get secret -n openfaas basic-auth -o jsonpath="{.data.basic-auth-password}" | base64 --decode; echo)
+PASSWORD={{ secret "basic-auth" "basic-auth-password" }}
 echo -n $PASSWORD | faas-cli login --username admin --password-stdin
 
 faas-cli store deploy env
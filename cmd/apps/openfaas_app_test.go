@@ -4,7 +4,11 @@
 package apps
 
 import (
+	"context"
 	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
 )
 
 func Test_getValuesSuffix_arm64(t *testing.T) {
@@ -22,3 +26,66 @@ func Test_getValuesSuffix_aarch64(t *testing.T) {
 		t.Errorf("suffix, want: %s, got: %s", want, got)
 	}
 }
+
+func Test_basicAuthPassword(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantType string
+		wantErr  bool
+	}{
+		{nil, types.GeneratedSecret, false},
+		{[]string{"--basic-auth-password", "secret"}, types.StringLiteralSecret, false},
+		{[]string{"--basic-auth-password-from", "env:OF_PASSWORD"}, types.FromEnvSecret, false},
+		{[]string{"--basic-auth-password-from", "sops:password.enc"}, types.FromSOPSSecret, false},
+		{[]string{"--basic-auth-password", "secret", "--basic-auth-password-from", "stdin"}, "", true},
+	}
+
+	for _, tc := range tests {
+		command := MakeInstallOpenFaaS()
+		if err := command.ParseFlags(tc.args); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := basicAuthPassword(command)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%v: want error", tc.args)
+			}
+			continue
+		}
+		if err != nil || got.Type != tc.wantType || got.Key != "basic-auth-password" {
+			t.Errorf("%v: want %s, got %+v, %v", tc.args, tc.wantType, got, err)
+		}
+	}
+}
+
+func Test_dashboardJWTSecret_KeptOnReinstall(t *testing.T) {
+	fake := k8s.NewFakeClient()
+	k8s.SetClient(fake)
+	defer k8s.SetClient(nil)
+
+	install := func() map[string]string {
+		secret, err := dashboardJWTSecret("openfaas")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := k8s.GetClient().ApplySecret(context.Background(), secret); err != nil {
+			t.Fatal(err)
+		}
+
+		values := map[string]string{}
+		for _, data := range fake.Secrets["openfaas/dashboard-jwt"].SecretData {
+			values[data.Key] = data.Value
+		}
+		return values
+	}
+
+	first := install()
+	if len(first["key"]) == 0 || len(first["key.pub"]) == 0 {
+		t.Fatalf("want key and key.pub, got %v", first)
+	}
+
+	if second := install(); second["key"] != first["key"] || second["key.pub"] != first["key.pub"] {
+		t.Errorf("want the key pair kept on re-install")
+	}
+}
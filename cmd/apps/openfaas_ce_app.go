@@ -14,7 +14,6 @@ import (
 	"github.com/alexellis/arkade/pkg/k8s"
 
 	"github.com/alexellis/arkade/pkg"
-	"github.com/sethvargo/go-password/password"
 
 	"github.com/spf13/cobra"
 )
@@ -30,6 +29,7 @@ func MakeInstallOpenFaaSCE() *cobra.Command {
 
 	openfaasCE.Flags().BoolP("basic-auth", "a", true, "Enable authentication")
 	openfaasCE.Flags().String("basic-auth-password", "", "Overide the default random basic-auth-password if this is set")
+	openfaasCE.Flags().String("basic-auth-password-from", "", "Read the basic-auth-password from env:NAME, stdin, sops:FILE or file:FILE")
 	openfaasCE.Flags().BoolP("load-balancer", "l", false, "Add a loadbalancer")
 	openfaasCE.Flags().StringP("namespace", "n", "openfaas", "The namespace for the core services")
 	openfaasCE.Flags().Bool("update-repo", true, "Update the helm repo")
@@ -65,18 +65,13 @@ func MakeInstallOpenFaaSCE() *cobra.Command {
 		}
 
 		if basicAuthEnabled {
-			pass, _ := command.Flags().GetString("basic-auth-password")
-
-			if len(pass) == 0 {
-				var err error
-				pass, err = password.Generate(25, 10, 0, false, true)
-				if err != nil {
-					return err
-				}
+			pass, err := basicAuthPassword(command)
+			if err != nil {
+				return err
 			}
 			secretData := []types.SecretsData{
 				{Type: types.StringLiteralSecret, Key: "basic-auth-user", Value: "admin"},
-				{Type: types.StringLiteralSecret, Key: "basic-auth-password", Value: pass},
+				pass,
 			}
 
 			basicAuthSecret := types.NewGenericSecret("basic-auth", namespace, secretData)
@@ -117,7 +112,7 @@ func MakeInstallOpenFaaSCE() *cobra.Command {
 			return err
 		}
 
-		fmt.Println(openfaasCEPostInstallMsg)
+		fmt.Println(infoMessage(openfaasCEPostInstallMsg, command))
 
 		if basicAuthEnabled == false {
 			fmt.Println(
@@ -165,11 +160,16 @@ const OpenFaaSCEInfoMsg = `# OpenFaaS CE is licensed for personal use only, or a
 arkade get faas-cli
 
 # Forward the gateway to your machine
-kubectl rollout status -n openfaas deploy/gateway
-kubectl port-forward -n openfaas svc/gateway 8080:8080 &
+kubectl rollout status -n {{ namespace }} deploy/gateway
+kubectl port-forward -n {{ namespace }} svc/gateway 8080:8080 &
+{{- if and live (eq (flag "load-balancer") "true") }}
+
+# The gateway's LoadBalancer is available at:
+http://{{ loadBalancer "gateway-external" }}:8080
+{{- end }}
 
 # If basic auth is enabled, you can now log into your gateway:
-PASSWORD=$(kubectl get secret -n openfaas basic-auth -o jsonpath="{.data.basic-auth-password}" | base64 --decode; echo)
+PASSWORD={{ secret "basic-auth" "basic-auth-password" }}
 echo -n $PASSWORD | faas-cli login --username admin --password-stdin
 
 faas-cli store deploy nodeinfo
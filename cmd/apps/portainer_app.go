@@ -29,13 +29,27 @@ func MakeInstallPortainer() *cobra.Command {
 	portainer.Flags().Bool("update-repo", true, "Update the helm repo")
 
 	portainer.Flags().StringArray("set", []string{}, "Use custom flags or override existing flags \n(example --set tls.enabled=false)")
+	apps.AddIngressFlags(portainer)
 
 	portainer.RunE = func(command *cobra.Command, args []string) error {
+		ingress := apps.IngressFromFlags(command, apps.Ingress{
+			App:     "portainer",
+			Name:    "portainer",
+			Service: "portainer",
+			Port:    9000,
+		})
+
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
 		if err := config.SetKubeconfig(kubeConfigPath); err != nil {
 			return err
 		}
 
+		if ingress.Enabled() {
+			if err := apps.PreflightIngress("portainer", ingress); err != nil {
+				return err
+			}
+		}
+
 		namespace, err := command.Flags().GetString("namespace")
 		if err != nil {
 			return err
@@ -88,6 +102,12 @@ func MakeInstallPortainer() *cobra.Command {
 			return err
 		}
 
+		if ingress.Enabled() {
+			if err := apps.InstallIngress("portainer", ingress); err != nil {
+				return err
+			}
+		}
+
 		println(portainerInstallMsg)
 		return nil
 	}
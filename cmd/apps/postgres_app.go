@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/alexellis/arkade/pkg/apps"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 
 	"github.com/alexellis/arkade/pkg"
@@ -38,9 +37,6 @@ func MakeInstallPostgresql() *cobra.Command {
 		kubeConfigPath, _ := command.Flags().GetString("kubeconfig")
 		updateRepo, _ := postgresql.Flags().GetBool("update-repo")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		ns, _ := postgresql.Flags().GetString("namespace")
 
 		if ns != "default" {
@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/k8s"
 	execute "github.com/alexellis/go-execute/v2"
 	"github.com/spf13/cobra"
 )
@@ -60,7 +61,7 @@ func runQemuStaticContainer() error {
 		StreamStdio: true,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := k8s.Run(context.Background(), task)
 
 	if err != nil {
 		return err
@@ -4,12 +4,9 @@
 package apps
 
 import (
-	"fmt"
-
 	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/config"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/alexellis/arkade/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -36,9 +33,6 @@ func MakeInstallRedis() *cobra.Command {
 		updateRepo, _ := command.Flags().GetBool("update-repo")
 
 		// exit on arm
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		overrides := map[string]string{
 			"serviceAccount.create": "true",
 			"rbac.create":           "true",
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path"
 	"strconv"
 
 	"golang.org/x/crypto/bcrypt"
@@ -17,7 +16,6 @@ import (
 	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/env"
 	"github.com/alexellis/arkade/pkg/helm"
-	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/sethvargo/go-password/password"
 	"github.com/spf13/cobra"
 )
@@ -68,12 +66,7 @@ Or the Open Source Docker registry https://github.com/distribution/distribution`
 		fmt.Printf("Client: %s, %s\n", clientArch, clientOS)
 		log.Printf("User dir established as: %s\n", userPath)
 
-		os.Setenv("HELM_HOME", path.Join(userPath, ".helm"))
-
-		_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-		if err != nil {
-			return err
-		}
+		os.Setenv("HELM_HOME", config.GetHelmDir())
 
 		username, _ := command.Flags().GetString("username")
 
@@ -100,9 +93,12 @@ Or the Open Source Docker registry https://github.com/distribution/distribution`
 			return err
 		}
 
-		chartPath := path.Join(os.TempDir(), "charts")
 		err = helm.FetchChart("twuni/docker-registry", defaultVersion)
+		if err != nil {
+			return err
+		}
 
+		chartPath, err := helm.ChartsDir()
 		if err != nil {
 			return err
 		}
@@ -126,9 +122,6 @@ Or the Open Source Docker registry https://github.com/distribution/distribution`
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		fmt.Println("Chart path: ", chartPath)
 
 		ns := "default"
@@ -143,7 +136,7 @@ Or the Open Source Docker registry https://github.com/distribution/distribution`
 			return err
 		}
 
-		fmt.Println(registryInstallMsg)
+		fmt.Println(infoMessage(registryInstallMsg, command))
 
 		if len(outputFile) > 0 {
 			err := os.WriteFile(outputFile, []byte(pass), 0600)
@@ -164,11 +157,11 @@ Or the Open Source Docker registry https://github.com/distribution/distribution`
 
 const RegistryInfoMsg = `# Your docker-registry has been configured
 
-kubectl logs deploy/docker-registry
+kubectl logs -n {{ namespace }} deploy/docker-registry
 
 export IP="192.168.0.11" # Set to WiFI/ethernet adapter
 export PASSWORD="" # See below
-kubectl port-forward svc/docker-registry --address 0.0.0.0 5000 &
+kubectl port-forward -n {{ namespace }} svc/docker-registry --address 0.0.0.0 5000 &
 
 docker login $IP:5000 --username admin --password $PASSWORD
 docker tag alpine:3.11 $IP:5000/alpine:3.11
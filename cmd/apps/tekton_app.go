@@ -29,9 +29,6 @@ func MakeInstallTekton() *cobra.Command {
 			return err
 		}
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		fmt.Println("Installing Tekton pipelines...")
 		_, err := k8s.KubectlTask("apply", "-f",
 			"https://storage.googleapis.com/tekton-releases/pipeline/latest/release.yaml")
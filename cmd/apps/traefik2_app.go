@@ -39,20 +39,14 @@ func MakeInstallTraefik2() *cobra.Command {
 
 		updateRepo, _ := traefik2.Flags().GetBool("update-repo")
 		namespace, _ := traefik2.Flags().GetString("namespace")
-		userPath, err := config.InitUserDir()
-		if err != nil {
+		if _, err := config.InitUserDir(); err != nil {
 			return err
 		}
 
-		clientArch, clientOS := env.GetClientArch()
+		_, clientOS := env.GetClientArch()
 		fmt.Printf("Client: %q\n", clientOS)
 
-		_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-		if err != nil {
-			return err
-		}
-
-		err = helm.AddHelmRepo("traefik", "https://traefik.github.io/charts", updateRepo)
+		err := helm.AddHelmRepo("traefik", "https://traefik.github.io/charts", updateRepo)
 		if err != nil {
 			return fmt.Errorf("Unable to add repo %s", err)
 		}
@@ -10,8 +10,6 @@ import (
 	"github.com/alexellis/arkade/pkg/apps"
 	"github.com/alexellis/arkade/pkg/types"
 
-	"github.com/alexellis/arkade/pkg/k8s"
-
 	"github.com/alexellis/arkade/pkg"
 	"github.com/spf13/cobra"
 )
@@ -32,9 +30,6 @@ func MakeInstallWaypoint() *cobra.Command {
 	waypoint.RunE = func(command *cobra.Command, args []string) error {
 		updateRepo, _ := waypoint.Flags().GetBool("update-repo")
 
-		arch := k8s.GetNodeArchitecture()
-		fmt.Printf("Node architecture: %q\n", arch)
-
 		namespace, _ := waypoint.Flags().GetString("namespace")
 
 		overrides := map[string]string{}
@@ -15,6 +15,7 @@ import (
 
 const (
 	versionKey        = "version"
+	appVersionKey     = "appVersion"
 	ChartYamlFileName = "Chart.yaml"
 	ChartYmlFileName  = "Chart.yml"
 )
@@ -27,12 +28,18 @@ func MakeBump() *cobra.Command {
 if there are changes staged in git.
 
 Add --force to bump the version even if there are not changes staged in git.
+
+Add --app-version to set the appVersion at the same time. Only the version
+and appVersion are changed, comments and formatting in Chart.yaml are kept.
 `,
 		Example: `  # Bump if there are changes
   arkade chart bump -f ./chart/values.yaml
 
   # Force a bump, even if there are no changes 
-  arkade chart bump -f ./charts/values.yaml --check-for-updates`,
+  arkade chart bump -f ./charts/values.yaml --check-for-updates
+
+  # Bump the version and set the appVersion
+  arkade chart bump -f ./chart/values.yaml --app-version 0.27.0 --write`,
 		SilenceUsage: true,
 	}
 
@@ -40,6 +47,7 @@ Add --force to bump the version even if there are not changes staged in git.
 	command.Flags().BoolP("verbose", "v", false, "Verbose output")
 	command.Flags().BoolP("write", "w", false, "Write the updated values back to the file, or stdout when set to false")
 	command.Flags().Bool("force", false, "Update the version even if there are no changes staged in the adjacent folder to Chart.yaml")
+	command.Flags().String("app-version", "", "Set the appVersion in Chart.yaml along with the version")
 
 	command.RunE = func(cmd *cobra.Command, args []string) error {
 		valuesFile, err := cmd.Flags().GetString("file")
@@ -62,6 +70,10 @@ Add --force to bump the version even if there are not changes staged in git.
 		if err != nil {
 			return err
 		}
+		appVersion, err := cmd.Flags().GetString("app-version")
+		if err != nil {
+			return err
+		}
 
 		chartDir := filepath.Dir(valuesFile)
 		chartYamlPath := filepath.Join(chartDir, ChartYamlFileName)
@@ -123,16 +135,27 @@ Add --force to bump the version even if there are not changes staged in git.
 
 			fmt.Printf("%s %s => %s\n", chartYamlPath, ver.String(), newVer.String())
 
-			if write {
+			update := map[string]string{
+				versionKey: newVer.String(),
+			}
+			if len(appVersion) > 0 {
+				if _, ok := values[appVersionKey]; !ok {
+					return fmt.Errorf("unable to find an %s in %s", appVersionKey, chartYamlPath)
+				}
+				fmt.Printf("%s %s: %v => %s\n", chartYamlPath, appVersionKey, values[appVersionKey], appVersion)
+				update[appVersionKey] = appVersion
+			}
 
-				update := map[string]string{
-					fmt.Sprintf("%s: %s", versionKey, ver.String()): fmt.Sprintf("%s: %s", versionKey, newVer.String()),
+			if write {
+				chartYaml, err := os.ReadFile(chartYamlPath)
+				if err != nil {
+					return err
 				}
-				rawChartYaml, err := helm.ReplaceValuesInHelmValuesFile(update, chartYamlPath)
+				rawChartYaml, err := helm.SetValues(chartYaml, update)
 				if err != nil {
-					return fmt.Errorf("unable to bump chart version in %s", chartYamlPath)
+					return fmt.Errorf("unable to bump chart version in %s: %w", chartYamlPath, err)
 				}
-				if err = os.WriteFile(chartYamlPath, []byte(rawChartYaml), 0600); err != nil {
+				if err = os.WriteFile(chartYamlPath, rawChartYaml, 0600); err != nil {
 					return fmt.Errorf("unable to write updated yaml to %s", chartYamlPath)
 				}
 				fmt.Printf("Wrote to: %s. OK.\n", chartYamlPath)
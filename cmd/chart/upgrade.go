@@ -22,6 +22,17 @@ func MakeUpgrade() *cobra.Command {
 Container images must be specified at the top level, or one level down in the 
 "image: " or "component.image: " field in a values.yaml file.
 
+Images may also be split into a map, as used by Bitnami and most upstream
+charts, in which case only the tag is updated. The registry is optional and
+defaults to global.imageRegistry:
+
+image:
+  registry: docker.io
+  repository: bitnami/redis
+  tag: 7.2.4
+
+Images pinned with a "digest" field are not upgraded.
+
 Returns exit code zero if all images were found on the remote registry.
 
 Otherwise, it returns a non-zero exit code and the updated values.yaml file.
@@ -114,13 +125,28 @@ ignore:
 			log.Printf("Verifying images in: %s\n", file)
 		}
 
-		values, err := helm.Load(file)
+		data, err := os.ReadFile(file)
 		if err != nil {
 			return err
 		}
 
-		filtered := helm.FilterImagesUptoDepth(values, depth, "", arkadeCfg)
-		if len(filtered) == 0 {
+		found, err := helm.FindImagesUptoDepth(data, depth, arkadeCfg)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", file, err)
+		}
+
+		// Each image is looked up once, however often it's used
+		filtered := map[string]string{}
+		for _, image := range found {
+			if len(image.Digest) > 0 {
+				if verbose {
+					log.Printf("Skipping %s, pinned to %s", image.Path, image.Digest)
+				}
+				continue
+			}
+			filtered[image.Ref] = image.Ref
+		}
+		if len(found) == 0 {
 			return fmt.Errorf("no images found in %s", file)
 		}
 
@@ -136,6 +162,7 @@ ignore:
 		workChan := make(chan string, len(filtered))
 		errChan := make(chan error, len(filtered))
 		updatedImages := make(map[string]string)
+		var mu sync.Mutex
 
 		for i := 0; i < workers; i++ {
 			go func() {
@@ -150,7 +177,9 @@ ignore:
 							continue
 						}
 						if updated {
+							mu.Lock()
 							updatedImages[image] = imageNameAndTag
+							mu.Unlock()
 						}
 					}
 				}
@@ -175,13 +204,30 @@ ignore:
 			return joinedErrors
 		}
 
-		rawValues, err := helm.ReplaceValuesInHelmValuesFile(updatedImages, file)
+		// Each image is set at its own path, so the same image elsewhere in
+		// the file, i.e. in a comment, is left alone. Images given as a map
+		// only have their tag set.
+		updates := map[string]string{}
+		for _, image := range found {
+			updated, ok := updatedImages[image.Ref]
+			if !ok || len(image.Digest) > 0 {
+				continue
+			}
+			if image.Structured() {
+				_, tag := images.SplitImageName(updated)
+				updates[image.TagPath] = tag
+			} else {
+				updates[image.Path] = updated
+			}
+		}
+
+		rawValues, err := helm.SetValues(data, updates)
 		if err != nil {
-			return err
+			return fmt.Errorf("unable to update images in %s: %w", file, err)
 		}
 
 		if len(updatedImages) > 0 && writeFile {
-			if err := os.WriteFile(file, []byte(rawValues), 0600); err != nil {
+			if err := os.WriteFile(file, rawValues, 0600); err != nil {
 				return err
 			}
 			log.Printf("Wrote %d updates to: %s", len(updatedImages), file)
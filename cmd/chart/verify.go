@@ -8,6 +8,7 @@ import (
 
 	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/helm"
+	"github.com/alexellis/arkade/pkg/images"
 	"github.com/spf13/cobra"
 
 	"github.com/google/go-containerregistry/pkg/crane"
@@ -19,7 +20,9 @@ func MakeVerify() *cobra.Command {
 		Short: "Verify images from a values.yaml file exist on the remote registry",
 		Long: `Verify images in a values.yaml file exist within a remote registry.
 Container images must be specified at the top level, or one level down in the 
-"image: " or "component.image: " field in a values.yaml file.
+"image: " or "component.image: " field in a values.yaml file, either as a
+string, or as a map with a "repository", "tag" and optional "registry", which
+defaults to global.imageRegistry.
 
 Returns exit code zero if all images were found on the remote registry.
 
@@ -68,12 +71,15 @@ autoscaler          ghcr.io/openfaasltd/autoscaler:0.2.5
 			fmt.Printf("Verifying images in: %s\n", file)
 		}
 
-		values, err := helm.Load(file)
+		data, err := os.ReadFile(file)
 		if err != nil {
 			return err
 		}
 		cfg := &config.ArkadeConfig{}
-		filtered := helm.FilterImagesUptoDepth(values, depth, "", cfg)
+		filtered, err := helm.FindImagesUptoDepth(data, depth, cfg)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", file, err)
+		}
 		if len(filtered) == 0 {
 			return fmt.Errorf("no images found in %s", file)
 		}
@@ -84,9 +90,14 @@ autoscaler          ghcr.io/openfaasltd/autoscaler:0.2.5
 				fmt.Printf("Found %d images\n", len(filtered))
 			}
 		}
-		for k, v := range filtered {
+		for _, image := range filtered {
+			v := image.Ref
+			if len(image.Digest) > 0 {
+				v, _ = images.SplitImageName(image.Ref)
+				v += "@" + image.Digest
+			}
 			if verbose {
-				fmt.Printf("> [%s] %s\n", k, v)
+				fmt.Printf("> [%s] %s\n", image.Path, v)
 			}
 
 			ref, err := crane.Head(v)
@@ -94,7 +105,7 @@ autoscaler          ghcr.io/openfaasltd/autoscaler:0.2.5
 				missed = append(missed, verifyError{
 					Err:       err,
 					Image:     v,
-					Component: k,
+					Component: image.Path,
 				})
 			} else {
 				if verbose {
@@ -0,0 +1,249 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	execute "github.com/alexellis/go-execute/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+// fanOutFlags select the contexts, and are replaced by a single
+// --context for each install.
+var fanOutFlags = map[string]bool{
+	"context":      true,
+	"all-contexts": true,
+	"selector":     true,
+}
+
+// kubeContexts returns the contexts given with --context, or every
+// context in the kubeconfig matching --selector with --all-contexts.
+func kubeContexts(cmd *cobra.Command) ([]string, error) {
+	contexts, _ := cmd.Flags().GetStringSlice("context")
+	all, _ := cmd.Flags().GetBool("all-contexts")
+	selector, _ := cmd.Flags().GetString("selector")
+
+	if len(selector) > 0 && !all {
+		return nil, fmt.Errorf("--selector can only be used with --all-contexts")
+	}
+	if !all {
+		return contexts, nil
+	}
+	if len(contexts) > 0 {
+		return nil, fmt.Errorf("give either --context or --all-contexts")
+	}
+
+	args := []string{"config", "get-contexts", "--output=name"}
+	if kubeconfig, _ := cmd.Flags().GetString("kubeconfig"); len(kubeconfig) > 0 {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+	res, err := k8s.KubectlTask(args...)
+	if err != nil {
+		return nil, err
+	}
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("unable to list contexts: %s", strings.TrimSpace(res.Stderr))
+	}
+
+	var selected []string
+	for _, name := range strings.Fields(res.Stdout) {
+		if len(selector) > 0 {
+			if ok, err := path.Match(selector, name); err != nil {
+				return nil, fmt.Errorf("invalid --selector %q: %w", selector, err)
+			} else if !ok {
+				continue
+			}
+		}
+		selected = append(selected, name)
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no contexts found matching --selector %q", selector)
+	}
+	return selected, nil
+}
+
+// fanOutArgs rebuilds the command-line of cmd to run it again for a
+// single context. A dry-run --output directory gets a folder for each
+// context.
+func fanOutArgs(cmd, install *cobra.Command, args []string, kubeContext string) []string {
+	var names []string
+	for c := cmd; c != nil && c != install; c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+
+	out := append([]string{"install"}, names...)
+	out = append(out, args...)
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if fanOutFlags[f.Name] {
+			return
+		}
+
+		values := []string{f.Value.String()}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			values = s.GetSlice()
+		}
+		for _, value := range values {
+			if f.Name == "output" {
+				value = filepath.Join(value, kubeContext)
+			}
+			out = append(out, fmt.Sprintf("--%s=%s", f.Name, value))
+		}
+	})
+
+	return append(out, "--context="+kubeContext)
+}
+
+// contextResult is the outcome of an install to one context.
+type contextResult struct {
+	Context  string
+	Duration time.Duration
+	Err      error
+}
+
+// runArkade runs arkade with args, and is replaced in tests. It is not
+// run through the k8s.Executor, as each install has its own.
+var runArkade = func(ctx context.Context, args []string, out io.Writer) error {
+	arkade, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	task := execute.ExecTask{
+		Command:            arkade,
+		Args:               args,
+		StdOutWriter:       out,
+		StdErrWriter:       out,
+		DisableStdioBuffer: true,
+	}
+	res, err := task.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("exit code %d", res.ExitCode)
+	}
+	return nil
+}
+
+// installContexts runs the install once for each context, in parallel,
+// with each line of output prefixed by its context. A summary is
+// printed at the end.
+func installContexts(cmd, install *cobra.Command, args, contexts []string, out io.Writer) error {
+	var outMu sync.Mutex
+	results := make([]contextResult, len(contexts))
+
+	// The flag set isn't safe to visit from several goroutines, so each
+	// command-line is built up-front
+	contextArgs := make([][]string, len(contexts))
+	for i, kubeContext := range contexts {
+		contextArgs[i] = fanOutArgs(cmd, install, args, kubeContext)
+	}
+
+	var wg sync.WaitGroup
+	for i, kubeContext := range contexts {
+		wg.Add(1)
+		go func(i int, kubeContext string, args []string) {
+			defer wg.Done()
+
+			w := &prefixWriter{prefix: "[" + kubeContext + "] ", out: out, outMu: &outMu}
+			start := time.Now()
+			err := runArkade(cmd.Context(), args, w)
+			w.Flush()
+
+			results[i] = contextResult{Context: kubeContext, Duration: time.Since(start), Err: err}
+		}(i, kubeContext, contextArgs[i])
+	}
+	wg.Wait()
+
+	failed := printContextResults(out, results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d context(s) failed", failed, len(contexts))
+	}
+	return nil
+}
+
+func printContextResults(out io.Writer, results []contextResult) int {
+	failed := 0
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tSTATUS\tDURATION\tERROR")
+	for _, r := range results {
+		status, msg := "ok", ""
+		if r.Err != nil {
+			failed++
+			status, msg = "failed", r.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Context, status, r.Duration.Round(time.Second), msg)
+	}
+	w.Flush()
+
+	return failed
+}
+
+// prefixWriter writes each complete line with a prefix, so that the
+// output of installs running at the same time can be told apart.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	outMu  *sync.Mutex
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any output left without a trailing newline.
+func (w *prefixWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.writeLine(append(w.buf, '\n'))
+	w.buf = nil
+	return err
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.outMu.Lock()
+	defer w.outMu.Unlock()
+
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
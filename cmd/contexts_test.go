@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func fakeRunArkade(t *testing.T, run func(args []string, out io.Writer) error) {
+	t.Helper()
+
+	original := runArkade
+	runArkade = func(ctx context.Context, args []string, out io.Writer) error {
+		return run(args, out)
+	}
+	t.Cleanup(func() { runArkade = original })
+}
+
+func Test_Install_FansOutToContexts(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string][]string{}
+	fakeRunArkade(t, func(args []string, out io.Writer) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got[args[len(args)-1]] = args
+		return nil
+	})
+
+	install := MakeInstall()
+	install.SetArgs([]string{"openfaas", "--gateways=2", "--set", "a=b", "--set", "c=d",
+		"--dry-run", "--output", "plan", "--context", "edge-1,edge-2"})
+	install.SetOut(io.Discard)
+	if err := install.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kubeContext := range []string{"edge-1", "edge-2"} {
+		args := got["--context="+kubeContext]
+		if len(args) < 2 || args[0] != "install" || args[1] != "openfaas" {
+			t.Fatalf("want install openfaas for %s, got %v", kubeContext, args)
+		}
+
+		flags := strings.Join(args[2:], " ")
+		for _, want := range []string{"--gateways=2", "--set=a=b", "--set=c=d", "--dry-run=true", "--output=plan/" + kubeContext} {
+			if !strings.Contains(flags, want) {
+				t.Errorf("want %s for %s, got %v", want, kubeContext, args)
+			}
+		}
+		if strings.Count(flags, "--context") != 1 {
+			t.Errorf("want a single --context for %s, got %v", kubeContext, args)
+		}
+	}
+}
+
+func Test_installContexts_PrefixesOutputAndSummarises(t *testing.T) {
+	fakeRunArkade(t, func(args []string, out io.Writer) error {
+		kubeContext := strings.TrimPrefix(args[len(args)-1], "--context=")
+		fmt.Fprintf(out, "installing\nto %s", kubeContext)
+		if kubeContext == "edge-2" {
+			return errors.New("exit code 1")
+		}
+		return nil
+	})
+
+	cmd := &cobra.Command{Use: "openfaas"}
+	out := &bytes.Buffer{}
+	err := installContexts(cmd, nil, nil, []string{"edge-1", "edge-2"}, out)
+	if err == nil || err.Error() != "1 of 2 context(s) failed" {
+		t.Errorf("want 1 of 2 failed, got %v", err)
+	}
+
+	for _, want := range []string{"[edge-1] installing\n", "[edge-1] to edge-1\n", "[edge-2] to edge-2\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("want %q in output:\n%s", want, out.String())
+		}
+	}
+
+	var summary [][]string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && (fields[1] == "ok" || fields[1] == "failed") {
+			summary = append(summary, fields[:2])
+		}
+	}
+	want := [][]string{{"edge-1", "ok"}, {"edge-2", "failed"}}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("want summary %v, got %v in:\n%s", want, summary, out.String())
+	}
+}
+
+func Test_kubeContexts_SelectorNeedsAllContexts(t *testing.T) {
+	install := MakeInstall()
+	install.SetArgs([]string{"openfaas", "--selector", "edge-*"})
+	install.SetOut(io.Discard)
+	install.SetErr(io.Discard)
+
+	err := install.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--all-contexts") {
+		t.Errorf("want error for --selector without --all-contexts, got %v", err)
+	}
+}
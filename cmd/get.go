@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -22,6 +23,7 @@ import (
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/env"
 	"github.com/alexellis/arkade/pkg/get"
 )
@@ -94,7 +96,7 @@ and provides a fast and easy alternative to a package manager.`,
 
 	command.Flags().Bool("progress", true, "Display a progress bar")
 	command.Flags().StringP("format", "o", "", "Format format of the list of tools (table/markdown/list)")
-	command.Flags().String("path", "", "Leave empty to store in HOME/.arkade/bin/, otherwise give a path for the resulting binaries")
+	command.Flags().String("path", "", "Leave empty to store in arkade's bin directory (HOME/.arkade/bin/ by default), otherwise give a path for the resulting binaries")
 	command.Flags().StringP("version", "v", "", "Download a specific version")
 	command.Flags().String("arch", clientArch, "CPU architecture for the tool")
 	command.Flags().String("os", clientOS, "Operating system for the tool")
@@ -145,8 +147,10 @@ and provides a fast and easy alternative to a package manager.`,
 
 		movePath = os.ExpandEnv(movePath)
 
-		signalChan := make(chan os.Signal, 1)
-		signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+		// Cancelling the context on a signal aborts in-flight requests
+		// and retries, and removes partially downloaded files.
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
 
 		arch, _ := command.Flags().GetString("arch")
 		if err := get.ValidateArch(arch); err != nil {
@@ -188,7 +192,17 @@ and provides a fast and easy alternative to a package manager.`,
 				// Phase 1: resolve version.
 				events <- downloadEvent{toolIndex: idx, resolving: true}
 
-				resolved, err := get.ResolveVersion(&tool, version)
+				client := get.NewClient(get.Options{
+					Quiet:      true, // the renderer owns the display
+					Verify:     verify,
+					InstallDir: movePath,
+					Progress: func(bytesRead, totalBytes int64) {
+						atomic.StoreInt64(&progress[idx].bytesRead, bytesRead)
+						atomic.StoreInt64(&progress[idx].totalBytes, totalBytes)
+					},
+				})
+
+				resolved, err := client.ResolveVersion(ctx, &tool, version)
 				if err != nil {
 					events <- downloadEvent{toolIndex: idx, err: err}
 					continue
@@ -197,19 +211,7 @@ and provides a fast and easy alternative to a package manager.`,
 				// Phase 2: download.
 				events <- downloadEvent{toolIndex: idx, started: true, version: resolved}
 
-				cb := func(bytesRead, totalBytes int64) {
-					atomic.StoreInt64(&progress[idx].bytesRead, bytesRead)
-					atomic.StoreInt64(&progress[idx].totalBytes, totalBytes)
-				}
-
-				toolPath, _, dlErr := get.DownloadWithProgress(&tool,
-					arch,
-					operatingSystem,
-					resolved,
-					movePath,
-					true, // quiet — the renderer owns the display
-					verify,
-					cb)
+				toolPath, _, dlErr := client.Download(ctx, &tool, arch, operatingSystem, resolved)
 				events <- downloadEvent{toolIndex: idx, path: toolPath, err: dlErr}
 			}
 		}
@@ -258,13 +260,6 @@ and provides a fast and easy alternative to a package manager.`,
 			}
 		}
 
-		// Restore terminal on signal.
-		go func() {
-			<-signalChan
-			leaveAltScreen()
-			os.Exit(2)
-		}()
-
 		finished := 0
 		var firstErr error
 		var firstErrTool *get.Tool
@@ -381,7 +376,7 @@ and provides a fast and easy alternative to a package manager.`,
 				// Warn about conflicting binaries found elsewhere in $PATH.
 				if movePath == "" {
 					homeDir, _ := os.UserHomeDir()
-					arkadeBin := filepath.Clean(filepath.Join(homeDir, ".arkade", "bin"))
+					arkadeBin := filepath.Clean(config.GetPaths().Bin)
 
 					pathDirs := filepath.SplitList(os.Getenv("PATH"))
 
@@ -5,21 +5,33 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
 )
 
 func MakeInfo() *cobra.Command {
 
 	info := &cobra.Command{
-		Use:     "info",
-		Short:   "Find info about a Kubernetes app",
-		Long:    "Find info about how to use the installed Kubernetes app",
+		Use:   "info",
+		Short: "Find info about a Kubernetes app",
+		Long: `Find info about how to use the installed Kubernetes app.
+
+With --live, values such as passwords and LoadBalancer addresses are
+read from the cluster, using the flags recorded when the app was
+installed, instead of printing the commands to look them up.`,
 		Aliases: []string{"f"},
 		Example: `  arkade info [APP]
 arkade info openfaas
 arkade info inlets-operator
 arkade info mongodb
+arkade info openfaas --live
 arkade info
 arkade info --help`,
 		SilenceUsage: true,
@@ -33,6 +45,9 @@ arkade info --help`,
 		},
 	}
 
+	info.Flags().Bool("live", false, "Read passwords and addresses from the cluster")
+	info.Flags().String("kubeconfig", "", "Local path for your kubeconfig file")
+
 	info.RunE = func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			fmt.Println("Run arkade info APP_NAME for more")
@@ -45,14 +60,55 @@ arkade info --help`,
 
 		appList := GetApps()
 		appName := args[0]
-		if _, ok := appList[appName]; !ok {
+		app, ok := appList[appName]
+		if !ok {
 			return fmt.Errorf("no info available for app: %s", appName)
 		}
+
+		live, _ := cmd.Flags().GetBool("live")
+		data := pkgapps.InfoData{
+			Flags: defaultFlags(app),
+			Live:  live,
+		}
+
+		if live {
+			kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+			if err := config.SetKubeconfig(kubeconfig); err != nil {
+				return err
+			}
+
+			// The record is optional, the defaults are used without one
+			record, err := state.NewStore().Get(k8s.CurrentCluster(kubeconfig), appName)
+			if err == nil {
+				for name, values := range record.Flags {
+					data.Flags[name] = strings.Join(values, ",")
+				}
+			}
+		}
+
+		message, err := pkgapps.RenderInfo(app.InfoMessage, data)
+		if err != nil {
+			return err
+		}
+
 		fmt.Printf("Info for app: %s\n", appName)
-		fmt.Println(appList[appName].InfoMessage)
+		fmt.Println(message)
 		return nil
 
 	}
 	return info
 
 }
+
+// defaultFlags returns the default value of each of an app's flags.
+func defaultFlags(app ArkadeApp) map[string]string {
+	flags := map[string]string{}
+	if app.Installer == nil {
+		return flags
+	}
+
+	app.Installer().Flags().VisitAll(func(f *pflag.Flag) {
+		flags[f.Name] = f.DefValue
+	})
+	return flags
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+)
+
+func Test_InfoMessagesRender(t *testing.T) {
+	for name, app := range GetApps() {
+		out, err := pkgapps.RenderInfo(app.InfoMessage, pkgapps.InfoData{Flags: defaultFlags(app)})
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if strings.Contains(out, "<no value>") {
+			t.Errorf("%s: info message has an unset value:\n%s", name, out)
+		}
+	}
+}
+
+func Test_InfoMessage_OpenFaaSDefaults(t *testing.T) {
+	app := GetApps()["openfaas"]
+
+	out, err := pkgapps.RenderInfo(app.InfoMessage, pkgapps.InfoData{Flags: defaultFlags(app)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `PASSWORD=$(kubectl get secret -n openfaas basic-auth -o jsonpath="{.data.basic-auth-password}" | base64 --decode; echo)`
+	if !strings.Contains(out, want) {
+		t.Errorf("want %q in:\n%s", want, out)
+	}
+}
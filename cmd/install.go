@@ -10,16 +10,27 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/renderer"
 	"github.com/olekukonko/tablewriter/tw"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/alexellis/arkade/cmd/apps"
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/get"
+	"github.com/alexellis/arkade/pkg/helm"
+	"github.com/alexellis/arkade/pkg/k8s"
 )
 
+// exportStdout is where --export writes YAML, whilst os.Stdout is sent
+// to stderr during the install.
+var exportStdout = os.Stdout
+
 type ArkadeApp struct {
 	Name        string
 	Installer   func() *cobra.Command
@@ -39,13 +50,136 @@ You can also find the post-install message for each app with the "info"
 command.`,
 		Example: `  arkade install
   arkade install openfaas  --gateways=2
-  arkade install inlets-operator --token-file $HOME/do-token`,
+  arkade install openfaas  --gateways=2 --dry-run
+  arkade install openfaas  --output ./openfaas-plan
+  arkade install openfaas  --export flux > openfaas.yaml
+  arkade install openfaas  --mirror registry.example.com:5000
+  arkade install openfaas  --context edge-1,edge-2
+  arkade install -f stack.yaml --all-contexts --selector 'edge-*'
+  arkade install inlets-operator --token-file $HOME/do-token
+  arkade install -f stack.yaml`,
 		SilenceUsage: true,
 	}
 
 	command.PersistentFlags().String("kubeconfig", "", "Local path for your kubeconfig file")
-	command.PersistentFlags().Bool("wait", false, "If we should wait for the resource to be ready before returning (helm3 only, default false)")
+	command.PersistentFlags().Bool("wait", false, "Wait for the app's Deployments, StatefulSets, DaemonSets and Jobs to be ready before returning")
+	command.PersistentFlags().Duration("wait-timeout", 5*time.Minute, "How long to wait for the app to be ready with --wait")
 	command.Flags().Bool("print-table", false, "print a table in markdown format")
+	command.Flags().StringP("file", "f", "", "Install the apps listed in a stack file")
+	command.PersistentFlags().String("chart-version", "", "Install this version of the app's helm chart, instead of the default")
+	command.PersistentFlags().Bool("skip-preflight", false, "Install even when the cluster does not meet the app's requirements")
+	command.PersistentFlags().StringArray("patch", []string{}, "Patch the app's helm chart with this kustomize patch file, can be given more than once")
+	command.PersistentFlags().String("kustomize", "", "Post-render the app's helm chart with the kustomization.yaml in this directory")
+	command.PersistentFlags().String("mirror", "", "Copy the images of the app's helm chart to this registry, and install them from it")
+	command.PersistentFlags().Bool("mirror-insecure", false, "Allow plain HTTP for the --mirror registry")
+	command.PersistentFlags().StringSlice("context", []string{}, "Install into these kubeconfig contexts, in parallel when more than one is given")
+	command.PersistentFlags().Bool("all-contexts", false, "Install into every context in the kubeconfig, in parallel")
+	command.PersistentFlags().String("selector", "", "With --all-contexts, only install into contexts matching this pattern, i.e. \"edge-*\"")
+	pkgapps.AddDryRunFlags(command)
+
+	var (
+		dryRun   *k8s.DryRunExecutor
+		export   *pkgapps.ExportExecutor
+		recorder *k8s.RecordingExecutor
+		rollout  *k8s.RolloutExecutor
+		globals  installGlobals
+		fanOut   bool
+	)
+
+	command.PersistentPreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		contexts, err := kubeContexts(cmd)
+		if err != nil {
+			return err
+		}
+		file, _ := cmd.Flags().GetString("file")
+		if len(contexts) > 1 && cmd.Name() != "info" && (cmd != command || len(file) > 0) {
+			if format, _ := cmd.Flags().GetString("export"); len(format) > 0 {
+				return fmt.Errorf("--export can't be used with more than one context")
+			}
+
+			// Run an install for each context in place of this one
+			fanOut = true
+			cmd.RunE = func(cmd *cobra.Command, args []string) error {
+				return installContexts(cmd, command, args, contexts, os.Stdout)
+			}
+			return nil
+		}
+
+		// Put back what the install changes once it's done, or has failed,
+		// for the next app in a stack file
+		globals = saveInstallGlobals()
+		defer func() {
+			if err != nil {
+				globals.restore()
+				return
+			}
+			restoreOnFailure(cmd, globals)
+		}()
+
+		if len(contexts) == 1 {
+			config.SetKubeContext(contexts[0])
+		}
+
+		pkgapps.SkipPreflight, _ = cmd.Flags().GetBool("skip-preflight")
+		chartVersion, _ := cmd.Flags().GetString("chart-version")
+		helm.SetChartVersion(chartVersion)
+		if err := setPostRender(cmd); err != nil {
+			return err
+		}
+
+		if export, err = pkgapps.SetupExport(cmd); err != nil {
+			return err
+		}
+		if export != nil {
+			// Keep stdout for the exported YAML, so it can be piped to a file
+			os.Stdout = os.Stderr
+			return nil
+		}
+
+		if dryRun, err = pkgapps.SetupDryRun(cmd, os.Stdout); err != nil {
+			return err
+		}
+
+		// Record what each app creates for "arkade list" and "arkade uninstall",
+		// unless an executor such as "arkade upgrade --diff" renders it instead
+		if dryRun == nil && !k8s.IsDryRun() && cmd.Parent() == command && cmd.Name() != "info" {
+			kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+			recorder = pkgapps.StartRecording(cmd.Name(), kubeconfig)
+			pkgapps.SaveOnFailure(recorder, cmd)
+
+			// Track the namespaces each app applies to, to wait for them
+			if wait, _ := cmd.Flags().GetBool("wait"); wait {
+				rollout = k8s.NewRolloutExecutor(k8s.GetExecutor())
+				k8s.SetExecutor(rollout)
+			}
+		}
+		return nil
+	}
+
+	command.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		if fanOut {
+			return nil
+		}
+		defer globals.restore()
+
+		if export != nil {
+			outputDir, _ := cmd.Flags().GetString("output")
+			return pkgapps.WriteExport(export, cmd.Name(), outputDir, exportStdout)
+		}
+		if dryRun != nil {
+			fmt.Printf("[dry-run] %d step(s) rendered, no changes were made to the cluster\n", dryRun.Steps())
+		}
+		if recorder != nil {
+			if err := pkgapps.SaveRecording(recorder, cmd); err != nil {
+				return err
+			}
+		}
+		if rollout != nil {
+			timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+			return k8s.WaitForRollouts(cmd.Context(), rollout.Namespaces(), timeout, os.Stdout)
+		}
+		return nil
+	}
 
 	command.RunE = func(command *cobra.Command, args []string) error {
 
@@ -56,6 +190,10 @@ command.`,
 			return nil
 		}
 
+		if file, _ := command.Flags().GetString("file"); len(file) > 0 {
+			return installStack(command, file)
+		}
+
 		if len(args) == 0 {
 			fmt.Printf(
 				`You can install %d apps to your Kubernetes cluster.
@@ -95,6 +233,181 @@ To request a new app, raise a GitHub issue at:
 	return command
 }
 
+// setPostRender sets up --patch, --kustomize and --mirror for helm. The paths are
+// made absolute in the flags too, so they are recorded for "arkade
+// upgrade" no matter where it is run from.
+func setPostRender(cmd *cobra.Command) error {
+	patches, _ := cmd.Flags().GetStringArray("patch")
+	kustomize, _ := cmd.Flags().GetString("kustomize")
+	mirror, _ := cmd.Flags().GetString("mirror")
+	mirrorInsecure, _ := cmd.Flags().GetBool("mirror-insecure")
+
+	p, err := helm.PostRender{Patches: patches, Kustomize: kustomize, Mirror: mirror, MirrorInsecure: mirrorInsecure}.Abs()
+	if err != nil {
+		return err
+	}
+
+	if f := cmd.Flags().Lookup("patch"); f != nil && f.Changed {
+		if err := f.Value.(pflag.SliceValue).Replace(p.Patches); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("kustomize") {
+		if err := cmd.Flags().Set("kustomize", p.Kustomize); err != nil {
+			return err
+		}
+	}
+
+	return helm.SetPostRender(p)
+}
+
+// installGlobals holds the package-level settings which an install
+// changes for the app it runs.
+type installGlobals struct {
+	stdout        *os.File
+	executor      k8s.Executor
+	skipPreflight bool
+	kubeContext   string
+	chartVersion  string
+	postRender    helm.PostRender
+}
+
+func saveInstallGlobals() installGlobals {
+	return installGlobals{
+		stdout:        os.Stdout,
+		executor:      k8s.GetExecutor(),
+		skipPreflight: pkgapps.SkipPreflight,
+		kubeContext:   config.KubeContext(),
+		chartVersion:  helm.ChartVersion(),
+		postRender:    helm.GetPostRender(),
+	}
+}
+
+func (g installGlobals) restore() {
+	os.Stdout = g.stdout
+	k8s.SetExecutor(g.executor)
+	pkgapps.SkipPreflight = g.skipPreflight
+	config.SetKubeContext(g.kubeContext)
+	helm.SetChartVersion(g.chartVersion)
+
+	// The saved PostRender was already checked by SetPostRender
+	if err := helm.SetPostRender(g.postRender); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+	}
+}
+
+// restoreOnFailure restores the globals when the app's install fails,
+// as PersistentPostRunE is only run after a successful install.
+func restoreOnFailure(command *cobra.Command, globals installGlobals) {
+	run := command.RunE
+	if run == nil {
+		return
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		err := run(cmd, args)
+		if err != nil {
+			globals.restore()
+		}
+		return err
+	}
+}
+
+// installStack installs each app in a stack file, in stages so that
+// dependencies are installed and ready first. Each app is run through
+// a new install command, exactly as if typed on the command-line.
+func installStack(command *cobra.Command, file string) error {
+	stack, err := pkgapps.LoadStack(file)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range stack.Apps {
+		if found, _, err := command.Find([]string{app.AppName()}); err != nil || found == command {
+			return fmt.Errorf("%s in %s: %s", app.Name, file, checkForTool(app.AppName(), get.MakeTools()))
+		}
+	}
+
+	stages, err := stack.Stages()
+	if err != nil {
+		return err
+	}
+
+	forwarded, err := forwardedFlags(command)
+	if err != nil {
+		return err
+	}
+
+	for i, stage := range stages {
+		for _, app := range stage {
+			// The stack file decides whether dependencies are waited for
+			args := append(app.Args(), forwarded...)
+			if stack.ShouldWait(app) {
+				args = append(args, "--wait")
+			}
+
+			fmt.Printf("[stage %d/%d] arkade install %s\n", i+1, len(stages), strings.Join(k8s.RedactArgs(args), " "))
+
+			install := MakeInstall()
+			install.SetArgs(args)
+			install.SilenceErrors = true
+
+			if err := install.Execute(); err != nil {
+				return fmt.Errorf("unable to install %s: %w", app.Name, err)
+			}
+		}
+	}
+
+	fmt.Printf("Installed %d app(s) from %s\n", len(stack.Apps), file)
+	return nil
+}
+
+// stackFlags are passed on from "arkade install -f" to every app in the
+// stack. The contexts were already split up by installContexts.
+var stackFlags = map[string]bool{
+	"kubeconfig":      true,
+	"wait":            true,
+	"wait-timeout":    true,
+	"dry-run":         true,
+	"output":          true,
+	"export":          true,
+	"skip-preflight":  true,
+	"mirror":          true,
+	"mirror-insecure": true,
+	"context":         true,
+}
+
+// forwardedFlags returns the flags set on "arkade install -f" to pass on
+// to each app, and fails for any which can't be, rather than ignoring
+// them.
+func forwardedFlags(command *cobra.Command) ([]string, error) {
+	var forwarded []string
+	var err error
+
+	command.Flags().Visit(func(f *pflag.Flag) {
+		switch {
+		case err != nil, f.Name == "file", f.Name == "all-contexts", f.Name == "selector":
+			return
+		case f.Name == "patch", f.Name == "kustomize":
+			err = fmt.Errorf("--%s can't be used with --file, as it would patch every app in the stack", f.Name)
+			return
+		case !stackFlags[f.Name]:
+			err = fmt.Errorf("--%s can't be used with --file, set it for each app in the stack instead", f.Name)
+			return
+		}
+
+		values := []string{f.Value.String()}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			values = s.GetSlice()
+		}
+		for _, value := range values {
+			forwarded = append(forwarded, fmt.Sprintf("--%s=%s", f.Name, value))
+		}
+	})
+
+	return forwarded, err
+}
+
 func GetApps() map[string]ArkadeApp {
 	arkadeApps := map[string]ArkadeApp{}
 	arkadeApps["argocd"] = NewArkadeApp(apps.MakeInstallArgoCD, apps.ArgoCDInfoMsg)
@@ -119,14 +432,12 @@ func GetApps() map[string]ArkadeApp {
 	arkadeApps["kafka-connector"] = NewArkadeApp(apps.MakeInstallKafkaConnector, apps.KafkaConnectorInfoMsg)
 	arkadeApps["kong-ingress"] = NewArkadeApp(apps.MakeInstallKongIngress, apps.KongIngressInfoMsg)
 	arkadeApps["kube-image-prefetch"] = NewArkadeApp(apps.MakeInstallKubeImagePrefetch, apps.KubeImagePrefetchInfoMsg)
-	arkadeApps["kube-state-metrics"] = NewArkadeApp(apps.MakeInstallKubeStateMetrics, apps.KubeStateMetricsInfoMsg)
 	arkadeApps["kubernetes-dashboard"] = NewArkadeApp(apps.MakeInstallKubernetesDashboard, apps.KubernetesDashboardInfoMsg)
 	arkadeApps["kuma"] = NewArkadeApp(apps.MakeInstallKuma, apps.KumaInfoMsg)
 	arkadeApps["kyverno"] = NewArkadeApp(apps.MakeInstallKyverno, apps.KyvernoInfoMsg)
 	arkadeApps["linkerd"] = NewArkadeApp(apps.MakeInstallLinkerd, apps.LinkerdInfoMsg)
 	arkadeApps["loki"] = NewArkadeApp(apps.MakeInstallLoki, apps.LokiInfoMsg)
 	arkadeApps["metallb-arp"] = NewArkadeApp(apps.MakeInstallMetalLB, apps.MetalLBInfoMsg)
-	arkadeApps["metrics-server"] = NewArkadeApp(apps.MakeInstallMetricsServer, apps.MetricsInfoMsg)
 	arkadeApps["minio"] = NewArkadeApp(apps.MakeInstallMinio, apps.MinioInfoMsg)
 	arkadeApps["mongodb"] = NewArkadeApp(apps.MakeInstallMongoDB, apps.MongoDBInfoMsg)
 	arkadeApps["mqtt-connector"] = NewArkadeApp(apps.MakeInstallMQTTConnector, apps.MQTTConnectorInfoMsg)
@@ -152,9 +463,49 @@ func GetApps() map[string]ArkadeApp {
 
 	// Special "chart" app - let a user deploy any helm chart
 	arkadeApps["chart"] = NewArkadeApp(apps.MakeInstallChart, "")
+
+	// Apps defined in YAML, built-in and from the user's apps directory
+	if err := addDefinitions(arkadeApps, config.GetPaths().Apps); err != nil {
+		warnDefinitions.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		})
+	}
+
 	return arkadeApps
 }
 
+// warnDefinitions prints invalid app definitions once, as the apps are
+// listed each time an install command is created.
+var warnDefinitions sync.Once
+
+// addDefinitions adds the apps defined in YAML, which are built into
+// arkade or placed in dir by the user. A user's definition can't
+// replace an existing app.
+func addDefinitions(arkadeApps map[string]ArkadeApp, dir string) error {
+	builtin, err := apps.Definitions()
+	if err != nil {
+		return err
+	}
+	for _, d := range builtin {
+		arkadeApps[d.Name] = NewArkadeApp(apps.MakeInstallDefinition(d), d.Info)
+	}
+
+	user, err := pkgapps.LoadDefinitions(os.DirFS(dir))
+	var errs []error
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", dir, err))
+	}
+	for _, d := range user {
+		if _, ok := arkadeApps[d.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s: %s is already an arkade app, give it another name", dir, d.Name))
+			continue
+		}
+		arkadeApps[d.Name] = NewArkadeApp(apps.MakeInstallDefinition(d), d.Info)
+	}
+
+	return errors.Join(errs...)
+}
+
 func NewArkadeApp(cmd func() *cobra.Command, msg string) ArkadeApp {
 	return ArkadeApp{
 		Installer:   cmd,
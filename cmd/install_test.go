@@ -5,10 +5,18 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/alexellis/arkade/cmd/apps"
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/get"
+	"github.com/alexellis/arkade/pkg/helm"
+	"github.com/alexellis/arkade/pkg/k8s"
 	"github.com/spf13/cobra"
 )
 
@@ -171,3 +179,113 @@ func TestFindAppByName(t *testing.T) {
 		})
 	}
 }
+
+func Test_addDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"podinfo.yaml":  "name: podinfo\nrepo:\n  name: podinfo\n  url: https://stefanprodan.github.io/podinfo\nchart: podinfo\ninfo: See {{ namespace }}\n",
+		"openfaas.yaml": "name: openfaas\nchart: oci://example.com/openfaas\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	arkadeApps := map[string]ArkadeApp{"openfaas": NewArkadeApp(apps.MakeInstallOpenFaaS, apps.OpenFaaSInfoMsg)}
+	err := addDefinitions(arkadeApps, dir)
+	if err == nil || !strings.Contains(err.Error(), "openfaas is already an arkade app") {
+		t.Errorf("want error for replacing openfaas, got %v", err)
+	}
+
+	for _, name := range []string{"podinfo", "kube-state-metrics", "metrics-server"} {
+		if _, ok := arkadeApps[name]; !ok {
+			t.Errorf("want app %s", name)
+		}
+	}
+	if arkadeApps["podinfo"].InfoMessage != "See {{ namespace }}" {
+		t.Errorf("unexpected info message %q", arkadeApps["podinfo"].InfoMessage)
+	}
+	if got := arkadeApps["openfaas"].Installer().Name(); got != "openfaas" {
+		t.Errorf("want the built-in openfaas installer, got %s", got)
+	}
+}
+
+func Test_forwardedFlags(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{
+			args: []string{"-f", "stack.yaml", "--wait", "--wait-timeout=10m", "--kubeconfig=/tmp/config"},
+			want: []string{"--kubeconfig=/tmp/config", "--wait=true", "--wait-timeout=10m0s"},
+		},
+		{
+			args: []string{"-f", "stack.yaml", "--context=edge-1", "--dry-run"},
+			want: []string{"--context=edge-1", "--dry-run=true"},
+		},
+		{
+			args:    []string{"-f", "stack.yaml", "--chart-version=1.0.0"},
+			wantErr: "--chart-version can't be used with --file",
+		},
+		{
+			args:    []string{"-f", "stack.yaml", "--patch=patch.yaml"},
+			wantErr: "as it would patch every app",
+		},
+	}
+
+	for _, tc := range tests {
+		command := MakeInstall()
+		if err := command.ParseFlags(tc.args); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := forwardedFlags(command)
+		if len(tc.wantErr) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%v: want error %q, got %v", tc.args, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("%v: want %v, got %v", tc.args, tc.want, got)
+		}
+	}
+}
+
+func Test_restoreOnFailure(t *testing.T) {
+	globals := saveInstallGlobals()
+	dryRun, err := k8s.NewDryRunExecutor(&bytes.Buffer{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	command := &cobra.Command{
+		Use: "openfaas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			os.Stdout = os.Stderr
+			k8s.SetExecutor(dryRun)
+			pkgapps.SkipPreflight = true
+			config.SetKubeContext("edge-1")
+			helm.SetChartVersion("14.0.0")
+			return errors.New("helm upgrade failed")
+		},
+	}
+	restoreOnFailure(command, globals)
+	command.SetArgs([]string{})
+	command.SilenceErrors = true
+	command.SilenceUsage = true
+
+	if err := command.Execute(); err == nil {
+		t.Fatal("want the install's error")
+	}
+
+	got := saveInstallGlobals()
+	if got.stdout != globals.stdout || got.executor != globals.executor || got.skipPreflight != globals.skipPreflight ||
+		got.kubeContext != globals.kubeContext || got.chartVersion != globals.chartVersion {
+		t.Errorf("want the globals restored to %+v, got %+v", globals, got)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+)
+
+func MakeList() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "list",
+		Short: "List apps installed with arkade",
+		Long: `List the apps installed with "arkade install" to the current cluster,
+along with the helm releases and namespaces they created.`,
+		Example: `  arkade list
+  arkade list --kubeconfig ./kubeconfig`,
+		Aliases:      []string{"ls"},
+		SilenceUsage: true,
+	}
+
+	command.Flags().String("kubeconfig", "", "Local path for your kubeconfig file")
+
+	command.RunE = func(command *cobra.Command, args []string) error {
+		kubeconfig, _ := command.Flags().GetString("kubeconfig")
+
+		cluster := k8s.CurrentCluster(kubeconfig)
+		records, err := state.NewStore().List(cluster)
+		if err != nil {
+			return err
+		}
+
+		if len(records) == 0 {
+			fmt.Printf("No apps have been installed with arkade to context %q\n", cluster.Context)
+			return nil
+		}
+
+		printRecords(os.Stdout, records)
+		return nil
+	}
+
+	return command
+}
+
+func printRecords(out io.Writer, records []state.Record) {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "APP\tRELEASES\tNAMESPACES\tINSTALLED")
+
+	for _, r := range records {
+		var releases []string
+		for _, rel := range r.Releases {
+			releases = append(releases, rel.Namespace+"/"+rel.Name)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			r.App,
+			orNone(strings.Join(releases, ",")),
+			orNone(strings.Join(r.Namespaces, ",")),
+			r.InstalledAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	w.Flush()
+}
+
+func orNone(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
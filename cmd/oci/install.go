@@ -116,10 +116,6 @@ OCI image.`,
 
 		if cmd.Flags().Changed("os") {
 			clientOS, _ = cmd.Flags().GetString("os")
-		} else {
-			if strings.Contains(clientOS, "microsoft windows") {
-				clientOS = "windows"
-			}
 		}
 
 		tempFile, err := os.CreateTemp(os.TempDir(), "arkade-oci-*")
@@ -135,13 +131,13 @@ OCI image.`,
 		}
 		defer f.Close()
 
-		downloadArch, downloadOS := getDownloadArch(clientArch, clientOS)
-		platform := &v1.Platform{Architecture: downloadArch, OS: downloadOS}
+		target := env.NewPlatform(clientOS, clientArch)
+		platform := ociPlatform(target)
 
 		// ── progress state ───────────────────────────────────
 		p := &imageProgress{
 			imageName: imageName,
-			platform:  fmt.Sprintf("%s/%s", downloadOS, downloadArch),
+			platform:  target.String(),
 			status:    stResolving,
 			started:   time.Now(),
 		}
@@ -339,15 +335,12 @@ func buildPullOptions(platform *v1.Platform, forceAnonymousAuth bool) []crane.Op
 	return options
 }
 
-func getDownloadArch(clientArch, clientOS string) (arch string, os string) {
-	downloadArch := strings.ToLower(clientArch)
-	downloadOS := strings.ToLower(clientOS)
-
-	if downloadArch == "x86_64" {
-		downloadArch = "amd64"
-	} else if downloadArch == "aarch64" {
-		downloadArch = "arm64"
+// ociPlatform converts a canonical platform into the form used to select
+// a manifest from an image index.
+func ociPlatform(p env.Platform) *v1.Platform {
+	return &v1.Platform{
+		Architecture: p.Arch,
+		OS:           p.OS,
+		Variant:      p.Variant,
 	}
-
-	return downloadArch, downloadOS
 }
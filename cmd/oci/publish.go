@@ -202,13 +202,10 @@ func buildImageFromDir(dir string) (v1.Image, func(), error) {
 		return nil, nil, err
 	}
 	cfg = cfg.DeepCopy()
-	arch, osName := env.GetClientArch()
-	downloadArch, downloadOS := getDownloadArch(arch, osName)
-	if downloadOS == "microsoft windows" {
-		downloadOS = "windows"
-	}
-	cfg.OS = downloadOS
-	cfg.Architecture = downloadArch
+	platform := env.GetClientPlatform()
+	cfg.OS = platform.OS
+	cfg.Architecture = platform.Arch
+	cfg.Variant = platform.Variant
 	img, err = mutate.ConfigFile(img, cfg)
 	if err != nil {
 		cleanup()
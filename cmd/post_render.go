@@ -0,0 +1,39 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/alexellis/arkade/pkg/helm"
+)
+
+// MakePostRender is run by helm as a post-renderer, when an app is
+// installed with --patch, --kustomize or --mirror. It reads the rendered
+// manifests from stdin and writes the patched manifests to stdout.
+func MakePostRender() *cobra.Command {
+	var command = &cobra.Command{
+		Use:          helm.PostRenderCommand,
+		Short:        "Patch the manifests rendered by helm, and mirror their images",
+		Hidden:       true,
+		SilenceUsage: true,
+	}
+
+	command.Flags().StringArray("patch", []string{}, "Patch file to apply")
+	command.Flags().String("kustomize", "", "Directory with a kustomization.yaml")
+	command.Flags().String("mirror", "", "Registry to mirror images to")
+	command.Flags().Bool("mirror-insecure", false, "Allow plain HTTP for the mirror registry")
+
+	command.RunE = func(command *cobra.Command, args []string) error {
+		patches, _ := command.Flags().GetStringArray("patch")
+		kustomize, _ := command.Flags().GetString("kustomize")
+		mirror, _ := command.Flags().GetString("mirror")
+		mirrorInsecure, _ := command.Flags().GetBool("mirror-insecure")
+
+		p := helm.PostRender{Patches: patches, Kustomize: kustomize, Mirror: mirror, MirrorInsecure: mirrorInsecure}
+		return helm.RenderManifests(command.Context(), p, command.InOrStdin(), command.OutOrStdout(), command.ErrOrStderr())
+	}
+
+	return command
+}
@@ -105,13 +105,9 @@ func MakeInstallActionsRunner() *cobra.Command {
 			return fmt.Errorf("unsupported operating system: %q, use linux or darwin (macOS)", osVer)
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
+		dlArch := env.NewPlatform(osVer, arch).Arch
+		if dlArch == "amd64" {
 			dlArch = "x64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		} else if arch == "armv7" || arch == "armv7l" {
-			dlArch = "arm"
 		}
 
 		fmt.Fprintf(out, "Installing version: %s for: %s / %s\n", version, dlOS, dlArch)
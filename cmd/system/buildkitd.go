@@ -55,14 +55,7 @@ func MakeInstallBuildkitd() *cobra.Command {
 			arch, _ = cmd.Flags().GetString("arch")
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
-			dlArch = "amd64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		} else if arch == "armv7" || arch == "armv7l" {
-			dlArch = "arm"
-		}
+		dlArch := env.NewPlatform(osVer, arch).Arch
 
 		if version == "" {
 			v, err := get.FindGitHubRelease("moby", "buildkit")
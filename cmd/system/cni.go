@@ -59,14 +59,7 @@ func MakeInstallCNI() *cobra.Command {
 			return fmt.Errorf("this app only supports Linux")
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
-			dlArch = "amd64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		} else if arch == "armv7" || arch == "armv7l" {
-			dlArch = "arm"
-		}
+		dlArch := env.NewPlatform(osVer, arch).Arch
 		if version == githubLatest {
 			v, err := get.FindGitHubRelease(owner, repo)
 			if err != nil {
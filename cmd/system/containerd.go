@@ -74,30 +74,17 @@ func MakeInstallContainerd() *cobra.Command {
 			version = latestVerison
 		}
 
-		downloadArch := ""
-
-		if arch == "x86_64" {
-			downloadArch = "amd64"
-		} else if arch == "aarch64" {
-			downloadArch = "arm64"
-		} else {
+		downloadArch := env.NewPlatform(osVer, arch).Arch
+		if downloadArch != "amd64" && downloadArch != "arm64" {
 			return fmt.Errorf("this app currently only supports arm64 and amd64 archs")
 		}
 
 		containerdTool := get.Tool{
-			Name:    "containerd",
-			Repo:    "containerd",
-			Owner:   "containerd",
-			Version: version,
-			BinaryTemplate: `
-			{{$archStr := .Arch}}
-			{{- if eq .Arch "aarch64" -}}
-			{{$archStr = "arm64"}}
-			{{- else if eq .Arch "x86_64" -}}
-			{{$archStr = "amd64"}}
-			{{- end -}}
-			{{.Name}}-{{.VersionNumber}}-{{.OS}}-{{$archStr}}.tar.gz
-			`,
+			Name:           "containerd",
+			Repo:           "containerd",
+			Owner:          "containerd",
+			Version:        version,
+			BinaryTemplate: `{{.Name}}-{{.VersionNumber}}-{{.GoOS}}-{{.GoArch}}.tar.gz`,
 		}
 
 		url, _, err := containerdTool.GetURL(osVer, downloadArch, containerdTool.Version, !progress)
@@ -49,9 +49,12 @@ func MakeInstallFirecracker() *cobra.Command {
 			return fmt.Errorf("this app only supports Linux")
 		}
 
-		if arch != "x86_64" && arch != "aarch64" {
+		// Firecracker's release assets use the uname names
+		platform := env.NewPlatform(osVer, arch)
+		if platform.Arch != "amd64" && platform.Arch != "arm64" {
 			return fmt.Errorf("this app only supports x86_64 and aarch64 and not %s", arch)
 		}
+		arch = platform.UnameArch()
 
 		if version == githubLatest {
 			v, err := get.FindGitHubRelease(owner, repo)
@@ -63,14 +63,7 @@ func MakeInstallGitLabRunner() *cobra.Command {
 			return fmt.Errorf("unsupported operating system: %q, use linux or darwin (macOS)", osVer)
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
-			dlArch = "amd64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		} else if arch == "armv7" || arch == "armv7l" {
-			dlArch = "arm"
-		}
+		dlArch := env.NewPlatform(osVer, arch).Arch
 
 		if version == "" {
 			version = "latest"
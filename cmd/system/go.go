@@ -53,12 +53,10 @@ func MakeInstallGo() *cobra.Command {
 			return fmt.Errorf("this app only supports Linux")
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
-			dlArch = "amd64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		} else if arch == "armv7" || arch == "armv7l" {
+		// Go only publishes a single 32-bit ARM build, named armv6l
+		platform := env.NewPlatform(osVer, arch)
+		dlArch := platform.Arch
+		if platform.Arch == "arm" {
 			dlArch = "armv6l"
 		}
 
@@ -95,11 +95,13 @@ func MakeInstallNode() *cobra.Command {
 			arch, _ = cmd.Flags().GetString("arch")
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
+		platform := env.NewPlatform(osVer, arch)
+		dlArch := platform.Arch
+		switch platform.Arch {
+		case "amd64":
 			dlArch = "x64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
+		case "arm":
+			dlArch = "armv7l"
 		}
 
 		if (version == "latest" || strings.Contains(version, "latest-")) && channel == "release" {
@@ -48,12 +48,11 @@ func MakeInstallPowershell() *cobra.Command {
 			arch, _ = cmd.Flags().GetString("arch")
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
+		dlArch := env.NewPlatform(osVer, arch).Arch
+		switch dlArch {
+		case "amd64":
 			dlArch = "x64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		} else if arch == "armv7" || arch == "armv7l" {
+		case "arm":
 			dlArch = "arm32"
 		}
 
@@ -78,12 +78,7 @@ func MakeInstallPrometheus() *cobra.Command {
 			return fmt.Errorf("unsupported operating system: %q, use linux or darwin (macOS)", osVer)
 		}
 
-		dlArch := arch
-		if arch == "x86_64" {
-			dlArch = "amd64"
-		} else if arch == "aarch64" {
-			dlArch = "arm64"
-		}
+		dlArch := env.NewPlatform(osVer, arch).Arch
 
 		fmt.Printf("Installing version: %s for: %s / %s\n", version, dlOS, dlArch)
 
@@ -82,34 +82,17 @@ func MakeInstallRegistry() *cobra.Command {
 			version = latestVerison
 		}
 
-		downloadArch := ""
-
-		if arch == "x86_64" {
-			downloadArch = "amd64"
-		} else if arch == "aarch64" {
-			downloadArch = "arm64"
-		} else {
+		downloadArch := env.NewPlatform(osVer, arch).Arch
+		if downloadArch != "amd64" && downloadArch != "arm64" {
 			return fmt.Errorf("this app currently only supports arm64 and amd64 archs")
 		}
 
 		containerdTool := get.Tool{
-			Name:    toolName,
-			Repo:    "distribution",
-			Owner:   "distribution",
-			Version: version,
-			BinaryTemplate: `
-			{{$archStr := .Arch}}
-			{{- if or (eq .Arch "aarch64") (eq .Arch "arm64") -}}
-			{{$archStr = "arm64"}}
-			{{- else if eq .Arch "armv7l" -}}
-			{{$arch = "armv7"}}
-			{{- else if eq .Arch "armv6l" -}}
-			{{$arch = "armv6"}}
-			{{- else if eq .Arch "x86_64" -}}
-			{{$archStr = "amd64"}}
-			{{- end -}}
-			{{.Name}}_{{.VersionNumber}}_{{.OS}}_{{$archStr}}.tar.gz
-			`,
+			Name:           toolName,
+			Repo:           "distribution",
+			Owner:          "distribution",
+			Version:        version,
+			BinaryTemplate: `{{.Name}}_{{.VersionNumber}}_{{.GoOS}}_{{.GoArch}}{{.Variant}}.tar.gz`,
 		}
 
 		url, _, err := containerdTool.GetURL(osVer, downloadArch, containerdTool.Version, !progress)
@@ -44,14 +44,12 @@ func MakeInstallTCRedirectTap() *cobra.Command {
 			return fmt.Errorf("this app only supports Linux")
 		}
 
-		if arch != "x86_64" && arch != "aarch64" {
+		platform := env.NewPlatform(osVer, arch)
+		if platform.Arch != "amd64" && platform.Arch != "arm64" {
 			return fmt.Errorf("this app only supports x86_64 and aarch64 and not %s", arch)
 		}
 
-		dlArch := arch
-		if arch == "aarch64" {
-			dlArch = "arm64"
-		}
+		dlArch := platform.Arch
 
 		if version == githubLatest {
 			v, err := get.FindGitHubRelease(owner, repo)
@@ -65,7 +63,7 @@ func MakeInstallTCRedirectTap() *cobra.Command {
 		fmt.Printf("Installing version: %s for: %s\n", version, dlArch)
 
 		filename := fmt.Sprintf("tc-redirect-tap-%s", dlArch)
-		if dlArch == "x86_64" {
+		if dlArch == "amd64" {
 			filename = "tc-redirect-tap"
 		}
 		dlURL := fmt.Sprintf(githubDownloadTemplate, owner, repo, version, filename)
@@ -66,18 +66,11 @@ func runInstallZvolSnapshotter(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("this app only supports Linux")
 	}
 
-	if arch != "x86_64" && arch != "aarch64" {
+	dlArch := env.NewPlatform(osVer, arch).Arch
+	if dlArch != "amd64" && dlArch != "arm64" {
 		return fmt.Errorf("this app only supports x86_64 and aarch64 and not %s", arch)
 	}
 
-	dlArch := arch
-	if arch == "x86_64" {
-		dlArch = "amd64"
-	}
-	if arch == "aarch64" {
-		dlArch = "arm64"
-	}
-
 	if version == githubLatest {
 		v, err := get.FindGitHubRelease(owner, repo)
 		if err != nil {
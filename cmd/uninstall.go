@@ -4,45 +4,114 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
 )
 
 func MakeUninstall() *cobra.Command {
 	var command = &cobra.Command{
-		Use:          "uninstall",
-		Short:        "Uninstall apps installed with arkade",
-		Long:         `Uninstall apps installed with arkade`,
-		Example:      `  arkade uninstall`,
+		Use:   "uninstall APP",
+		Short: "Uninstall apps installed with arkade",
+		Long: `Uninstall an app using the record kept by "arkade install" of the helm
+releases, manifests, secrets and namespaces it created.
+
+Run "arkade list" to see which apps have been recorded for the current
+cluster.`,
+		Example: `  arkade uninstall openfaas
+  arkade uninstall openfaas --keep-namespaces
+  arkade uninstall openfaas --dry-run`,
 		Aliases:      []string{"delete"},
-		SilenceUsage: false,
+		SilenceUsage: true,
 	}
 
-	command.PersistentFlags().String("kubeconfig", "kubeconfig", "Local path for your kubeconfig file")
-	command.PersistentFlags().Bool("wait", false, "If we should wait for the resource to be ready before returning (helm3 only, default false)")
+	command.PersistentFlags().String("kubeconfig", "", "Local path for your kubeconfig file")
+	command.PersistentFlags().Bool("wait", false, "If we should wait for helm releases to be removed before returning")
+	command.Flags().Bool("keep-namespaces", false, "Keep any namespaces created by the app")
+	pkgapps.AddDryRunFlags(command)
 
 	command.RunE = func(command *cobra.Command, args []string) error {
 
 		if len(args) == 0 {
-			fmt.Printf(
-				`Apps installed to Kubernetes can rarely be uninstalled in a single command 
-and often leave clusters in an inconsistent state. Kubernetes does not 
-track resources created by applications in the same way that something like 
-Windows 10 or MacOS would do, so  it is often much easier for you to 
-create a new cluster, than to remove an application.
+			fmt.Print(uninstallHelp)
+			return nil
+		}
+
+		kubeconfig, _ := command.Flags().GetString("kubeconfig")
+		keepNamespaces, _ := command.Flags().GetBool("keep-namespaces")
+		wait, _ := command.Flags().GetBool("wait")
+
+		if err := config.SetKubeconfig(kubeconfig); err != nil {
+			return err
+		}
+
+		dryRun, err := pkgapps.SetupDryRun(command, os.Stdout)
+		if err != nil {
+			return err
+		}
+		defer k8s.SetExecutor(nil)
+
+		store := state.NewStore()
+		cluster := k8s.CurrentCluster(kubeconfig)
+
+		for _, app := range args {
+			record, err := store.Get(cluster, app)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return fmt.Errorf("%s has no install record for context %q, run \"arkade list\" to see recorded apps", app, cluster.Context)
+				}
+				return err
+			}
+
+			if err := pkgapps.Uninstall(record, pkgapps.UninstallOptions{
+				KeepNamespaces: keepNamespaces,
+				Wait:           wait,
+			}); err != nil {
+				return err
+			}
+
+			if dryRun != nil {
+				continue
+			}
+
+			if err := store.Delete(cluster, app); err != nil {
+				return err
+			}
+			fmt.Printf("%s has been uninstalled.\n", app)
+		}
 
-With a tool like kind, you just run:
+		if dryRun != nil {
+			fmt.Printf("[dry-run] %d step(s) rendered, no changes were made to the cluster\n", dryRun.Steps())
+		}
 
-kind delete cluster; kind create cluster
+		return nil
+	}
 
-Most arkade apps are installed with helm, so you can simply use helm to 
-remove them, but beware that each project generally provides very specific 
-guidance on how to clear up all resources that it may have created 
-at installation time.
+	return command
+}
 
-Get helm:
-arkade get helm
+const uninstallHelp = `Apps installed with arkade are recorded along with the helm releases,
+manifests, secrets and namespaces they created, so that they can be
+removed with:
+
+  arkade uninstall APP
+
+Run "arkade list" to see which apps have been recorded for the current
+cluster. A chart installed with "arkade install chart" is recorded as
+chart/RELEASE, i.e. "arkade uninstall chart/openfaas".
+
+Apps installed before arkade kept records, or by an upstream CLI like
+istioctl, may need to be removed by hand. Most arkade apps are installed
+with helm, so you can use helm to remove them, but beware that each
+project generally provides very specific guidance on how to clear up all
+resources that it may have created at installation time.
 
 List charts:
 helm list --all-namespaces
@@ -53,22 +122,7 @@ helm delete -n openfaas openfaas
 Delete any namespaces it created:
 kubectl delete namespace openfaas openfaas-fn
 
-Where an app was installed via manifest files or an upstream CLI like 
-with linkerd or OSM, then you can usually delete its namespace, 
-however this does not always remove all resources such as a 
-ClusterRole. See the app's code for more:
+See the app's code for more:
 
 https://github.com/alexellis/arkade/tree/master/cmd/apps
-
-You can seek out technical support from the OpenFaaS community 
-at https://slack.openfaas.io/
-
-`)
-			return nil
-		}
-
-		return nil
-	}
-
-	return command
-}
+`
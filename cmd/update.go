@@ -6,7 +6,6 @@ package cmd
 import (
 	"fmt"
 	"runtime"
-	"strings"
 
 	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/env"
@@ -74,13 +73,7 @@ func (u *urlResolver) GetRelease() (string, error) {
 }
 
 func (u *urlResolver) GetDownloadURL(release string) (string, error) {
-	arch, operatingSystem := env.GetClientArch()
-	arch = strings.ToLower(arch)
-	operatingSystem = strings.ToLower(operatingSystem)
-
-	if arch == "x86_64" {
-		arch = "amd64"
-	}
+	platform := env.GetClientPlatform()
 
 	name := "arkade"
 	toolList := get.MakeTools()
@@ -92,7 +85,7 @@ func (u *urlResolver) GetDownloadURL(release string) (string, error) {
 		}
 	}
 
-	downloadUrl, _, err := get.GetDownloadURL(tool, operatingSystem, arch, release, false)
+	downloadUrl, _, err := get.GetDownloadURL(tool, platform.OS, platform.UnameArch(), release, false)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,203 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	pkgapps "github.com/alexellis/arkade/pkg/apps"
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+)
+
+func MakeUpgrade() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "upgrade APP [-- FLAGS]",
+		Short: "Upgrade an app with the flags it was installed with",
+		Long: `Upgrade an app installed with arkade, by running the install again with
+the flags recorded when it was installed, merged with any new flags.
+
+Values for --set are merged by key, and any other flags for the app can
+be changed after "--". The changes to the flags are shown before the
+upgrade starts.
+
+With --diff, nothing is upgraded, and the values each helm release would
+be upgraded with are rendered by helm and compared with those in the
+cluster, including any new defaults from --chart-version.`,
+		Example: `  arkade upgrade openfaas
+  arkade upgrade openfaas --chart-version 14.2.0
+  arkade upgrade openfaas --set gateway.replicas=3 -- --queue-workers=2
+  arkade upgrade openfaas --chart-version 14.2.0 --diff`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+	}
+
+	command.Flags().String("kubeconfig", "", "Local path for your kubeconfig file")
+	command.Flags().String("chart-version", "", "Upgrade to this version of the app's helm chart")
+	command.Flags().StringArray("set", []string{}, "Set or replace a helm value (example --set key=value)")
+	command.Flags().Bool("wait", false, "Wait for the upgrade to be ready before returning")
+	command.Flags().Bool("diff", false, "Only show the changes to the flags and values, without upgrading")
+	command.Flags().Bool("dry-run", false, "Print the helm and kubectl commands instead of changing the cluster")
+
+	command.RunE = func(command *cobra.Command, args []string) error {
+		app := args[0]
+
+		appFlags := args[1:]
+		if dash := command.ArgsLenAtDash(); dash > -1 {
+			if dash != 1 {
+				return fmt.Errorf("give one app to upgrade before \"--\"")
+			}
+		} else if len(appFlags) > 0 {
+			return fmt.Errorf("give one app to upgrade, and its flags after \"--\"")
+		}
+
+		changes, err := parseAppFlags(appFlags)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{"chart-version", "set", "wait"} {
+			if f := command.Flags().Lookup(name); f.Changed {
+				if name == "set" {
+					values, _ := command.Flags().GetStringArray(name)
+					changes[name] = append(changes[name], values...)
+				} else {
+					changes[name] = []string{f.Value.String()}
+				}
+			}
+		}
+
+		kubeconfig, _ := command.Flags().GetString("kubeconfig")
+		cluster := k8s.CurrentCluster(kubeconfig)
+
+		record, err := state.NewStore().Get(cluster, app)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s has no install record for context %q, run \"arkade list\" to see recorded apps", app, cluster.Context)
+			}
+			return err
+		}
+
+		flags := pkgapps.UpgradeFlags(record.Flags, changes)
+
+		changed := pkgapps.FlagsDiff(record.Flags, flags)
+		if len(changed) == 0 {
+			fmt.Printf("No changes to the flags for %s, it will be upgraded with the latest defaults.\n", app)
+		} else {
+			fmt.Printf("Changes to the flags for %s:\n", app)
+			for _, line := range changed {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+
+		if err := pkgapps.FillRedacted(flags, promptSecret); err != nil {
+			return err
+		}
+
+		installArgs := pkgapps.UpgradeArgs(record, flags)
+		if len(kubeconfig) > 0 {
+			installArgs = append(installArgs, "--kubeconfig="+kubeconfig)
+		}
+
+		if diffOnly, _ := command.Flags().GetBool("diff"); diffOnly {
+			return diffValues(app, installArgs)
+		}
+		if dryRun, _ := command.Flags().GetBool("dry-run"); dryRun {
+			installArgs = append(installArgs, "--dry-run")
+		}
+
+		fmt.Printf("Upgrading %s\n", app)
+
+		install := MakeInstall()
+		install.SetArgs(installArgs)
+		install.SilenceErrors = true
+
+		if err := install.Execute(); err != nil {
+			return fmt.Errorf("unable to upgrade %s: %w", app, err)
+		}
+		return nil
+	}
+
+	return command
+}
+
+// promptSecret asks for a helm value which wasn't recorded as it looks
+// like a secret.
+func promptSecret(key string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%s was not recorded as it looks like a secret, give it again with --set %s=VALUE", key, key)
+	}
+
+	fmt.Printf("%s was not recorded as it looks like a secret, enter its value: ", key)
+	value, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// diffValues runs the install with a ValuesDiffExecutor, and prints how
+// the values of each of the app's helm releases would change.
+func diffValues(app string, installArgs []string) error {
+	executor := pkgapps.NewValuesDiffExecutor()
+	k8s.SetExecutor(executor)
+	defer k8s.SetExecutor(nil)
+
+	fmt.Printf("Rendering the values for %s\n", app)
+
+	install := MakeInstall()
+	install.SetArgs(installArgs)
+	install.SilenceErrors = true
+
+	if err := install.Execute(); err != nil {
+		return fmt.Errorf("unable to render the values for %s: %w", app, err)
+	}
+
+	for _, diff := range executor.Diffs() {
+		if len(diff.Lines) == 0 {
+			fmt.Printf("No changes to the values of %s/%s.\n", diff.Namespace, diff.Release)
+			continue
+		}
+		fmt.Printf("Changes to the values of %s/%s:\n", diff.Namespace, diff.Release)
+		for _, line := range diff.Lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// parseAppFlags reads flags given after "--" as "--name=value", or
+// "--name" for a boolean. Repeated flags keep each value.
+func parseAppFlags(args []string) (map[string][]string, error) {
+	flags := map[string][]string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			return nil, fmt.Errorf("unexpected argument %q, give flags as --name=value", arg)
+		}
+
+		name, value, ok := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !ok {
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				value = args[i+1]
+				i++
+			} else {
+				value = "true"
+			}
+		}
+
+		flags[name] = append(flags[name], value)
+	}
+
+	return flags, nil
+}
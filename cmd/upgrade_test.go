@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseAppFlags(t *testing.T) {
+	got, err := parseAppFlags([]string{"--gateways=3", "--load-balancer", "--set", "a=b", "--set=c=d", "--namespace", "faas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"gateways":      {"3"},
+		"load-balancer": {"true"},
+		"set":           {"a=b", "c=d"},
+		"namespace":     {"faas"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if _, err := parseAppFlags([]string{"openfaas"}); err == nil {
+		t.Errorf("want error for a positional argument")
+	}
+}
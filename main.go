@@ -15,6 +15,7 @@ import (
 	"github.com/alexellis/arkade/cmd/oci"
 	"github.com/alexellis/arkade/cmd/system"
 	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/helm"
 	"github.com/spf13/cobra"
 )
 
@@ -42,6 +43,8 @@ func main() {
 	rootCmd.AddCommand(cmd.MakeUpdate())
 	rootCmd.AddCommand(cmd.MakeGet())
 	rootCmd.AddCommand(cmd.MakeUninstall())
+	rootCmd.AddCommand(cmd.MakeList())
+	rootCmd.AddCommand(cmd.MakeUpgrade())
 	rootCmd.AddCommand(cmd.MakeShellCompletion())
 
 	rootCmd.AddCommand(cmd.MakeRelease())
@@ -53,8 +56,12 @@ func main() {
 	rootCmd.AddCommand(system.MakeSystem())
 	rootCmd.AddCommand(oci.MakeOci())
 	rootCmd.AddCommand(cmd.MakeSearch())
+	rootCmd.AddCommand(cmd.MakePostRender())
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	helm.CleanCharts()
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -20,6 +20,14 @@ func MakeInstallChart(options *types.InstallerOptions) (*types.InstallerOutput,
 		return nil, err
 	}
 
+	chart := options.Helm.Repo.Name
+	if len(chart) == 0 {
+		chart = options.Helm.Repo.URL
+	}
+	if err := Preflight(path.Base(chart), options.Requirements); err != nil {
+		return nil, err
+	}
+
 	if options.CreateNamespace {
 		if err := k8s.CreateNamespace(options.Namespace); err != nil {
 			return nil, err
@@ -43,32 +51,50 @@ func MakeInstallChart(options *types.InstallerOptions) (*types.InstallerOutput,
 
 	log.Printf("User dir established as: %s\n", userPath)
 
-	os.Setenv("HELM_HOME", path.Join(userPath, ".helm"))
+	os.Setenv("HELM_HOME", config.GetHelmDir())
 
-	_, err = helm.TryDownloadHelm(userPath, clientArch, clientOS)
-	if err != nil {
-		return nil, err
-	}
-
-	installer := helm.Helm3OCIUpgrade
-	name := options.Helm.Repo.URL
-	if !helm.IsOCI(options.Helm.Repo.URL) {
-		if err = helm.AddHelmRepo(options.Helm.Repo.Name, options.Helm.Repo.URL, options.Helm.UpdateRepo); err != nil {
+	repo := options.Helm.Repo
+	if helm.IsLocalChart(repo.Name) {
+		if err := helm.Helm3LocalUpgrade(
+			repo.Name,
+			options.Namespace,
+			options.Helm.ValuesFiles,
+			options.Helm.Overrides,
+			options.Helm.Wait); err != nil {
 			return result, err
 		}
 
-		if err := helm.FetchChart(options.Helm.Repo.Name, options.Helm.Repo.Version); err != nil {
+		return result, nil
+	}
+
+	if helm.IsOCI(repo.URL) {
+		if err := helm.Helm3OCIUpgradeWithAuth(
+			repo.URL,
+			options.Namespace,
+			options.Helm.ValuesFiles,
+			repo.Version,
+			options.Helm.Overrides,
+			options.Helm.Wait,
+			repo.Auth); err != nil {
 			return result, err
 		}
-		installer = helm.Helm3Upgrade
-		name = options.Helm.Repo.Name
+
+		return result, nil
+	}
+
+	if err = helm.AddHelmRepoWithAuth(repo.Name, repo.URL, options.Helm.UpdateRepo, repo.Auth); err != nil {
+		return result, err
+	}
+
+	if err := helm.FetchChart(repo.Name, repo.Version); err != nil {
+		return result, err
 	}
 
-	if err := installer(
-		name,
+	if err := helm.Helm3Upgrade(
+		repo.Name,
 		options.Namespace,
 		options.Helm.ValuesFiles,
-		options.Helm.Repo.Version,
+		repo.Version,
 		options.Helm.Overrides,
 		options.Helm.Wait); err != nil {
 		return result, err
@@ -0,0 +1,246 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Definition describes an app which installs a single helm chart, so
+// that it can be added to "arkade install" with YAML rather than Go:
+//
+//	name: kube-state-metrics
+//	short: Install kube-state-metrics
+//	namespace: kube-system
+//	repo:
+//	  name: prometheus-community
+//	  url: https://prometheus-community.github.io/helm-charts
+//	chart: kube-state-metrics
+//	flags:
+//	- name: replicas
+//	  type: int
+//	  default: "1"
+//	  description: Number of replicas
+//	  value: replicas
+//	info: |
+//	  kubectl port-forward -n {{ namespace }} service/kube-state-metrics 8080
+type Definition struct {
+	// Name is the app's name, i.e. "arkade install NAME".
+	Name string `yaml:"name"`
+
+	Short   string `yaml:"short"`
+	Long    string `yaml:"long"`
+	Example string `yaml:"example"`
+
+	// Namespace is the default for --namespace, "default" when empty.
+	Namespace string `yaml:"namespace"`
+
+	Repo DefinitionRepo `yaml:"repo"`
+
+	// Chart is the chart's name within Repo, or an oci:// reference.
+	Chart string `yaml:"chart"`
+
+	// Version of the chart, the latest when empty.
+	Version string `yaml:"version"`
+
+	// Set holds helm values which are always set, before any flags.
+	Set map[string]string `yaml:"set"`
+
+	Flags []DefinitionFlag `yaml:"flags"`
+
+	// Info is printed after the install and by "arkade info", and is
+	// rendered with RenderInfo.
+	Info string `yaml:"info"`
+}
+
+// DefinitionRepo is the helm repository which holds the chart.
+type DefinitionRepo struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// DefinitionFlag is a flag of the install command which sets a helm
+// value, i.e. --replicas=2 sets "replicas=2".
+type DefinitionFlag struct {
+	Name        string `yaml:"name"`
+	Shorthand   string `yaml:"shorthand"`
+	Description string `yaml:"description"`
+
+	// Type is "string", "bool" or "int", "string" when empty.
+	Type    string `yaml:"type"`
+	Default string `yaml:"default"`
+
+	// Value is the helm value to set, i.e. "image.tag". A flag without
+	// one can still be used in the info message.
+	Value string `yaml:"value"`
+}
+
+// ReservedFlags are added to every app from a Definition, or to
+// "arkade install" itself, so can't be defined again.
+var ReservedFlags = map[string]bool{
+	"namespace":       true,
+	"set":             true,
+	"values":          true,
+	"update-repo":     true,
+	"kubeconfig":      true,
+	"wait":            true,
+	"wait-timeout":    true,
+	"chart-version":   true,
+	"skip-preflight":  true,
+	"patch":           true,
+	"kustomize":       true,
+	"mirror":          true,
+	"mirror-insecure": true,
+	"context":         true,
+	"all-contexts":    true,
+	"selector":        true,
+	"dry-run":         true,
+	"output":          true,
+	"export":          true,
+	"help":            true,
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ChartRef returns the chart to install, i.e. "prometheus-community/
+// kube-state-metrics".
+func (d Definition) ChartRef() string {
+	if strings.HasPrefix(d.Chart, "oci://") {
+		return d.Chart
+	}
+	return d.Repo.Name + "/" + d.Chart
+}
+
+// Validate checks a Definition has everything needed to install it.
+func (d Definition) Validate() error {
+	if !validName.MatchString(d.Name) {
+		return fmt.Errorf("name %q must be lower-case letters, numbers and dashes", d.Name)
+	}
+	if len(d.Chart) == 0 {
+		return fmt.Errorf("%s: chart is required", d.Name)
+	}
+	if !strings.HasPrefix(d.Chart, "oci://") && (len(d.Repo.Name) == 0 || len(d.Repo.URL) == 0) {
+		return fmt.Errorf("%s: repo.name and repo.url are required for chart %q", d.Name, d.Chart)
+	}
+
+	seen := map[string]bool{}
+	for _, f := range d.Flags {
+		if !validName.MatchString(f.Name) {
+			return fmt.Errorf("%s: flag %q must be lower-case letters, numbers and dashes", d.Name, f.Name)
+		}
+		if ReservedFlags[f.Name] {
+			return fmt.Errorf("%s: flag %q is already defined by arkade", d.Name, f.Name)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("%s: flag %q is given more than once", d.Name, f.Name)
+		}
+		seen[f.Name] = true
+
+		if len(f.Shorthand) > 1 || f.Shorthand == "n" || f.Shorthand == "f" {
+			return fmt.Errorf("%s: shorthand %q for flag %q must be a single letter other than n and f", d.Name, f.Shorthand, f.Name)
+		}
+
+		switch f.Type {
+		case "", "string":
+		case "bool", "int":
+			if err := f.checkDefault(); err != nil {
+				return fmt.Errorf("%s: %w", d.Name, err)
+			}
+		default:
+			return fmt.Errorf("%s: flag %q has unknown type %q, use string, bool or int", d.Name, f.Name, f.Type)
+		}
+	}
+
+	return nil
+}
+
+func (f DefinitionFlag) checkDefault() error {
+	if len(f.Default) == 0 {
+		return nil
+	}
+
+	var err error
+	switch f.Type {
+	case "bool":
+		_, err = strconv.ParseBool(f.Default)
+	case "int":
+		_, err = strconv.Atoi(f.Default)
+	}
+	if err != nil {
+		return fmt.Errorf("default %q for flag %q is not a %s", f.Default, f.Name, f.Type)
+	}
+	return nil
+}
+
+// ParseDefinition reads a single Definition from YAML.
+func ParseDefinition(data []byte) (*Definition, error) {
+	d := &Definition{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(d); err != nil {
+		return nil, err
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// LoadDefinitions reads each .yaml or .yml file at the top of fsys,
+// i.e. os.DirFS(dir), sorted by name. A missing directory has no
+// definitions. Files which can't be read are
+// reported together in the error, along with the valid definitions.
+func LoadDefinitions(fsys fs.FS) ([]Definition, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+
+	var definitions []Definition
+	var errs []error
+	seen := map[string]string{}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		d, err := ParseDefinition(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid app definition %s: %w", file, err))
+			continue
+		}
+		if other, ok := seen[d.Name]; ok {
+			errs = append(errs, fmt.Errorf("invalid app definition %s: %s is already defined in %s", file, d.Name, other))
+			continue
+		}
+		seen[d.Name] = file
+		definitions = append(definitions, *d)
+	}
+
+	return definitions, errors.Join(errs...)
+}
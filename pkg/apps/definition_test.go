@@ -0,0 +1,101 @@
+package apps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const podinfoDefinition = `name: podinfo
+namespace: podinfo
+repo:
+  name: podinfo
+  url: https://stefanprodan.github.io/podinfo
+chart: podinfo
+flags:
+- name: replicas
+  type: int
+  default: "2"
+  value: replicaCount
+`
+
+func Test_ParseDefinition(t *testing.T) {
+	d, err := ParseDefinition([]byte(podinfoDefinition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.ChartRef() != "podinfo/podinfo" {
+		t.Errorf("want chart podinfo/podinfo, got %s", d.ChartRef())
+	}
+	if len(d.Flags) != 1 || d.Flags[0].Value != "replicaCount" || d.Flags[0].Default != "2" {
+		t.Errorf("unexpected flags: %v", d.Flags)
+	}
+}
+
+func Test_ParseDefinition_OCI(t *testing.T) {
+	d, err := ParseDefinition([]byte("name: api\nchart: oci://registry.example.com/charts/api\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ChartRef() != "oci://registry.example.com/charts/api" {
+		t.Errorf("want the OCI chart, got %s", d.ChartRef())
+	}
+}
+
+func Test_ParseDefinition_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       string
+	}{
+		{"no name", "chart: podinfo\n", "name"},
+		{"upper case name", "name: PodInfo\nchart: oci://example.com/podinfo\n", "lower-case"},
+		{"no repo", "name: podinfo\nchart: podinfo\n", "repo.name and repo.url"},
+		{"unknown field", "name: podinfo\ncharts: podinfo\n", "charts"},
+		{"reserved flag", "name: podinfo\nchart: oci://example.com/podinfo\nflags:\n- name: namespace\n", "already defined"},
+		{"duplicate flag", "name: podinfo\nchart: oci://example.com/podinfo\nflags:\n- name: a\n- name: a\n", "more than once"},
+		{"unknown type", "name: podinfo\nchart: oci://example.com/podinfo\nflags:\n- name: a\n  type: float\n", "unknown type"},
+		{"bad default", "name: podinfo\nchart: oci://example.com/podinfo\nflags:\n- name: a\n  type: int\n  default: two\n", "not a int"},
+		{"shorthand", "name: podinfo\nchart: oci://example.com/podinfo\nflags:\n- name: a\n  shorthand: n\n", "shorthand"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(tc.definition))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("want error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func Test_LoadDefinitions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"podinfo.yaml":  {Data: []byte(podinfoDefinition)},
+		"copy.yml":      {Data: []byte(podinfoDefinition)},
+		"broken.yaml":   {Data: []byte("name: broken\n")},
+		"README.md":     {Data: []byte("# Apps\n")},
+		"nested/a.yaml": {Data: []byte(podinfoDefinition)},
+	}
+
+	definitions, err := LoadDefinitions(fsys)
+	if len(definitions) != 1 || definitions[0].Name != "podinfo" {
+		t.Fatalf("want podinfo only, got %v", definitions)
+	}
+
+	for _, want := range []string{"broken.yaml", "podinfo.yaml: podinfo is already defined in copy.yml"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("want error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func Test_LoadDefinitions_MissingDir(t *testing.T) {
+	definitions, err := LoadDefinitions(os.DirFS(filepath.Join(t.TempDir(), "apps")))
+	if err != nil || len(definitions) != 0 {
+		t.Errorf("want no definitions or error, got %v, %v", definitions, err)
+	}
+}
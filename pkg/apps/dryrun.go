@@ -0,0 +1,52 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// AddDryRunFlags adds --dry-run, --export and --output to an install
+// command.
+func AddDryRunFlags(command *cobra.Command) {
+	flags := command.PersistentFlags()
+	flags.Bool("dry-run", false, "Print the helm and kubectl commands, values and manifests instead of changing the cluster")
+	flags.String("output", "", "Write each dry-run step or exported app to files in this directory, implies --dry-run without --export")
+	flags.String("export", "", "Print GitOps manifests instead of changing the cluster, one of: flux, argocd")
+}
+
+// SetupDryRun reads the flags added by AddDryRunFlags, and when a
+// dry-run was requested, installs a k8s.DryRunExecutor which renders
+// commands to out or the output directory. It returns nil otherwise.
+func SetupDryRun(command *cobra.Command, out io.Writer) (*k8s.DryRunExecutor, error) {
+	dryRun, _ := command.Flags().GetBool("dry-run")
+	outputDir, _ := command.Flags().GetString("output")
+
+	if export, _ := command.Flags().GetString("export"); len(export) > 0 {
+		return nil, nil
+	}
+
+	if !dryRun && len(outputDir) == 0 {
+		return nil, nil
+	}
+
+	// Apps installed from a stack file share the stack's executor
+	if existing, ok := k8s.GetExecutor().(*k8s.DryRunExecutor); ok {
+		return existing, nil
+	}
+
+	executor, err := k8s.NewDryRunExecutor(out, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	k8s.SetExecutor(executor)
+	fmt.Fprintln(out, "[dry-run] no changes will be made to the cluster")
+
+	return executor, nil
+}
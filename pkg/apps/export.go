@@ -0,0 +1,711 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	execute "github.com/alexellis/go-execute/v2"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ExportFlux emits Flux HelmRepository and HelmRelease objects,
+	// with secrets as SOPS placeholders.
+	ExportFlux = "flux"
+
+	// ExportArgoCD emits Argo CD Application objects, with secrets as
+	// SealedSecret placeholders.
+	ExportArgoCD = "argocd"
+)
+
+// secretPlaceholder is written in place of each secret value.
+const secretPlaceholder = "REPLACE_ME"
+
+// ExportExecutor converts the commands an app would run into GitOps
+// manifests instead of changing the cluster. Helm releases become a
+// Flux HelmRelease or an Argo CD Application, namespaces and applied
+// manifests are kept as they are, and secrets become placeholders to
+// be encrypted before they are committed.
+//
+// Read-only commands such as "helm repo add" and "helm fetch" still
+// run, any other command cannot be exported and returns an error.
+type ExportExecutor struct {
+	Format string
+
+	// FluxNamespace holds the Flux HelmRepository objects, and
+	// ArgoCDNamespace holds the Argo CD Applications.
+	FluxNamespace   string
+	ArgoCDNamespace string
+
+	// Warnings receives a note for each release whose values look like
+	// secrets, and were taken out of the exported values.
+	Warnings io.Writer
+
+	mu    sync.Mutex
+	repos map[string]string
+	docs  []exportDoc
+}
+
+type exportDoc struct {
+	comment string
+	object  interface{}
+}
+
+// NewExportExecutor creates an ExportExecutor for ExportFlux or
+// ExportArgoCD.
+func NewExportExecutor(format string) (*ExportExecutor, error) {
+	switch format {
+	case ExportFlux, ExportArgoCD:
+	default:
+		return nil, fmt.Errorf("unsupported export format: %q, use %s or %s", format, ExportFlux, ExportArgoCD)
+	}
+
+	return &ExportExecutor{
+		Format:          format,
+		FluxNamespace:   "flux-system",
+		ArgoCDNamespace: "argocd",
+		Warnings:        os.Stderr,
+		repos:           map[string]string{},
+	}, nil
+}
+
+// SetupExport reads the --export flag added by AddDryRunFlags, and
+// when set, installs an ExportExecutor. It returns nil otherwise.
+func SetupExport(command *cobra.Command) (*ExportExecutor, error) {
+	format, _ := command.Flags().GetString("export")
+	if len(format) == 0 {
+		return nil, nil
+	}
+
+	if dryRun, _ := command.Flags().GetBool("dry-run"); dryRun {
+		return nil, fmt.Errorf("--export and --dry-run cannot be used together")
+	}
+
+	// Apps installed from a stack file share the stack's executor
+	if existing, ok := k8s.GetExecutor().(*ExportExecutor); ok {
+		return existing, nil
+	}
+
+	executor, err := NewExportExecutor(format)
+	if err != nil {
+		return nil, err
+	}
+
+	k8s.SetExecutor(executor)
+	return executor, nil
+}
+
+// WriteExport flushes an app's exported objects to stdout, or to
+// APP.yaml when an output directory is given.
+func WriteExport(executor *ExportExecutor, app, outputDir string, stdout io.Writer) error {
+	if executor.Len() == 0 {
+		return nil
+	}
+
+	if len(outputDir) == 0 {
+		return executor.Flush(stdout)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	file := filepath.Join(outputDir, app+".yaml")
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := executor.Flush(f); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote: %s\n", file)
+	return nil
+}
+
+// DryRun is always true, as nothing is changed in the cluster.
+func (e *ExportExecutor) DryRun() bool {
+	return true
+}
+
+func (e *ExportExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	command := filepath.Base(task.Command)
+	positional, _ := k8s.SplitArgs(task.Args)
+
+	// Remember each repository's URL for the HelmRelease
+	if command == "helm" && len(positional) >= 4 && positional[0] == "repo" && positional[1] == "add" {
+		e.repos[positional[2]] = positional[3]
+	}
+
+	if k8s.IsReadOnly(task.Command, task.Args) {
+		return k8s.TaskExecutor{}.Execute(ctx, task)
+	}
+
+	var err error
+	switch {
+	case command == "helm" && len(positional) > 0 && (positional[0] == "upgrade" || positional[0] == "install"):
+		err = e.exportRelease(task)
+	case command == "kubectl" && len(positional) > 1 && positional[0] == "create" && (positional[1] == "namespace" || positional[1] == "ns"):
+		e.exportNamespace(positional)
+	case command == "kubectl" && len(positional) > 1 && positional[0] == "create" && positional[1] == "secret":
+		err = e.exportSecret(task.Args)
+	case command == "kubectl" && len(positional) > 0 && positional[0] == "apply":
+		err = e.exportManifests(task)
+	default:
+		err = fmt.Errorf("unable to export \"%s %s\", only helm releases, namespaces, secrets and manifests can be exported",
+			command, strings.Join(task.Args, " "))
+	}
+
+	return execute.ExecResult{}, err
+}
+
+// Len returns the number of objects waiting to be flushed.
+func (e *ExportExecutor) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.docs)
+}
+
+// Flush writes the exported objects as a multi-document YAML file,
+// then forgets them so that each app in a stack is written once.
+func (e *ExportExecutor) Flush(w io.Writer) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := &bytes.Buffer{}
+	for i, doc := range e.docs {
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		if len(doc.comment) > 0 {
+			for _, line := range strings.Split(doc.comment, "\n") {
+				out.WriteString("# " + line + "\n")
+			}
+		}
+
+		if raw, ok := doc.object.(string); ok {
+			out.WriteString(strings.TrimSpace(raw) + "\n")
+			continue
+		}
+
+		data, err := yaml.Marshal(doc.object)
+		if err != nil {
+			return err
+		}
+		out.Write(data)
+	}
+
+	if _, err := w.Write(out.Bytes()); err != nil {
+		return err
+	}
+	e.docs = nil
+	return nil
+}
+
+func (e *ExportExecutor) add(comment string, object interface{}) {
+	e.docs = append(e.docs, exportDoc{comment: comment, object: object})
+}
+
+func (e *ExportExecutor) exportNamespace(positional []string) {
+	if len(positional) < 3 {
+		return
+	}
+
+	e.add("", map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": positional[2]},
+	})
+}
+
+func (e *ExportExecutor) exportSecret(args []string) error {
+	positional, flags := k8s.SplitArgs(args)
+	if len(positional) < 4 {
+		return fmt.Errorf("unable to export secret from: kubectl %s", strings.Join(args, " "))
+	}
+
+	secretType, name := positional[2], positional[3]
+	namespace := flags["-n"]
+	if len(namespace) == 0 {
+		namespace = flags["--namespace"]
+	}
+
+	kubeType := "Opaque"
+	data := map[string]interface{}{}
+
+	switch secretType {
+	case "docker-registry":
+		kubeType = "kubernetes.io/dockerconfigjson"
+		data[".dockerconfigjson"] = secretPlaceholder
+	case "tls":
+		kubeType = "kubernetes.io/tls"
+		data["tls.crt"] = secretPlaceholder
+		data["tls.key"] = secretPlaceholder
+	default:
+		for _, arg := range args {
+			for _, prefix := range []string{"--from-literal=", "--from-file="} {
+				if kv, ok := strings.CutPrefix(arg, prefix); ok {
+					key, _, found := strings.Cut(kv, "=")
+					if !found && prefix == "--from-file=" {
+						key = filepath.Base(kv)
+					}
+					data[key] = secretPlaceholder
+				}
+			}
+		}
+	}
+
+	metadata := map[string]interface{}{"name": name}
+	if len(namespace) > 0 {
+		metadata["namespace"] = namespace
+	}
+
+	if e.Format == ExportArgoCD {
+		e.add(fmt.Sprintf("Replace with the output of:\n  kubectl create secret %s %s -n %s --dry-run=client -o yaml ... | kubeseal -o yaml", secretType, name, namespace),
+			map[string]interface{}{
+				"apiVersion": "bitnami.com/v1alpha1",
+				"kind":       "SealedSecret",
+				"metadata":   metadata,
+				"spec": map[string]interface{}{
+					"encryptedData": data,
+					"template": map[string]interface{}{
+						"metadata": metadata,
+						"type":     kubeType,
+					},
+				},
+			})
+		return nil
+	}
+
+	e.add("Set each value, then encrypt before committing:\n  sops --encrypt --encrypted-regex '^(data|stringData)$' --in-place FILE",
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   metadata,
+			"type":       kubeType,
+			"stringData": data,
+		})
+	return nil
+}
+
+func (e *ExportExecutor) exportManifests(task execute.ExecTask) error {
+	var stdin []byte
+	if task.Stdin != nil {
+		data := &bytes.Buffer{}
+		if _, err := data.ReadFrom(task.Stdin); err != nil {
+			return err
+		}
+		stdin = data.Bytes()
+	}
+
+	for i := 0; i < len(task.Args)-1; i++ {
+		switch task.Args[i] {
+		case "-f", "--filename":
+		case "-k", "--kustomize":
+			e.add("", map[string]interface{}{
+				"apiVersion": "kustomize.config.k8s.io/v1beta1",
+				"kind":       "Kustomization",
+				"resources":  []string{task.Args[i+1]},
+			})
+			continue
+		default:
+			continue
+		}
+
+		source := task.Args[i+1]
+		data, err := k8s.ReadManifest(source, stdin)
+		if err != nil {
+			// Refer to a URL which can't be fetched now, so the
+			// GitOps tool fetches it instead
+			if strings.Contains(source, "://") {
+				e.add("Unable to fetch "+source+", so it is referenced instead",
+					map[string]interface{}{
+						"apiVersion": "kustomize.config.k8s.io/v1beta1",
+						"kind":       "Kustomization",
+						"resources":  []string{source},
+					})
+				continue
+			}
+			return fmt.Errorf("unable to read manifest %s: %w", source, err)
+		}
+
+		comment := ""
+		if source != "-" && !strings.HasPrefix(source, os.TempDir()) {
+			comment = "Source: " + source
+		}
+		e.add(comment, strings.TrimPrefix(strings.TrimSpace(string(data)), "---\n"))
+	}
+
+	return nil
+}
+
+// helmRelease is what a "helm upgrade --install" would install.
+type helmRelease struct {
+	Name       string
+	Namespace  string
+	Chart      string
+	RepoName   string
+	RepoURL    string
+	OCI        bool
+	Version    string
+	ChartFiles []string
+	Values     map[string]interface{}
+}
+
+func (e *ExportExecutor) parseRelease(task execute.ExecTask) (*helmRelease, error) {
+	positional, flags := k8s.SplitArgs(task.Args)
+	if len(positional) < 3 {
+		return nil, fmt.Errorf("unable to export helm release from: helm %s", strings.Join(task.Args, " "))
+	}
+
+	rel := &helmRelease{
+		Name:      positional[1],
+		Namespace: flags["--namespace"],
+		Values:    map[string]interface{}{},
+	}
+
+	chart := positional[2]
+	if strings.HasPrefix(chart, "oci://") {
+		index := strings.LastIndex(chart, "/")
+		rel.OCI = true
+		rel.RepoURL = chart[:index]
+		rel.Chart = chart[index+1:]
+		rel.RepoName = rel.Chart
+	} else {
+		repo, name, ok := strings.Cut(chart, "/")
+		if !ok || len(repo) == 0 || strings.HasPrefix(repo, ".") || strings.HasSuffix(chart, ".tgz") {
+			return nil, fmt.Errorf("unable to export chart %s, only charts from a repository can be exported", chart)
+		}
+		url, ok := e.repos[repo]
+		if !ok {
+			return nil, fmt.Errorf("unable to export chart %s, the URL for repository %s is unknown", chart, repo)
+		}
+		rel.RepoName, rel.RepoURL, rel.Chart = repo, url, name
+	}
+
+	for i := 0; i < len(task.Args)-1; i++ {
+		switch task.Args[i] {
+		case "--version":
+			rel.Version = task.Args[i+1]
+		case "--values", "-f":
+			if err := rel.addValuesFile(task.Args[i+1], task.Cwd); err != nil {
+				return nil, err
+			}
+		case "--set":
+			k, v, _ := strings.Cut(task.Args[i+1], "=")
+			setValue(rel.Values, k, parseSetValue(v))
+		case "--set-string":
+			k, v, _ := strings.Cut(task.Args[i+1], "=")
+			setValue(rel.Values, k, v)
+		case "--set-file":
+			k, file, _ := strings.Cut(task.Args[i+1], "=")
+			if !filepath.IsAbs(file) && len(task.Cwd) > 0 {
+				file = filepath.Join(task.Cwd, file)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read --set-file for %s: %w", k, err)
+			}
+			setValue(rel.Values, k, string(data))
+		case "--post-renderer":
+			return nil, fmt.Errorf("unable to export %s with --patch, --kustomize or --mirror, add the patches and image overrides to your GitOps repository instead", rel.Name)
+		}
+	}
+
+	return rel, nil
+}
+
+// addValuesFile refers to values files from the chart by name, and
+// merges the user's values files into Values.
+func (rel *helmRelease) addValuesFile(file, chartDir string) error {
+	if len(chartDir) > 0 && strings.HasPrefix(file, chartDir+string(os.PathSeparator)) {
+		if name := strings.TrimPrefix(file, chartDir+string(os.PathSeparator)); name != "values.yaml" {
+			rel.ChartFiles = append(rel.ChartFiles, name)
+		}
+		return nil
+	}
+
+	if strings.Contains(file, "://") {
+		return fmt.Errorf("unable to export values file %s, only local files can be exported", file)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("unable to parse values file %s: %w", file, err)
+	}
+	mergeValues(rel.Values, values)
+
+	return nil
+}
+
+func (e *ExportExecutor) exportRelease(task execute.ExecTask) error {
+	rel, err := e.parseRelease(task)
+	if err != nil {
+		return err
+	}
+
+	version := rel.Version
+	if len(version) == 0 {
+		version = "*"
+	}
+
+	// Argo CD can't read values from a Secret, so they're only redacted
+	secrets := redactValues(rel.Values, "", e.Format == ExportFlux)
+	if len(secrets) > 0 {
+		if e.Format == ExportFlux {
+			fmt.Fprintf(e.Warnings, "Warning: %d value(s) of %s look like secrets, and were moved to Secret %s-values: %s\n",
+				len(secrets), rel.Name, rel.Name, strings.Join(secrets, ", "))
+		} else {
+			fmt.Fprintf(e.Warnings, "Warning: %d value(s) of %s look like secrets, and were replaced with %s, set them outside of git: %s\n",
+				len(secrets), rel.Name, secretPlaceholder, strings.Join(secrets, ", "))
+		}
+	}
+
+	if e.Format == ExportArgoCD {
+		helm := map[string]interface{}{"releaseName": rel.Name}
+		if len(rel.ChartFiles) > 0 {
+			helm["valueFiles"] = rel.ChartFiles
+		}
+		if len(rel.Values) > 0 {
+			helm["valuesObject"] = rel.Values
+		}
+
+		e.add("", map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":      rel.Name,
+				"namespace": e.ArgoCDNamespace,
+			},
+			"spec": map[string]interface{}{
+				"project": "default",
+				"source": map[string]interface{}{
+					"repoURL":        strings.TrimPrefix(rel.RepoURL, "oci://"),
+					"chart":          rel.Chart,
+					"targetRevision": version,
+					"helm":           helm,
+				},
+				"destination": map[string]interface{}{
+					"server":    "https://kubernetes.default.svc",
+					"namespace": rel.Namespace,
+				},
+				"syncPolicy": map[string]interface{}{
+					"syncOptions": []string{"CreateNamespace=true"},
+				},
+			},
+		})
+		return nil
+	}
+
+	repoSpec := map[string]interface{}{
+		"interval": "1h",
+		"url":      rel.RepoURL,
+	}
+	if rel.OCI {
+		repoSpec["type"] = "oci"
+	}
+
+	e.add("", map[string]interface{}{
+		"apiVersion": "source.toolkit.fluxcd.io/v1",
+		"kind":       "HelmRepository",
+		"metadata": map[string]interface{}{
+			"name":      rel.RepoName,
+			"namespace": e.FluxNamespace,
+		},
+		"spec": repoSpec,
+	})
+
+	chartSpec := map[string]interface{}{
+		"chart":   rel.Chart,
+		"version": version,
+		"sourceRef": map[string]interface{}{
+			"kind":      "HelmRepository",
+			"name":      rel.RepoName,
+			"namespace": e.FluxNamespace,
+		},
+	}
+	if len(rel.ChartFiles) > 0 {
+		chartSpec["valuesFiles"] = append([]string{"values.yaml"}, rel.ChartFiles...)
+	}
+
+	spec := map[string]interface{}{
+		"interval":    "1h",
+		"releaseName": rel.Name,
+		"chart":       map[string]interface{}{"spec": chartSpec},
+	}
+	if len(rel.Values) > 0 {
+		spec["values"] = rel.Values
+	}
+
+	if len(secrets) > 0 {
+		name := rel.Name + "-values"
+		data := map[string]interface{}{}
+		var valuesFrom []interface{}
+		for _, path := range secrets {
+			key := strings.ReplaceAll(path, `\.`, ".")
+			data[key] = secretPlaceholder
+			valuesFrom = append(valuesFrom, map[string]interface{}{
+				"kind":       "Secret",
+				"name":       name,
+				"valuesKey":  key,
+				"targetPath": path,
+			})
+		}
+		spec["valuesFrom"] = valuesFrom
+
+		e.add("Set each value, then encrypt before committing:\n  sops --encrypt --encrypted-regex '^(data|stringData)$' --in-place FILE",
+			map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]interface{}{"name": name, "namespace": rel.Namespace},
+				"type":       "Opaque",
+				"stringData": data,
+			})
+	}
+
+	e.add("", map[string]interface{}{
+		"apiVersion": "helm.toolkit.fluxcd.io/v2",
+		"kind":       "HelmRelease",
+		"metadata": map[string]interface{}{
+			"name":      rel.Name,
+			"namespace": rel.Namespace,
+		},
+		"spec": spec,
+	})
+	return nil
+}
+
+// secretValueKey matches values which usually hold a secret, such as
+// "adminPassword" or "auth.token", whether from --set or a values file.
+var secretValueKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|apikey|api_key|privatekey|accesskey|secretkey|credentials)$`)
+
+// redactValues finds values which look like secrets, and removes them,
+// or replaces them with a placeholder when remove is false. It returns
+// their paths, sorted, with any dots in keys escaped as for --set.
+func redactValues(values map[string]interface{}, prefix string, remove bool) []string {
+	var paths []string
+	for k, v := range values {
+		path := strings.ReplaceAll(k, ".", `\.`)
+		if len(prefix) > 0 {
+			path = prefix + "." + path
+		}
+
+		switch value := v.(type) {
+		case map[string]interface{}:
+			found := redactValues(value, path, remove)
+			if remove && len(found) > 0 && len(value) == 0 {
+				delete(values, k)
+			}
+			paths = append(paths, found...)
+			continue
+		case bool, nil:
+			continue
+		}
+
+		// i.e. "existingSecret" names a secret, rather than holding one
+		if !secretValueKey.MatchString(k) || strings.HasPrefix(strings.ToLower(k), "existing") {
+			continue
+		}
+
+		paths = append(paths, path)
+		if remove {
+			delete(values, k)
+		} else {
+			values[k] = secretPlaceholder
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// mergeValues deep-merges src into dst, as helm does for values files.
+func mergeValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if srcMap, ok := v.(map[string]interface{}); ok {
+			if dstMap, ok := dst[k].(map[string]interface{}); ok {
+				mergeValues(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+var unescapedDot = regexp.MustCompile(`([^\\])\.`)
+
+// setValue applies a --set override to values, following helm's rules
+// for nested keys and escaped dots. The value is parsed by
+// parseSetValue for --set, and kept as a string for --set-string.
+func setValue(values map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(unescapedDot.ReplaceAllString(key, "$1\x00"), "\x00")
+
+	current := values
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\.`, ".")
+		if i == len(parts)-1 {
+			current[part] = value
+			return
+		}
+
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+}
+
+// parseSetValue types a --set value as helm does, with lists in braces,
+// booleans, null and integers.
+func parseSetValue(value string) interface{} {
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		var list []interface{}
+		for _, item := range strings.Split(strings.Trim(value, "{}"), ",") {
+			list = append(list, parseSetValue(item))
+		}
+		return list
+	}
+
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil && (value == "0" || !strings.HasPrefix(value, "0")) {
+		return i
+	}
+
+	return value
+}
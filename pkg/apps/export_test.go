@@ -0,0 +1,270 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	execute "github.com/alexellis/go-execute/v2"
+	"gopkg.in/yaml.v3"
+)
+
+func exportDocs(t *testing.T, format string, tasks ...execute.ExecTask) []map[string]interface{} {
+	t.Helper()
+
+	e, err := NewExportExecutor(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.repos["openfaas"] = "https://openfaas.github.io/faas-netes/"
+	e.Warnings = io.Discard
+
+	for _, task := range tasks {
+		if _, err := e.Execute(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := e.Flush(out); err != nil {
+		t.Fatal(err)
+	}
+	if e.Len() != 0 {
+		t.Fatalf("want no objects after Flush, got %d", e.Len())
+	}
+
+	var docs []map[string]interface{}
+	dec := yaml.NewDecoder(out)
+	for {
+		doc := map[string]interface{}{}
+		if err := dec.Decode(&doc); err != nil {
+			break
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func lookup(doc map[string]interface{}, path string) interface{} {
+	var v interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func Test_ExportExecutor_Flux(t *testing.T) {
+	values := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(values, []byte("gateway:\n  replicas: 2\n  image: gw\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	docs := exportDocs(t, ExportFlux,
+		execute.ExecTask{Command: "kubectl", Args: []string{"create", "namespace", "openfaas-fn"}},
+		execute.ExecTask{Command: "kubectl", Args: []string{"create", "secret", "generic", "basic-auth", "--namespace", "openfaas",
+			"--from-literal=basic-auth-user=admin", "--from-literal=basic-auth-password=secret"}},
+		execute.ExecTask{Command: "helm", Args: []string{"upgrade", "--install", "openfaas", "openfaas/openfaas",
+			"--namespace", "openfaas", "--version", "14.2.0", "--values", values,
+			"--set", "gateway.replicas=3", "--set", "basic_auth=true", "--set", "ingress.hosts={a,b}"}},
+	)
+
+	if len(docs) != 4 {
+		t.Fatalf("want 4 objects, got %d: %v", len(docs), docs)
+	}
+
+	if docs[0]["kind"] != "Namespace" || lookup(docs[0], "metadata.name") != "openfaas-fn" {
+		t.Errorf("want Namespace openfaas-fn, got %v", docs[0])
+	}
+
+	wantData := map[string]interface{}{"basic-auth-user": "REPLACE_ME", "basic-auth-password": "REPLACE_ME"}
+	if docs[1]["kind"] != "Secret" || !reflect.DeepEqual(docs[1]["stringData"], wantData) {
+		t.Errorf("want Secret with placeholders, got %v", docs[1])
+	}
+	if lookup(docs[1], "metadata.namespace") != "openfaas" {
+		t.Errorf("want secret in openfaas, got %v", docs[1]["metadata"])
+	}
+
+	if docs[2]["kind"] != "HelmRepository" || lookup(docs[2], "spec.url") != "https://openfaas.github.io/faas-netes/" {
+		t.Errorf("want HelmRepository for openfaas, got %v", docs[2])
+	}
+
+	rel := docs[3]
+	if rel["kind"] != "HelmRelease" || lookup(rel, "metadata.namespace") != "openfaas" {
+		t.Errorf("want HelmRelease in openfaas, got %v", rel)
+	}
+	if got := lookup(rel, "spec.chart.spec.version"); got != "14.2.0" {
+		t.Errorf("want version 14.2.0, got %v", got)
+	}
+
+	wantValues := map[string]interface{}{
+		"gateway":    map[string]interface{}{"replicas": 3, "image": "gw"},
+		"basic_auth": true,
+		"ingress":    map[string]interface{}{"hosts": []interface{}{"a", "b"}},
+	}
+	if got := lookup(rel, "spec.values"); !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("want values:\n%v\ngot:\n%v", wantValues, got)
+	}
+}
+
+func Test_ExportExecutor_ArgoCD(t *testing.T) {
+	chartDir := t.TempDir()
+
+	docs := exportDocs(t, ExportArgoCD,
+		execute.ExecTask{Command: "kubectl", Args: []string{"create", "secret", "tls", "cert", "-n", "web", "--cert=a", "--key=b"}},
+		execute.ExecTask{Command: "helm", Cwd: chartDir, Args: []string{"upgrade", "--install", "nginx", "oci://ghcr.io/example/charts/nginx",
+			"--namespace", "web", "--values", filepath.Join(chartDir, "values-arm64.yaml")}},
+	)
+
+	if len(docs) != 2 {
+		t.Fatalf("want 2 objects, got %d: %v", len(docs), docs)
+	}
+
+	if docs[0]["kind"] != "SealedSecret" || lookup(docs[0], "spec.template.type") != "kubernetes.io/tls" {
+		t.Errorf("want SealedSecret of type tls, got %v", docs[0])
+	}
+
+	app := docs[1]
+	if app["kind"] != "Application" || lookup(app, "metadata.namespace") != "argocd" {
+		t.Errorf("want Application in argocd, got %v", app)
+	}
+	if got := lookup(app, "spec.source.repoURL"); got != "ghcr.io/example/charts" {
+		t.Errorf("want OCI repoURL without scheme, got %v", got)
+	}
+	if got := lookup(app, "spec.source.targetRevision"); got != "*" {
+		t.Errorf("want targetRevision *, got %v", got)
+	}
+	if got := lookup(app, "spec.source.helm.valueFiles"); !reflect.DeepEqual(got, []interface{}{"values-arm64.yaml"}) {
+		t.Errorf("want chart values file by name, got %v", got)
+	}
+	if got := lookup(app, "spec.destination.namespace"); got != "web" {
+		t.Errorf("want destination namespace web, got %v", got)
+	}
+}
+
+func Test_ExportExecutor_SecretValues(t *testing.T) {
+	values := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(values, []byte("auth:\n  token: abc\n  existingSecret: creds\nresources: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	task := execute.ExecTask{Command: "helm", Args: []string{"upgrade", "--install", "gitea", "openfaas/gitea",
+		"--namespace", "gitea", "--values", values,
+		"--set", "gitea.admin.password=hunter2", "--set", "gitea.admin.username=admin"}}
+
+	t.Run("flux", func(t *testing.T) {
+		docs := exportDocs(t, ExportFlux, task)
+		if len(docs) != 3 {
+			t.Fatalf("want 3 objects, got %d: %v", len(docs), docs)
+		}
+
+		secret := docs[1]
+		wantData := map[string]interface{}{"auth.token": "REPLACE_ME", "gitea.admin.password": "REPLACE_ME"}
+		if secret["kind"] != "Secret" || lookup(secret, "metadata.name") != "gitea-values" || !reflect.DeepEqual(secret["stringData"], wantData) {
+			t.Errorf("want Secret gitea-values with placeholders, got %v", secret)
+		}
+
+		rel := docs[2]
+		wantValues := map[string]interface{}{
+			"auth":      map[string]interface{}{"existingSecret": "creds"},
+			"gitea":     map[string]interface{}{"admin": map[string]interface{}{"username": "admin"}},
+			"resources": map[string]interface{}{},
+		}
+		if got := lookup(rel, "spec.values"); !reflect.DeepEqual(got, wantValues) {
+			t.Errorf("want values without secrets:\n%v\ngot:\n%v", wantValues, got)
+		}
+		wantFrom := []interface{}{
+			map[string]interface{}{"kind": "Secret", "name": "gitea-values", "valuesKey": "auth.token", "targetPath": "auth.token"},
+			map[string]interface{}{"kind": "Secret", "name": "gitea-values", "valuesKey": "gitea.admin.password", "targetPath": "gitea.admin.password"},
+		}
+		if got := lookup(rel, "spec.valuesFrom"); !reflect.DeepEqual(got, wantFrom) {
+			t.Errorf("want valuesFrom:\n%v\ngot:\n%v", wantFrom, got)
+		}
+	})
+
+	t.Run("argocd", func(t *testing.T) {
+		docs := exportDocs(t, ExportArgoCD, task)
+		if len(docs) != 1 {
+			t.Fatalf("want 1 object, got %d: %v", len(docs), docs)
+		}
+
+		values := lookup(docs[0], "spec.source.helm.valuesObject").(map[string]interface{})
+		if got := lookup(values, "gitea.admin.password"); got != "REPLACE_ME" {
+			t.Errorf("want password redacted, got %v", got)
+		}
+		if got := lookup(values, "auth.existingSecret"); got != "creds" {
+			t.Errorf("want existingSecret kept, got %v", got)
+		}
+	})
+}
+
+func Test_ExportExecutor_SetStringAndFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("-----BEGIN CERTIFICATE-----\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	docs := exportDocs(t, ExportFlux,
+		execute.ExecTask{Command: "helm", Cwd: dir, Args: []string{"upgrade", "--install", "openfaas", "openfaas/openfaas",
+			"--namespace", "openfaas", "--set-string", "gateway.replicas=3", "--set-file", "tls.ca=ca.crt"}},
+	)
+
+	wantValues := map[string]interface{}{
+		"gateway": map[string]interface{}{"replicas": "3"},
+		"tls":     map[string]interface{}{"ca": "-----BEGIN CERTIFICATE-----\n"},
+	}
+	if got := lookup(docs[len(docs)-1], "spec.values"); !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("want values:\n%v\ngot:\n%v", wantValues, got)
+	}
+}
+
+func Test_ExportExecutor_Unsupported(t *testing.T) {
+	e, err := NewExportExecutor(ExportFlux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = e.Execute(context.Background(), execute.ExecTask{Command: "kubectl", Args: []string{"delete", "pod", "x"}})
+	if err == nil || !strings.Contains(err.Error(), "unable to export") {
+		t.Errorf("want unable to export error, got %v", err)
+	}
+
+	_, err = e.Execute(context.Background(), execute.ExecTask{Command: "helm", Args: []string{"upgrade", "--install", "x", "unknown/x"}})
+	if err == nil || !strings.Contains(err.Error(), "repository unknown") {
+		t.Errorf("want unknown repository error, got %v", err)
+	}
+
+	e.repos["openfaas"] = "https://openfaas.github.io/faas-netes/"
+	_, err = e.Execute(context.Background(), execute.ExecTask{Command: "helm", Args: []string{"upgrade", "--install", "openfaas", "openfaas/openfaas",
+		"--post-renderer", "/usr/local/bin/arkade", "--post-renderer-args", "helm-post-render"}})
+	if err == nil || !strings.Contains(err.Error(), "--kustomize or --mirror") {
+		t.Errorf("want post-renderer error, got %v", err)
+	}
+
+	if _, err := NewExportExecutor("kustomize"); err == nil {
+		t.Errorf("want error for unsupported format")
+	}
+}
+
+func Test_setValue(t *testing.T) {
+	values := map[string]interface{}{}
+	setValue(values, `nodeSelector.kubernetes\.io/arch`, parseSetValue("arm64"))
+	setValue(values, "replicas", parseSetValue("007"))
+	setValue(values, "enabled", parseSetValue("false"))
+
+	want := map[string]interface{}{
+		"nodeSelector": map[string]interface{}{"kubernetes.io/arch": "arm64"},
+		"replicas":     "007",
+		"enabled":      false,
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+// InfoData is used to render an app's info message, which is a
+// text/template with these functions:
+//
+//	{{ namespace }}                    the app's namespace
+//	{{ flag "domain" }}                the value of a flag
+//	{{ secret "basic-auth" "key" }}    a value from a secret
+//	{{ loadBalancer "gateway" }}       the address of a LoadBalancer
+//	{{ if live }}...{{ end }}          only shown with "arkade info --live"
+//
+// Without Live, secret and loadBalancer render the command to look up the
+// value instead.
+type InfoData struct {
+	// Flags hold the app's flags, from the install command, or the
+	// defaults merged with its install record.
+	Flags map[string]string
+
+	// Live looks up values in the cluster with the k8s.Client.
+	Live bool
+}
+
+// RenderInfo renders an info message with data.
+func RenderInfo(message string, data InfoData) (string, error) {
+	r := &infoRenderer{data: data}
+
+	tmpl, err := template.New("info").Funcs(template.FuncMap{
+		"namespace":    r.namespace,
+		"flag":         r.flag,
+		"secret":       r.secret,
+		"loadBalancer": r.loadBalancer,
+		"live":         func() bool { return data.Live },
+	}).Parse(message)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse info message: %w", err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := tmpl.Execute(out, nil); err != nil {
+		return "", fmt.Errorf("unable to render info message: %w", err)
+	}
+	return out.String(), nil
+}
+
+type infoRenderer struct {
+	data InfoData
+}
+
+func (r *infoRenderer) namespace() string {
+	if ns := r.data.Flags["namespace"]; len(ns) > 0 {
+		return ns
+	}
+	return "default"
+}
+
+func (r *infoRenderer) flag(name string) string {
+	return r.data.Flags[name]
+}
+
+func (r *infoRenderer) secret(name, key string) (string, error) {
+	jsonPath := fmt.Sprintf("{.data.%s}", strings.ReplaceAll(key, ".", `\.`))
+
+	if !r.data.Live {
+		return fmt.Sprintf(`$(kubectl get secret -n %s %s -o jsonpath="%s" | base64 --decode; echo)`,
+			r.namespace(), name, jsonPath), nil
+	}
+
+	encoded, err := k8s.GetClient().Get(context.Background(), r.namespace(), "secret/"+name, jsonPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret %s: %w", name, err)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", fmt.Errorf("unable to decode %s in secret %s: %w", key, name, err)
+	}
+	return "'" + strings.ReplaceAll(string(value), "'", `'\''`) + "'", nil
+}
+
+func (r *infoRenderer) loadBalancer(service string) (string, error) {
+	if !r.data.Live {
+		return fmt.Sprintf(`$(kubectl get svc -n %s %s -o jsonpath="{.status.loadBalancer.ingress[0].ip}")`,
+			r.namespace(), service), nil
+	}
+
+	address, err := k8s.GetClient().Get(context.Background(), r.namespace(), "svc/"+service,
+		"{.status.loadBalancer.ingress[0].ip}{.status.loadBalancer.ingress[0].hostname}")
+	if err != nil {
+		return "", fmt.Errorf("unable to read service %s: %w", service, err)
+	}
+
+	if address = strings.TrimSpace(address); len(address) == 0 {
+		return "<pending>", nil
+	}
+	return address, nil
+}
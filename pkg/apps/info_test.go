@@ -0,0 +1,86 @@
+package apps
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+const testInfoMsg = `kubectl port-forward -n {{ namespace }} svc/gateway 8080:8080 &
+{{- if live }}
+http://{{ loadBalancer "gateway-external" }}:8080
+{{- end }}
+PASSWORD={{ secret "basic-auth" "basic-auth-password" }}`
+
+func Test_RenderInfo_Static(t *testing.T) {
+	got, err := RenderInfo(testInfoMsg, InfoData{Flags: map[string]string{"namespace": "openfaas"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `kubectl port-forward -n openfaas svc/gateway 8080:8080 &
+PASSWORD=$(kubectl get secret -n openfaas basic-auth -o jsonpath="{.data.basic-auth-password}" | base64 --decode; echo)`
+	if got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func Test_RenderInfo_DefaultNamespace(t *testing.T) {
+	got, err := RenderInfo("-n {{ namespace }}", InfoData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "-n default" {
+		t.Errorf("want: %q, got: %q", "-n default", got)
+	}
+}
+
+func Test_RenderInfo_Live(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Fields[`staging/secret/basic-auth {.data.basic-auth-password}`] = "czNjcjN0J3M="
+	client.Fields[`staging/svc/gateway-external {.status.loadBalancer.ingress[0].ip}{.status.loadBalancer.ingress[0].hostname}`] = "203.0.113.10"
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	got, err := RenderInfo(testInfoMsg, InfoData{
+		Flags: map[string]string{"namespace": "staging"},
+		Live:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `kubectl port-forward -n staging svc/gateway 8080:8080 &
+http://203.0.113.10:8080
+PASSWORD='s3cr3t'\''s'`
+	if got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func Test_RenderInfo_LivePendingLoadBalancer(t *testing.T) {
+	k8s.SetClient(k8s.NewFakeClient())
+	defer k8s.SetClient(nil)
+
+	got, err := RenderInfo(`{{ loadBalancer "ingress-nginx-controller" }}`, InfoData{Live: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "<pending>" {
+		t.Errorf("want: %q, got: %q", "<pending>", got)
+	}
+}
+
+func Test_RenderInfo_LiveError(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Err = errors.New("connection refused")
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	_, err := RenderInfo(testInfoMsg, InfoData{Live: true})
+	if err == nil || !strings.Contains(err.Error(), "unable to read service gateway-external") {
+		t.Errorf("want an error reading the service, got: %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
+)
+
+// Ingress is an Ingress record with a TLS certificate from cert-manager,
+// for an app's HTTP service.
+type Ingress struct {
+	// App serves the Service, i.e. "openfaas", and is used for
+	// preflight checks.
+	App string
+
+	// Name is used for the Ingress and the certificate's secret.
+	Name      string
+	Namespace string
+	Service   string
+	Port      int
+
+	// BackendHTTPS is set when the Service serves HTTPS.
+	BackendHTTPS bool
+
+	Domain       string
+	Email        string
+	IngressClass string
+
+	// Issuer is the name of an existing issuer, when empty a
+	// LetsEncrypt issuer is created.
+	Issuer        string
+	Staging       bool
+	ClusterIssuer bool
+
+	// Annotations are added to the Ingress.
+	Annotations map[string]string
+}
+
+// AddIngressFlags adds the flags to create an Ingress with TLS for an
+// app, read them with IngressFromFlags.
+func AddIngressFlags(command *cobra.Command) {
+	command.Flags().StringP("domain", "d", "", "Custom Ingress Domain")
+	command.Flags().StringP("email", "e", "", "Letsencrypt Email")
+	command.Flags().String("ingress-class", "nginx", `Ingress class to be used such as "nginx" or "traefik"`)
+	command.Flags().Bool("staging", false, "set --staging to true to use the staging Letsencrypt issuer")
+	command.Flags().String("issuer", "", "provide the name of a pre-existing issuer, rather than creating one for LetsEncrypt")
+	command.Flags().Bool("cluster-issuer", false, "set to true to create a clusterissuer rather than a namespaces issuer (default: false)")
+}
+
+// IngressFromFlags returns an Ingress for the flags added with
+// AddIngressFlags. It is only Enabled when --domain is set.
+func IngressFromFlags(command *cobra.Command, ingress Ingress) Ingress {
+	ingress.Domain, _ = command.Flags().GetString("domain")
+	ingress.Email, _ = command.Flags().GetString("email")
+	ingress.IngressClass, _ = command.Flags().GetString("ingress-class")
+	ingress.Issuer, _ = command.Flags().GetString("issuer")
+	ingress.Staging, _ = command.Flags().GetBool("staging")
+	ingress.ClusterIssuer, _ = command.Flags().GetBool("cluster-issuer")
+
+	if len(ingress.Namespace) == 0 {
+		ingress.Namespace, _ = command.Flags().GetString("namespace")
+	}
+	return ingress
+}
+
+// Enabled is true when a domain was given for the Ingress.
+func (i Ingress) Enabled() bool {
+	return len(i.Domain) > 0
+}
+
+// Validate checks the flags needed to create the Ingress.
+func (i Ingress) Validate() error {
+	if len(i.Domain) == 0 || (len(i.Email) == 0 && len(i.Issuer) == 0) {
+		return errors.New("both --email and --domain flags should be set and not empty, please set these values")
+	}
+	if len(i.IngressClass) == 0 {
+		return errors.New("--ingress-class must be set")
+	}
+	return nil
+}
+
+// IssuerName is the name of the issuer used for the certificate.
+func (i Ingress) IssuerName() string {
+	if len(i.Issuer) > 0 {
+		return i.Issuer
+	}
+	if i.Staging {
+		return "letsencrypt-staging"
+	}
+	return "letsencrypt-prod"
+}
+
+// IssuerYAML renders a LetsEncrypt Issuer, or ClusterIssuer, which uses
+// the HTTP01 challenge through the ingress class.
+func (i Ingress) IssuerYAML() ([]byte, error) {
+	server := "https://acme-v02.api.letsencrypt.org/directory"
+	if i.Staging {
+		server = "https://acme-staging-v02.api.letsencrypt.org/directory"
+	}
+
+	return renderIngressTemplate("issuer", http01IssuerTemplate, map[string]interface{}{
+		"Name":          i.IssuerName(),
+		"Namespace":     i.Namespace,
+		"ClusterIssuer": i.ClusterIssuer,
+		"Email":         i.Email,
+		"Server":        server,
+		"IngressClass":  i.IngressClass,
+	})
+}
+
+// IngressYAML renders the Ingress, in networking.k8s.io/v1 when the
+// cluster serves it, or extensions/v1beta1 for older clusters.
+func (i Ingress) IngressYAML(hasNetworking bool) ([]byte, error) {
+	annotations := map[string]string{}
+	for k, v := range i.Annotations {
+		annotations[k] = v
+	}
+	if i.BackendHTTPS && i.IngressClass == "nginx" {
+		annotations["nginx.ingress.kubernetes.io/backend-protocol"] = "HTTPS"
+	}
+
+	tmpl := ingressExtensionsTemplate
+	if hasNetworking {
+		tmpl = ingressNetworkingTemplate
+	}
+
+	return renderIngressTemplate("ingress", tmpl, map[string]interface{}{
+		"Name":          i.Name,
+		"Namespace":     i.Namespace,
+		"Domain":        i.Domain,
+		"IngressClass":  i.IngressClass,
+		"IssuerName":    i.IssuerName(),
+		"ClusterIssuer": i.ClusterIssuer,
+		"Service":       i.Service,
+		"Port":          i.Port,
+		"Annotations":   annotations,
+	})
+}
+
+// IngressRequirements are the requirements for an Ingress with a
+// certificate from cert-manager, which can be checked before the app
+// behind it is installed.
+func IngressRequirements(ingressClass string, hasNetworking bool) types.Requirements {
+	requirements := types.Requirements{
+		Apps: []types.Prerequisite{
+			{
+				Name:      "cert-manager",
+				Resources: []string{"crd/certificates.cert-manager.io", "crd/issuers.cert-manager.io"},
+			},
+		},
+	}
+
+	// IngressClass is only served by networking.k8s.io/v1
+	if hasNetworking {
+		controller := types.Prerequisite{
+			Name:      "an IngressController",
+			Resources: []string{"ingressclass/" + ingressClass},
+			Hint:      fmt.Sprintf("an IngressController for the %q ingress class, or set --ingress-class", ingressClass),
+		}
+		switch ingressClass {
+		case "nginx":
+			controller.Hint = "arkade install ingress-nginx"
+		case "traefik":
+			controller.Hint = "arkade install traefik2"
+		}
+		requirements.Apps = append(requirements.Apps, controller)
+	}
+
+	return requirements
+}
+
+// PreflightIngress validates the Ingress and checks for cert-manager and
+// the IngressController, it is run before the app behind the Ingress is
+// installed, so that nothing is left half-installed.
+func PreflightIngress(app string, ingress Ingress) error {
+	if err := ingress.Validate(); err != nil {
+		return err
+	}
+
+	caps, err := k8s.GetCapabilities()
+	if err != nil {
+		return err
+	}
+
+	requirements := IngressRequirements(ingress.IngressClass, caps["networking.k8s.io/v1"])
+	return Preflight(app, &requirements)
+}
+
+// InstallIngress checks that the app's Service exists, then applies the
+// Ingress's issuer, unless an existing one was given, and the Ingress.
+// Run PreflightIngress before installing the app.
+func InstallIngress(app string, ingress Ingress) error {
+	if err := ingress.Validate(); err != nil {
+		return err
+	}
+
+	caps, err := k8s.GetCapabilities()
+	if err != nil {
+		return err
+	}
+	hasNetworking := caps["networking.k8s.io/v1"]
+
+	requirements := types.Requirements{
+		Apps: []types.Prerequisite{
+			{
+				Name:      ingress.App,
+				Namespace: ingress.Namespace,
+				Resources: []string{"service/" + ingress.Service},
+			},
+		},
+	}
+	if err := Preflight(app, &requirements); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client := k8s.GetClient()
+
+	if len(ingress.Issuer) > 0 {
+		fmt.Printf("Using existing issuer: %s\n", ingress.Issuer)
+	} else {
+		issuer, err := ingress.IssuerYAML()
+		if err != nil {
+			return err
+		}
+		if err := client.Apply(ctx, issuer); err != nil {
+			return fmt.Errorf("unable to apply the issuer %s: %w", ingress.IssuerName(), err)
+		}
+	}
+
+	manifest, err := ingress.IngressYAML(hasNetworking)
+	if err != nil {
+		return err
+	}
+	if err := client.Apply(ctx, manifest); err != nil {
+		return fmt.Errorf("unable to apply the ingress %s: %w", ingress.Name, err)
+	}
+
+	fmt.Printf("Ingress %s created for https://%s\n", ingress.Name, ingress.Domain)
+	return nil
+}
+
+func renderIngressTemplate(name, tmpl string, data interface{}) ([]byte, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+const http01IssuerTemplate = `
+apiVersion: cert-manager.io/v1
+{{- if .ClusterIssuer }}
+kind: ClusterIssuer
+{{- else }}
+kind: Issuer
+{{- end }}
+metadata:
+  name: {{.Name}}
+{{- if not .ClusterIssuer }}
+  namespace: {{.Namespace}}
+{{- end }}
+spec:
+  acme:
+    email: {{.Email}}
+    server: {{.Server}}
+    privateKeySecretRef:
+      name: example-issuer-account-key
+    solvers:
+    - selector: {}
+      http01:
+        ingress:
+          class: {{.IngressClass}}`
+
+// Ingress in extensions/v1beta1 are removed in k8s 1.22+, July 2021
+const ingressExtensionsTemplate = `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: {{.Name}}
+  namespace: {{.Namespace}}
+  annotations:
+{{- if .ClusterIssuer }}
+    cert-manager.io/cluster-issuer: {{.IssuerName}}
+{{- else }}
+    cert-manager.io/issuer: {{.IssuerName}}
+{{- end }}
+    kubernetes.io/ingress.class: {{.IngressClass}}
+    cert-manager.io/common-name: {{.Domain}}
+{{- range $key, $value := .Annotations }}
+    {{ $key }}: {{ printf "%q" $value }}
+{{- end }}
+spec:
+  rules:
+  - host: {{.Domain}}
+    http:
+      paths:
+      - backend:
+          serviceName: {{.Service}}
+          servicePort: {{.Port}}
+        path: /
+  tls:
+  - hosts:
+    - {{.Domain}}
+    secretName: {{.Name}}
+`
+
+// Ingress in networking.k8s.io/v1 was added in k8s 1.19+
+// this includes the pathType change added in 1.18
+const ingressNetworkingTemplate = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{.Name}}
+  namespace: {{.Namespace}}
+  annotations:
+{{- if .ClusterIssuer }}
+    cert-manager.io/cluster-issuer: {{.IssuerName}}
+{{- else }}
+    cert-manager.io/issuer: {{.IssuerName}}
+{{- end }}
+    kubernetes.io/ingress.class: {{.IngressClass}}
+    cert-manager.io/common-name: {{.Domain}}
+{{- range $key, $value := .Annotations }}
+    {{ $key }}: {{ printf "%q" $value }}
+{{- end }}
+spec:
+  rules:
+  - host: {{.Domain}}
+    http:
+      paths:
+      - path: /
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: {{.Service}}
+            port:
+              number: {{.Port}}
+  tls:
+  - hosts:
+    - {{.Domain}}
+    secretName: {{.Name}}
+`
@@ -0,0 +1,491 @@
+package apps
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+func Test_IssuerYAML(t *testing.T) {
+	templBytes, _ := Ingress{Email: "openfaas@subdomain.example.com", IngressClass: "traefik", Namespace: "openfaas"}.IssuerYAML()
+	var want = `
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: letsencrypt-prod
+  namespace: openfaas
+spec:
+  acme:
+    email: openfaas@subdomain.example.com
+    server: https://acme-v02.api.letsencrypt.org/directory
+    privateKeySecretRef:
+      name: example-issuer-account-key
+    solvers:
+    - selector: {}
+      http01:
+        ingress:
+          class: traefik`
+
+	got := string(templBytes)
+	if want != got {
+		t.Errorf("want:\n%q\n\ngot:\n%q\n", want, got)
+	}
+}
+
+func Test_IssuerYAML_TakesEmailOverride(t *testing.T) {
+	templBytes, _ := Ingress{Email: "openfaas@subdomain.example.com", IngressClass: "traefik", Namespace: "openfaas"}.IssuerYAML()
+	var want = `
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: letsencrypt-prod
+  namespace: openfaas
+spec:
+  acme:
+    email: openfaas@subdomain.example.com
+    server: https://acme-v02.api.letsencrypt.org/directory
+    privateKeySecretRef:
+      name: example-issuer-account-key
+    solvers:
+    - selector: {}
+      http01:
+        ingress:
+          class: traefik`
+
+	got := string(templBytes)
+	if want != got {
+		t.Errorf("want:\n%q\ngot:\n%q\n", want, got)
+	}
+}
+
+func Test_IssuerYAML_Staging(t *testing.T) {
+	templBytes, _ := Ingress{Email: "openfaas@subdomain.example.com", IngressClass: "traefik", Staging: true, Namespace: "openfaas"}.IssuerYAML()
+	var want = `
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: letsencrypt-staging
+  namespace: openfaas
+spec:
+  acme:
+    email: openfaas@subdomain.example.com
+    server: https://acme-staging-v02.api.letsencrypt.org/directory
+    privateKeySecretRef:
+      name: example-issuer-account-key
+    solvers:
+    - selector: {}
+      http01:
+        ingress:
+          class: traefik`
+
+	got := string(templBytes)
+	if want != got {
+		t.Errorf("want:\n%q\ngot:\n%q\n", want, got)
+	}
+}
+
+func Test_IngressYAML(t *testing.T) {
+	cases := []struct {
+		name          string
+		domain        string
+		email         string
+		ingressClass  string
+		ingressName   string
+		staging       bool
+		clusterIssuer bool
+		issuerName    string
+		namespace     string
+		hasNetworking bool
+		want          string
+	}{
+		{
+			name:          "build staging extensions/v1",
+			domain:        "openfaas.subdomain.example.com",
+			email:         "openfaas@subdomain.example.com",
+			ingressClass:  "traefik",
+			ingressName:   "openfaas-gateway",
+			staging:       true,
+			clusterIssuer: false,
+			issuerName:    "",
+			namespace:     "openfaas",
+			hasNetworking: false,
+			want: `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: openfaas-gateway
+  namespace: openfaas
+  annotations:
+    cert-manager.io/issuer: letsencrypt-staging
+    kubernetes.io/ingress.class: traefik
+    cert-manager.io/common-name: openfaas.subdomain.example.com
+spec:
+  rules:
+  - host: openfaas.subdomain.example.com
+    http:
+      paths:
+      - backend:
+          serviceName: gateway
+          servicePort: 8080
+        path: /
+  tls:
+  - hosts:
+    - openfaas.subdomain.example.com
+    secretName: openfaas-gateway
+      `,
+		},
+		{
+			name:          "build staging networking/v1",
+			domain:        "openfaas.subdomain.example.com",
+			email:         "openfaas@subdomain.example.com",
+			ingressClass:  "traefik",
+			ingressName:   "openfaas-gateway",
+			staging:       true,
+			clusterIssuer: false,
+			issuerName:    "",
+			namespace:     "openfaas",
+			hasNetworking: true,
+			want: `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: openfaas-gateway
+  namespace: openfaas
+  annotations:
+    cert-manager.io/issuer: letsencrypt-staging
+    kubernetes.io/ingress.class: traefik
+    cert-manager.io/common-name: openfaas.subdomain.example.com
+spec:
+  rules:
+  - host: openfaas.subdomain.example.com
+    http:
+      paths:
+      - path: /
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: gateway
+            port:
+              number: 8080
+  tls:
+  - hosts:
+    - openfaas.subdomain.example.com
+    secretName: openfaas-gateway
+      `,
+		},
+		{
+			name:          "build with custom issuer",
+			domain:        "openfaas.example.com",
+			email:         "openfaas@example.com",
+			ingressClass:  "traefik",
+			ingressName:   "openfaas-gateway",
+			staging:       true,
+			clusterIssuer: false,
+			issuerName:    "venafi-tpp",
+			namespace:     "openfaas",
+			hasNetworking: false,
+			want: `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: openfaas-gateway
+  namespace: openfaas
+  annotations:
+    cert-manager.io/issuer: venafi-tpp
+    kubernetes.io/ingress.class: traefik
+    cert-manager.io/common-name: openfaas.example.com
+spec:
+  rules:
+  - host: openfaas.example.com
+    http:
+      paths:
+      - backend:
+          serviceName: gateway
+          servicePort: 8080
+        path: /
+  tls:
+  - hosts:
+    - openfaas.example.com
+    secretName: openfaas-gateway
+  `,
+		},
+		{
+			name:          "build custom issuer networking/v1",
+			domain:        "openfaas.subdomain.example.com",
+			email:         "openfaas@subdomain.example.com",
+			ingressClass:  "traefik",
+			ingressName:   "openfaas-gateway",
+			staging:       true,
+			clusterIssuer: false,
+			issuerName:    "awesome-issuer",
+			namespace:     "openfaas",
+			hasNetworking: true,
+			want: `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: openfaas-gateway
+  namespace: openfaas
+  annotations:
+    cert-manager.io/issuer: awesome-issuer
+    kubernetes.io/ingress.class: traefik
+    cert-manager.io/common-name: openfaas.subdomain.example.com
+spec:
+  rules:
+  - host: openfaas.subdomain.example.com
+    http:
+      paths:
+      - path: /
+        pathType: ImplementationSpecific
+        backend:
+          service:
+            name: gateway
+            port:
+              number: 8080
+  tls:
+  - hosts:
+    - openfaas.subdomain.example.com
+    secretName: openfaas-gateway
+      `,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress := Ingress{
+				Name:          tc.ingressName,
+				Namespace:     tc.namespace,
+				Service:       "gateway",
+				Port:          8080,
+				Domain:        tc.domain,
+				Email:         tc.email,
+				IngressClass:  tc.ingressClass,
+				Issuer:        tc.issuerName,
+				Staging:       tc.staging,
+				ClusterIssuer: tc.clusterIssuer,
+			}
+			result, err := ingress.IngressYAML(tc.hasNetworking)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := string(result)
+			if strings.TrimSpace(tc.want) != strings.TrimSpace(got) {
+				t.Errorf("want:\n%q\ngot:\n%q\n", tc.want, got)
+			}
+
+		})
+	}
+
+}
+
+func Test_IssuerYAML_ClusterIssuerHasNoNamespace(t *testing.T) {
+	templBytes, _ := Ingress{Email: "openfaas@subdomain.example.com", IngressClass: "traefik", Staging: true, ClusterIssuer: true, Namespace: "openfaas"}.IssuerYAML()
+	var want = `
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: letsencrypt-staging
+spec:
+  acme:
+    email: openfaas@subdomain.example.com
+    server: https://acme-staging-v02.api.letsencrypt.org/directory
+    privateKeySecretRef:
+      name: example-issuer-account-key
+    solvers:
+    - selector: {}
+      http01:
+        ingress:
+          class: traefik`
+
+	got := string(templBytes)
+	if want != got {
+		t.Errorf("want:\n%q\ngot:\n%q\n", want, got)
+	}
+}
+
+func Test_IngressYAML_Annotations(t *testing.T) {
+	ingress := Ingress{
+		Name:         "argocd-server",
+		Namespace:    "argocd",
+		Service:      "argocd-server",
+		Port:         443,
+		BackendHTTPS: true,
+		Domain:       "argocd.example.com",
+		IngressClass: "nginx",
+		Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-body-size": "200m",
+		},
+	}
+
+	got, err := ingress.IngressYAML(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `    cert-manager.io/common-name: argocd.example.com
+    nginx.ingress.kubernetes.io/backend-protocol: "HTTPS"
+    nginx.ingress.kubernetes.io/proxy-body-size: "200m"
+spec:`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("want:\n%s\nin:\n%s", want, got)
+	}
+	if !strings.Contains(string(got), "number: 443") {
+		t.Errorf("want port 443 in:\n%s", got)
+	}
+}
+
+func Test_IngressYAML_BackendHTTPSOnlyForNginx(t *testing.T) {
+	ingress := Ingress{Name: "argocd-server", Service: "argocd-server", Port: 443, BackendHTTPS: true,
+		Domain: "argocd.example.com", IngressClass: "traefik"}
+
+	got, err := ingress.IngressYAML(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "backend-protocol") {
+		t.Errorf("want no nginx annotation for traefik, got:\n%s", got)
+	}
+}
+
+func Test_Ingress_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		ingress Ingress
+		wantErr bool
+	}{
+		{name: "domain and email", ingress: Ingress{Domain: "a.example.com", Email: "a@example.com", IngressClass: "nginx"}},
+		{name: "existing issuer needs no email", ingress: Ingress{Domain: "a.example.com", Issuer: "venafi-tpp", IngressClass: "nginx"}},
+		{name: "no email", ingress: Ingress{Domain: "a.example.com", IngressClass: "nginx"}, wantErr: true},
+		{name: "no domain", ingress: Ingress{Email: "a@example.com", IngressClass: "nginx"}, wantErr: true},
+		{name: "no ingress class", ingress: Ingress{Domain: "a.example.com", Email: "a@example.com"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.ingress.Validate()
+			if tc.wantErr != (err != nil) {
+				t.Errorf("want error: %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_InstallIngress_AppliesIssuerAndIngress(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Caps["networking.k8s.io/v1"] = true
+	client.Resources["crd/certificates.cert-manager.io"] = true
+	client.Resources["crd/issuers.cert-manager.io"] = true
+	client.Resources["ingressclass/nginx"] = true
+	client.Resources["grafana/service/grafana"] = true
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	err := InstallIngress("grafana", Ingress{
+		App: "grafana", Name: "grafana", Namespace: "grafana", Service: "grafana", Port: 80,
+		Domain: "grafana.example.com", Email: "a@example.com", IngressClass: "nginx",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.Manifests) != 2 {
+		t.Fatalf("want an issuer and an ingress, got %d manifests", len(client.Manifests))
+	}
+	if !strings.Contains(string(client.Manifests[0]), "kind: Issuer") {
+		t.Errorf("want the issuer first, got:\n%s", client.Manifests[0])
+	}
+	if !strings.Contains(string(client.Manifests[1]), "host: grafana.example.com") {
+		t.Errorf("want the ingress for the domain, got:\n%s", client.Manifests[1])
+	}
+}
+
+func Test_InstallIngress_ExistingIssuer(t *testing.T) {
+	client := k8s.NewFakeClient()
+	SkipPreflight = true
+	k8s.SetClient(client)
+	defer func() {
+		SkipPreflight = false
+		k8s.SetClient(nil)
+	}()
+
+	err := InstallIngress("grafana", Ingress{
+		App: "grafana", Name: "grafana", Namespace: "grafana", Service: "grafana", Port: 80,
+		Domain: "grafana.example.com", Issuer: "venafi-tpp", IngressClass: "nginx",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.Manifests) != 1 {
+		t.Fatalf("want only the ingress, got %d manifests", len(client.Manifests))
+	}
+	if !strings.Contains(string(client.Manifests[0]), "cert-manager.io/issuer: venafi-tpp") {
+		t.Errorf("want the existing issuer, got:\n%s", client.Manifests[0])
+	}
+}
+
+func Test_InstallIngress_PreflightFails(t *testing.T) {
+	client := k8s.NewFakeClient()
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	err := InstallIngress("grafana", Ingress{
+		App: "grafana", Name: "grafana", Namespace: "grafana", Service: "grafana", Port: 80,
+		Domain: "grafana.example.com", Email: "a@example.com", IngressClass: "nginx",
+	})
+
+	var preflightErr *PreflightError
+	if !errors.As(err, &preflightErr) {
+		t.Fatalf("want a PreflightError, got: %v", err)
+	}
+	if len(client.Manifests) > 0 {
+		t.Errorf("want nothing applied, got %d manifests", len(client.Manifests))
+	}
+}
+
+func Test_PreflightIngress(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Caps["networking.k8s.io/v1"] = true
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	ingress := Ingress{
+		App: "grafana", Name: "grafana", Namespace: "grafana", Service: "grafana", Port: 80,
+		Domain: "grafana.example.com", Email: "a@example.com", IngressClass: "nginx",
+	}
+
+	var preflightErr *PreflightError
+	if err := PreflightIngress("grafana", ingress); !errors.As(err, &preflightErr) || len(preflightErr.Failures) != 2 {
+		t.Fatalf("want cert-manager and the IngressController to be missing, got: %v", err)
+	}
+
+	client.Resources["crd/certificates.cert-manager.io"] = true
+	client.Resources["crd/issuers.cert-manager.io"] = true
+	client.Resources["ingressclass/nginx"] = true
+
+	// The app's Service is only created by the install which follows
+	if err := PreflightIngress("grafana", ingress); err != nil {
+		t.Errorf("want no error before the app is installed, got: %v", err)
+	}
+}
+
+func Test_InstallIngress_OnlyChecksBackend(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Resources["grafana/service/grafana"] = true
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	err := InstallIngress("grafana", Ingress{
+		App: "grafana", Name: "grafana", Namespace: "grafana", Service: "grafana", Port: 80,
+		Domain: "grafana.example.com", Email: "a@example.com", IngressClass: "nginx",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Manifests) != 2 {
+		t.Errorf("want an issuer and an ingress, got %d manifests", len(client.Manifests))
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
+)
+
+// SkipPreflight turns off Preflight, set by "arkade install
+// --skip-preflight".
+var SkipPreflight bool
+
+// PreflightError lists each requirement which the cluster does not meet.
+type PreflightError struct {
+	App      string
+	Failures []string
+}
+
+func (e *PreflightError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s cannot be installed, as the cluster does not meet its requirements:\n", e.App)
+	for _, f := range e.Failures {
+		fmt.Fprintf(&sb, "  - %s\n", f)
+	}
+	sb.WriteString("Fix the above, or run again with --skip-preflight to install anyway")
+	return sb.String()
+}
+
+// Preflight checks the cluster against an app's requirements, using the
+// current k8s.Client, and returns a *PreflightError listing everything
+// which needs to be fixed. In a dry-run the failures are printed as
+// warnings instead, since there may be no cluster.
+func Preflight(app string, req *types.Requirements) error {
+	if req == nil || SkipPreflight {
+		return nil
+	}
+
+	failures := CheckRequirements(context.Background(), k8s.GetClient(), *req)
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if k8s.IsDryRun() {
+		for _, f := range failures {
+			fmt.Printf("[dry-run] preflight: %s\n", f)
+		}
+		return nil
+	}
+
+	return &PreflightError{App: app, Failures: failures}
+}
+
+// CheckRequirements returns a message for each requirement which is not
+// met, including any which cannot be checked.
+func CheckRequirements(ctx context.Context, client k8s.Client, req types.Requirements) []string {
+	var failures []string
+
+	if len(req.KubernetesVersion) > 0 {
+		version, err := client.ServerVersion(ctx)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else if minimum := "v" + strings.TrimPrefix(req.KubernetesVersion, "v"); semver.Compare(serverVersion(version), minimum) < 0 {
+			failures = append(failures, fmt.Sprintf("Kubernetes %s or newer is required, but the cluster is running %s", minimum, version))
+		}
+	}
+
+	if len(req.APIs) > 0 {
+		caps, err := client.Capabilities(ctx)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else {
+			for _, api := range req.APIs {
+				if !caps[api] {
+					failures = append(failures, fmt.Sprintf("the API %s is not served by the cluster", api))
+				}
+			}
+		}
+	}
+
+	if len(req.Architectures) > 0 {
+		arch, err := client.NodeArchitecture(ctx)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else if !slices.Contains(req.Architectures, arch) {
+			failures = append(failures, fmt.Sprintf("nodes with the %s architecture are not supported, use one of: %s",
+				arch, strings.Join(req.Architectures, ", ")))
+		}
+	}
+
+	for _, crd := range req.CRDs {
+		found, err := client.Exists(ctx, "", "crd/"+crd)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else if !found {
+			failures = append(failures, fmt.Sprintf("the CustomResourceDefinition %s was not found", crd))
+		}
+	}
+
+	for _, app := range req.Apps {
+		for _, resource := range app.Resources {
+			found, err := client.Exists(ctx, app.Namespace, resource)
+			if err != nil {
+				failures = append(failures, err.Error())
+				break
+			}
+			if !found {
+				hint := app.Hint
+				if len(hint) == 0 {
+					hint = "arkade install " + app.Name
+				}
+				failures = append(failures, fmt.Sprintf("%s is required but was not found (%s), install it with: %s", app.Name, resource, hint))
+				break
+			}
+		}
+	}
+
+	return failures
+}
+
+// serverVersion trims a version such as "v1.29.1-eks-5e0fdde" so that
+// vendor suffixes are not compared as pre-releases.
+func serverVersion(version string) string {
+	if index := strings.IndexAny(version, "-+"); index > -1 {
+		version = version[:index]
+	}
+	return version
+}
@@ -0,0 +1,91 @@
+package apps
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
+)
+
+func Test_CheckRequirements_Met(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Version = "v1.29.1-eks-5e0fdde"
+	client.Caps["networking.k8s.io/v1"] = true
+	client.Resources["crd/certificates.cert-manager.io"] = true
+	client.Resources["ingressclass/nginx"] = true
+
+	req := types.Requirements{
+		KubernetesVersion: "1.19",
+		APIs:              []string{"networking.k8s.io/v1"},
+		Architectures:     []string{"amd64", "arm64"},
+		CRDs:              []string{"certificates.cert-manager.io"},
+		Apps: []types.Prerequisite{
+			{Name: "ingress-nginx", Resources: []string{"ingressclass"}},
+		},
+	}
+
+	if failures := CheckRequirements(context.Background(), client, req); len(failures) > 0 {
+		t.Errorf("want no failures, got: %v", failures)
+	}
+}
+
+func Test_CheckRequirements_NotMet(t *testing.T) {
+	client := k8s.NewFakeClient()
+	client.Version = "v1.18.2+k3s1"
+	client.Arch = "arm"
+
+	req := types.Requirements{
+		KubernetesVersion: "1.19",
+		APIs:              []string{"networking.k8s.io/v1"},
+		Architectures:     []string{"amd64", "arm64"},
+		CRDs:              []string{"certificates.cert-manager.io"},
+		Apps: []types.Prerequisite{
+			{Name: "cert-manager", Resources: []string{"crd/issuers.cert-manager.io"}},
+			{Name: "openfaas", Namespace: "openfaas", Resources: []string{"service/gateway"}, Hint: "arkade install openfaas"},
+		},
+	}
+
+	failures := CheckRequirements(context.Background(), client, req)
+
+	want := []string{
+		"Kubernetes v1.19 or newer is required, but the cluster is running v1.18.2+k3s1",
+		"the API networking.k8s.io/v1 is not served by the cluster",
+		"nodes with the arm architecture are not supported, use one of: amd64, arm64",
+		"the CustomResourceDefinition certificates.cert-manager.io was not found",
+		"cert-manager is required but was not found (crd/issuers.cert-manager.io), install it with: arkade install cert-manager",
+		"openfaas is required but was not found (service/gateway), install it with: arkade install openfaas",
+	}
+	if strings.Join(failures, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want failures:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(failures, "\n"))
+	}
+}
+
+func Test_Preflight(t *testing.T) {
+	client := k8s.NewFakeClient()
+	k8s.SetClient(client)
+	defer k8s.SetClient(nil)
+
+	req := &types.Requirements{
+		Apps: []types.Prerequisite{{Name: "cert-manager", Resources: []string{"crd/certificates.cert-manager.io"}}},
+	}
+
+	err := Preflight("openfaas-ingress", req)
+
+	var preflightErr *PreflightError
+	if !errors.As(err, &preflightErr) || len(preflightErr.Failures) != 1 {
+		t.Fatalf("want a PreflightError with one failure, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "--skip-preflight") {
+		t.Errorf("want --skip-preflight in the message, got %s", err)
+	}
+
+	SkipPreflight = true
+	defer func() { SkipPreflight = false }()
+
+	if err := Preflight("openfaas-ingress", req); err != nil {
+		t.Errorf("want no error with SkipPreflight, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// StartRecording installs a k8s.RecordingExecutor, so that what the app
+// creates in the cluster for kubeconfig can be saved with SaveRecording.
+func StartRecording(app, kubeconfig string) *k8s.RecordingExecutor {
+	cluster := k8s.CurrentCluster(kubeconfig)
+
+	record := &state.Record{
+		App:         app,
+		Context:     cluster.Context,
+		Server:      cluster.Server,
+		InstalledAt: time.Now().UTC(),
+	}
+
+	recorder := k8s.NewRecordingExecutor(record, state.NewStore().ManifestDir(cluster, app))
+	k8s.SetExecutor(recorder)
+
+	return recorder
+}
+
+// SaveRecording stores the record along with the flags which were set
+// on the install command, for "arkade list" and "arkade uninstall".
+func SaveRecording(recorder *k8s.RecordingExecutor, command *cobra.Command) error {
+	record := recorder.Record
+	if record.Empty() {
+		return nil
+	}
+
+	record.Flags = changedFlags(command)
+
+	// Any chart can be installed with "arkade install chart", so record
+	// it under the release name, apart from arkade's apps
+	if record.App == "chart" && len(record.Releases) > 0 {
+		record.Installer = record.App
+		record.App = state.ChartPrefix + record.Releases[0].Name
+	}
+
+	if err := state.NewStore().Save(record); err != nil {
+		return fmt.Errorf("unable to save install record: %w", err)
+	}
+	return nil
+}
+
+// SaveOnFailure saves the recording when the command's RunE fails, as
+// cobra skips PersistentPostRunE after an error. What a failed install
+// created can then still be removed with "arkade uninstall".
+func SaveOnFailure(recorder *k8s.RecordingExecutor, command *cobra.Command) {
+	run := command.RunE
+	if run == nil {
+		return
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) (err error) {
+		defer func() {
+			if err == nil {
+				return
+			}
+			defer k8s.SetExecutor(nil)
+
+			if saveErr := SaveRecording(recorder, cmd); saveErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", saveErr)
+			} else if !recorder.Record.Empty() {
+				fmt.Fprintf(os.Stderr, "The install failed, run \"arkade uninstall %s\" to remove what it created\n", recorder.Record.App)
+			}
+		}()
+
+		return run(cmd, args)
+	}
+}
+
+// pathFlags may give a local file or directory, i.e. a local chart for
+// "arkade install chart --repo-name ./chart".
+var pathFlags = map[string]bool{
+	"repo-name": true,
+	"values":    true,
+	"ca-file":   true,
+	"cert-file": true,
+	"key-file":  true,
+}
+
+// absPath returns the absolute path of a relative path which exists,
+// other values such as "stable/nginx" are returned as they are.
+func absPath(value string) string {
+	if len(value) == 0 || filepath.IsAbs(value) {
+		return value
+	}
+	if _, err := os.Stat(value); err != nil {
+		return value
+	}
+
+	abs, err := filepath.Abs(value)
+	if err != nil {
+		return value
+	}
+	return abs
+}
+
+// sliceValue is implemented by pflag's slice and array flags.
+type sliceValue interface {
+	GetSlice() []string
+}
+
+func changedFlags(command *cobra.Command) map[string][]string {
+	flags := map[string][]string{}
+
+	command.Flags().Visit(func(f *pflag.Flag) {
+		switch f.Name {
+		case "dry-run", "output", "export", "skip-preflight":
+			return
+		}
+
+		values := []string{f.Value.String()}
+		if s, ok := f.Value.(sliceValue); ok {
+			values = s.GetSlice()
+		}
+
+		// Local files are recorded by their absolute path, so that
+		// "arkade upgrade" finds them from any directory
+		if pathFlags[f.Name] {
+			values = slices.Clone(values)
+			for i, value := range values {
+				values[i] = absPath(value)
+			}
+		}
+
+		// Secrets aren't stored, "arkade upgrade" asks for them again
+		if f.Name == "set" || f.Name == "set-string" {
+			values = slices.Clone(values)
+			for i, kv := range values {
+				values[i] = k8s.RedactSet(kv)
+			}
+		}
+		flags[f.Name] = values
+	})
+
+	return flags
+}
@@ -0,0 +1,83 @@
+package apps
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+	execute "github.com/alexellis/go-execute/v2"
+	"github.com/spf13/cobra"
+)
+
+type succeedingExecutor struct{}
+
+func (succeedingExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	return execute.ExecResult{}, nil
+}
+
+func Test_SaveOnFailure_RecordsPartialInstall(t *testing.T) {
+	t.Setenv("ARKADE_HOME", t.TempDir())
+	config.SetKubeContext("edge-1")
+	defer config.SetKubeContext("")
+
+	recorder := StartRecording("openfaas", "")
+	recorder.Executor = succeedingExecutor{}
+	defer k8s.SetExecutor(nil)
+
+	command := &cobra.Command{
+		Use: "openfaas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := k8s.Run(context.Background(), execute.ExecTask{Command: "kubectl", Args: []string{"create", "namespace", "openfaas"}}); err != nil {
+				return err
+			}
+			return errors.New("helm upgrade failed")
+		},
+	}
+	SaveOnFailure(recorder, command)
+	command.SetArgs([]string{})
+	command.SilenceErrors = true
+	command.SilenceUsage = true
+
+	if err := command.Execute(); err == nil {
+		t.Fatal("want the install's error")
+	}
+
+	record, err := state.NewStore().Get(recorder.Record.Cluster(), "openfaas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.Namespaces) != 1 || record.Namespaces[0] != "openfaas" {
+		t.Errorf("want namespace openfaas recorded, got %v", record.Namespaces)
+	}
+	if _, ok := k8s.GetExecutor().(*k8s.RecordingExecutor); ok {
+		t.Errorf("want the executor reset after a failure")
+	}
+}
+
+func Test_changedFlags_LocalChart(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "chart"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	command := &cobra.Command{Use: "chart"}
+	command.Flags().String("repo-name", "", "")
+	command.Flags().StringArray("set", []string{}, "")
+	if err := command.ParseFlags([]string{"--repo-name", "./chart", "--set", "auth.password=s3cr3t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := changedFlags(command)
+	if want := filepath.Join(dir, "chart"); flags["repo-name"][0] != want {
+		t.Errorf("want the chart recorded as %s, got %s", want, flags["repo-name"][0])
+	}
+	if flags["set"][0] != "auth.password="+k8s.Redacted {
+		t.Errorf("want the password redacted, got %s", flags["set"][0])
+	}
+}
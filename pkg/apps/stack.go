@@ -0,0 +1,208 @@
+package apps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Stack is a list of apps to install together, read from a YAML file
+// for "arkade install -f stack.yaml".
+type Stack struct {
+	// Wait passes --wait to every app which others depend upon, so
+	// that each stage is ready before the next starts. It defaults
+	// to true.
+	Wait *bool `yaml:"wait,omitempty"`
+
+	Apps []StackApp `yaml:"apps"`
+}
+
+// StackApp is one app within a Stack.
+type StackApp struct {
+	// Name identifies the entry for depends_on, and is the app to
+	// install unless App is set.
+	Name string `yaml:"name"`
+
+	// App is the arkade app, i.e. "chart" when installing two charts.
+	App string `yaml:"app,omitempty"`
+
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Flags are passed as --name=value, a list repeats the flag.
+	Flags map[string]interface{} `yaml:"flags,omitempty"`
+
+	// Set holds helm overrides passed with --set.
+	Set map[string]string `yaml:"set,omitempty"`
+
+	// Values are values files, relative to the stack file.
+	Values []string `yaml:"values,omitempty"`
+
+	DependsOn []string `yaml:"depends_on,omitempty"`
+
+	// Wait overrides the stack's wait setting for this app.
+	Wait *bool `yaml:"wait,omitempty"`
+}
+
+// AppName returns the arkade app to install.
+func (a StackApp) AppName() string {
+	if len(a.App) > 0 {
+		return a.App
+	}
+	return a.Name
+}
+
+// LoadStack reads and validates a stack file, values files are made
+// relative to the directory of the file.
+func LoadStack(file string) (*Stack, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := &Stack{}
+	if err := yaml.Unmarshal(data, stack); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", file, err)
+	}
+
+	if len(stack.Apps) == 0 {
+		return nil, fmt.Errorf("no apps found in %s", file)
+	}
+
+	seen := map[string]bool{}
+	for i, app := range stack.Apps {
+		if len(app.Name) == 0 {
+			return nil, fmt.Errorf("app %d in %s has no name", i+1, file)
+		}
+		if seen[app.Name] {
+			return nil, fmt.Errorf("app %q is listed more than once, set a unique name and use \"app\" to install it twice", app.Name)
+		}
+		seen[app.Name] = true
+
+		for j, values := range app.Values {
+			if !filepath.IsAbs(values) && !strings.Contains(values, "://") {
+				stack.Apps[i].Values[j] = filepath.Join(filepath.Dir(file), values)
+			}
+		}
+	}
+
+	for _, app := range stack.Apps {
+		for _, dep := range app.DependsOn {
+			if !seen[dep] {
+				return nil, fmt.Errorf("app %q depends on %q, which is not in the stack", app.Name, dep)
+			}
+		}
+	}
+
+	return stack, nil
+}
+
+// Stages orders the apps so that each app comes after those it
+// depends on. Apps within a stage keep the order of the file.
+func (s *Stack) Stages() ([][]StackApp, error) {
+	done := map[string]bool{}
+	var stages [][]StackApp
+
+	for len(done) < len(s.Apps) {
+		var stage []StackApp
+		for _, app := range s.Apps {
+			if done[app.Name] {
+				continue
+			}
+
+			ready := true
+			for _, dep := range app.DependsOn {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				stage = append(stage, app)
+			}
+		}
+
+		if len(stage) == 0 {
+			var pending []string
+			for _, app := range s.Apps {
+				if !done[app.Name] {
+					pending = append(pending, app.Name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between: %s", strings.Join(pending, ", "))
+		}
+
+		for _, app := range stage {
+			done[app.Name] = true
+		}
+		stages = append(stages, stage)
+	}
+
+	return stages, nil
+}
+
+// ShouldWait returns whether to pass --wait to an app, which is the
+// default for any app others depend upon.
+func (s *Stack) ShouldWait(app StackApp) bool {
+	if app.Wait != nil {
+		return *app.Wait
+	}
+	if s.Wait != nil && !*s.Wait {
+		return false
+	}
+
+	for _, other := range s.Apps {
+		for _, dep := range other.DependsOn {
+			if dep == app.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Args returns the command-line for the app's installer, with flags
+// sorted so that the command is the same on every run.
+func (a StackApp) Args() []string {
+	args := []string{a.AppName()}
+
+	if len(a.Namespace) > 0 {
+		args = append(args, "--namespace", a.Namespace)
+	}
+
+	names := make([]string, 0, len(a.Flags))
+	for name := range a.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch v := a.Flags[name].(type) {
+		case []interface{}:
+			for _, item := range v {
+				args = append(args, fmt.Sprintf("--%s=%v", name, item))
+			}
+		default:
+			args = append(args, fmt.Sprintf("--%s=%v", name, v))
+		}
+	}
+
+	keys := make([]string, 0, len(a.Set))
+	for k := range a.Set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		args = append(args, "--set", k+"="+a.Set[k])
+	}
+
+	for _, values := range a.Values {
+		args = append(args, "--values", values)
+	}
+
+	return args
+}
@@ -0,0 +1,128 @@
+package apps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStack(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "stack.yaml")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func Test_LoadStack_Stages(t *testing.T) {
+	file := writeStack(t, `apps:
+  - name: openfaas-ingress
+    depends_on: [openfaas, ingress-nginx]
+    flags:
+      domain: gw.example.com
+      email: admin@example.com
+  - name: openfaas
+    depends_on: [cert-manager]
+    flags:
+      gateways: 2
+      load-balancer: false
+    set:
+      gateway.upstreamTimeout: 60s
+    values:
+      - openfaas.yaml
+  - name: cert-manager
+  - name: ingress-nginx
+`)
+
+	stack, err := LoadStack(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := stack.Stages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, stage := range stages {
+		var names []string
+		for _, app := range stage {
+			names = append(names, app.Name)
+		}
+		got = append(got, strings.Join(names, ","))
+	}
+
+	want := []string{"cert-manager,ingress-nginx", "openfaas", "openfaas-ingress"}
+	if strings.Join(got, " | ") != strings.Join(want, " | ") {
+		t.Fatalf("want stages %v, got %v", want, got)
+	}
+
+	openfaas := stages[1][0]
+	wantArgs := []string{"openfaas", "--gateways=2", "--load-balancer=false",
+		"--set", "gateway.upstreamTimeout=60s",
+		"--values", filepath.Join(filepath.Dir(file), "openfaas.yaml")}
+	if strings.Join(openfaas.Args(), " ") != strings.Join(wantArgs, " ") {
+		t.Fatalf("want args %v, got %v", wantArgs, openfaas.Args())
+	}
+
+	if !stack.ShouldWait(openfaas) {
+		t.Fatal("want --wait for openfaas, as openfaas-ingress depends on it")
+	}
+	if stack.ShouldWait(stages[2][0]) {
+		t.Fatal("want no --wait for openfaas-ingress, nothing depends on it")
+	}
+}
+
+func Test_LoadStack_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no apps",
+			content: "apps: []\n",
+			want:    "no apps found",
+		},
+		{
+			name:    "duplicate",
+			content: "apps:\n  - name: openfaas\n  - name: openfaas\n",
+			want:    "listed more than once",
+		},
+		{
+			name:    "missing dependency",
+			content: "apps:\n  - name: openfaas\n    depends_on: [cert-manager]\n",
+			want:    `depends on "cert-manager", which is not in the stack`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadStack(writeStack(t, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("want error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func Test_Stack_Cycle(t *testing.T) {
+	stack, err := LoadStack(writeStack(t, `apps:
+  - name: a
+    depends_on: [b]
+  - name: b
+    depends_on: [a]
+  - name: c
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stack.Stages(); err == nil || !strings.Contains(err.Error(), "dependency cycle between: a, b") {
+		t.Fatalf("want cycle error, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/alexellis/arkade/pkg/helm"
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+)
+
+// UninstallOptions control how an app is removed.
+type UninstallOptions struct {
+	// KeepNamespaces leaves namespaces in place, including those
+	// created by applied manifests.
+	KeepNamespaces bool
+	Wait           bool
+}
+
+// protectedNamespaces are never deleted, even when an install record
+// claims them.
+var protectedNamespaces = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// Uninstall removes what an install record says was created, in the
+// reverse order: helm releases, manifests, secrets then namespaces.
+// Resources which no longer exist are skipped.
+func Uninstall(record *state.Record, opts UninstallOptions) error {
+	for i := len(record.Releases) - 1; i >= 0; i-- {
+		rel := record.Releases[i]
+		fmt.Printf("Removing helm release %s/%s\n", rel.Namespace, rel.Name)
+
+		if err := helm.Uninstall(rel.Name, rel.Namespace, opts.Wait); err != nil {
+			if !errors.Is(err, helm.ErrNotFound) {
+				return err
+			}
+			fmt.Printf("[Warning] helm release %s not found\n", rel.Name)
+		}
+	}
+
+	for i := len(record.Manifests) - 1; i >= 0; i-- {
+		if err := deleteManifest(record.Manifests[i], opts.KeepNamespaces); err != nil {
+			return err
+		}
+	}
+
+	for _, secret := range record.Secrets {
+		fmt.Printf("Removing secret %s/%s\n", secret.Namespace, secret.Name)
+		if err := k8s.Kubectl("delete", "secret", secret.Name, "--namespace", secret.Namespace, "--ignore-not-found"); err != nil {
+			return err
+		}
+	}
+
+	if opts.KeepNamespaces {
+		return nil
+	}
+
+	for i := len(record.Namespaces) - 1; i >= 0; i-- {
+		ns := record.Namespaces[i]
+		if protectedNamespaces[ns] {
+			continue
+		}
+
+		fmt.Printf("Removing namespace %s\n", ns)
+		if err := k8s.Kubectl("delete", "namespace", ns, "--ignore-not-found"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteManifest(manifest state.Manifest, keepNamespaces bool) error {
+	fmt.Printf("Removing resources from %s\n", manifest.Source)
+
+	args := []string{"delete", "--ignore-not-found"}
+	if len(manifest.Namespace) > 0 {
+		args = append(args, "--namespace", manifest.Namespace)
+	}
+
+	if manifest.Kustomize {
+		return k8s.Kubectl(append(args, "-k", manifest.Source)...)
+	}
+
+	if keepNamespaces && !strings.Contains(manifest.Source, "://") {
+		data, err := os.ReadFile(manifest.Source)
+		if err != nil {
+			return err
+		}
+
+		return k8s.KubectlIn(strings.NewReader(withoutNamespaces(string(data))), append(args, "-f", "-")...)
+	}
+
+	return k8s.Kubectl(append(args, "-f", manifest.Source)...)
+}
+
+var namespaceKind = regexp.MustCompile(`(?m)^kind:\s*Namespace\s*$`)
+
+// withoutNamespaces removes Namespace documents from a manifest.
+func withoutNamespaces(manifest string) string {
+	var kept []string
+	for _, doc := range strings.Split(manifest, "\n---") {
+		if !namespaceKind.MatchString(doc) {
+			kept = append(kept, doc)
+		}
+	}
+	return strings.Join(kept, "\n---")
+}
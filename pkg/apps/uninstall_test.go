@@ -0,0 +1,89 @@
+package apps
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+)
+
+func Test_Uninstall_Order(t *testing.T) {
+	tests := []struct {
+		name           string
+		keepNamespaces bool
+		want           []string
+	}{
+		{
+			name: "removes namespaces",
+			want: []string{
+				"helm uninstall openfaas --namespace openfaas",
+				"kubectl delete --ignore-not-found -f https://example.com/namespaces.yml",
+				"kubectl delete secret basic-auth --namespace openfaas --ignore-not-found",
+				"kubectl delete namespace openfaas --ignore-not-found",
+			},
+		},
+		{
+			name:           "keeps namespaces",
+			keepNamespaces: true,
+			want: []string{
+				"helm uninstall openfaas --namespace openfaas",
+				"kubectl delete --ignore-not-found -f https://example.com/namespaces.yml",
+				"kubectl delete secret basic-auth --namespace openfaas --ignore-not-found",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			d, err := k8s.NewDryRunExecutor(out, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			k8s.SetExecutor(d)
+			defer k8s.SetExecutor(nil)
+
+			record := &state.Record{
+				App:        "openfaas",
+				Releases:   []state.Release{{Name: "openfaas", Namespace: "openfaas"}},
+				Manifests:  []state.Manifest{{Source: "https://example.com/namespaces.yml"}},
+				Secrets:    []state.Resource{{Name: "basic-auth", Namespace: "openfaas"}},
+				Namespaces: []string{"openfaas", "default"},
+			}
+
+			if err := Uninstall(record, UninstallOptions{KeepNamespaces: tc.keepNamespaces}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, "helm ") || strings.HasPrefix(line, "kubectl ") {
+					got = append(got, line)
+				}
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Fatalf("want:\n%s\ngot:\n%s", strings.Join(tc.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func Test_withoutNamespaces(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Namespace
+metadata:
+  name: openfaas
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+	got := withoutNamespaces(manifest)
+	if strings.Contains(got, "kind: Namespace") || !strings.Contains(got, "kind: ConfigMap") {
+		t.Fatalf("unexpected manifest:\n%s", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/state"
+)
+
+// upgradeIgnoredFlags are not carried over from an install record, as
+// they describe how the install was run rather than the app.
+var upgradeIgnoredFlags = map[string]bool{
+	"kubeconfig":     true,
+	"context":        true,
+	"all-contexts":   true,
+	"selector":       true,
+	"wait-timeout":   true,
+	"dry-run":        true,
+	"output":         true,
+	"export":         true,
+	"skip-preflight": true,
+}
+
+// UpgradeFlags merges the flags recorded for an install with changes
+// for an upgrade. Values for --set are merged by key, so that only the
+// keys given are replaced, other flags replace the recorded value.
+func UpgradeFlags(recorded, changes map[string][]string) map[string][]string {
+	merged := map[string][]string{}
+	for name, values := range recorded {
+		if !upgradeIgnoredFlags[name] {
+			merged[name] = values
+		}
+	}
+
+	for name, values := range changes {
+		if name == "set" {
+			merged[name] = mergeSet(merged[name], values)
+			continue
+		}
+		merged[name] = values
+	}
+
+	return merged
+}
+
+// mergeSet replaces or appends each key=value, keeping the order of the
+// original keys.
+func mergeSet(existing, changes []string) []string {
+	merged := append([]string{}, existing...)
+
+	for _, change := range changes {
+		key, _, _ := strings.Cut(change, "=")
+
+		replaced := false
+		for i, kv := range merged {
+			if k, _, _ := strings.Cut(kv, "="); k == key {
+				merged[i] = change
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, change)
+		}
+	}
+
+	return merged
+}
+
+// FillRedacted replaces each --set and --set-string value which was
+// redacted when it was recorded with the value returned for its key.
+func FillRedacted(flags map[string][]string, value func(key string) (string, error)) error {
+	for _, name := range []string{"set", "set-string"} {
+		values := slices.Clone(flags[name])
+		for i, kv := range values {
+			k, v, _ := strings.Cut(kv, "=")
+			if v != k8s.Redacted {
+				continue
+			}
+
+			secret, err := value(k)
+			if err != nil {
+				return err
+			}
+			values[i] = k + "=" + secret
+		}
+		if values != nil {
+			flags[name] = values
+		}
+	}
+	return nil
+}
+
+// UpgradeArgs returns the command-line to re-run the installer for a
+// record with the merged flags, sorted so that the command is the same
+// on every run.
+func UpgradeArgs(record *state.Record, flags map[string][]string) []string {
+	installer := record.Installer
+	if len(installer) == 0 {
+		installer = record.App
+	}
+
+	args := []string{installer}
+	for _, name := range sortedKeys(flags) {
+		for _, value := range flags[name] {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	return args
+}
+
+// FlagsDiff describes how the flags for an upgrade differ from those
+// recorded, with one line per change prefixed by "+", "-" or "~". Values
+// for --set keys which look like secrets are redacted, so a new secret
+// is only shown as changed.
+func FlagsDiff(recorded, upgraded map[string][]string) []string {
+	before := flatten(recorded)
+	after := flatten(upgraded)
+
+	keys := map[string]bool{}
+	for k := range before {
+		if !upgradeIgnoredFlags[strings.SplitN(k, " ", 2)[0]] {
+			keys[k] = true
+		}
+	}
+	for k := range after {
+		keys[k] = true
+	}
+
+	var lines []string
+	for _, k := range sortedKeys(keys) {
+		old, hadOld := before[k]
+		value, hasNew := after[k]
+
+		switch {
+		case hadOld && !hasNew:
+			lines = append(lines, fmt.Sprintf("- --%s=%s", k, redactFlag(k, old)))
+		case !hadOld && hasNew:
+			lines = append(lines, fmt.Sprintf("+ --%s=%s", k, redactFlag(k, value)))
+		case old == value:
+		case redactFlag(k, value) == k8s.Redacted:
+			lines = append(lines, fmt.Sprintf("~ --%s=%s (changed)", k, k8s.Redacted))
+		default:
+			lines = append(lines, fmt.Sprintf("~ --%s: %s => %s", k, old, value))
+		}
+	}
+	return lines
+}
+
+// flatten indexes flags by name, or by "set KEY" for each --set and
+// --set-string value, with repeated flags joined by commas.
+func flatten(flags map[string][]string) map[string]string {
+	out := map[string]string{}
+	for name, values := range flags {
+		if name == "set" || name == "set-string" {
+			for _, kv := range values {
+				k, v, _ := strings.Cut(kv, "=")
+				out[name+" "+k] = v
+			}
+			continue
+		}
+		out[name] = strings.Join(values, ",")
+	}
+	return out
+}
+
+// redactFlag redacts a value indexed by flatten, when it's for a --set
+// or --set-string key which looks like a secret.
+func redactFlag(k, value string) string {
+	name, key, ok := strings.Cut(k, " ")
+	if !ok || (name != "set" && name != "set-string") {
+		return value
+	}
+
+	_, redacted, _ := strings.Cut(k8s.RedactSet(key+"="+value), "=")
+	return redacted
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
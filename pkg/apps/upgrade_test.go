@@ -0,0 +1,134 @@
+package apps
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/state"
+)
+
+func Test_UpgradeFlags(t *testing.T) {
+	recorded := map[string][]string{
+		"gateways":     {"2"},
+		"kubeconfig":   {"/home/user/.kube/old"},
+		"context":      {"edge-1"},
+		"all-contexts": {"true"},
+		"selector":     {"env=prod"},
+		"wait-timeout": {"5m0s"},
+		"set":          {"gateway.replicas=1", "faasnetes.imagePullPolicy=Always"},
+	}
+	changes := map[string][]string{
+		"gateways":      {"3"},
+		"chart-version": {"14.2.0"},
+		"set":           {"gateway.replicas=3", "queueWorker.replicas=2"},
+	}
+
+	got := UpgradeFlags(recorded, changes)
+	want := map[string][]string{
+		"gateways":      {"3"},
+		"chart-version": {"14.2.0"},
+		"set":           {"gateway.replicas=3", "faasnetes.imagePullPolicy=Always", "queueWorker.replicas=2"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if recorded["set"][0] != "gateway.replicas=1" {
+		t.Errorf("want recorded flags unchanged, got %v", recorded["set"])
+	}
+}
+
+func Test_FlagsDiff(t *testing.T) {
+	recorded := map[string][]string{
+		"gateways":   {"2"},
+		"kubeconfig": {"/home/user/.kube/old"},
+		"operator":   {"false"},
+		"set":        {"gateway.replicas=1"},
+	}
+	upgraded := map[string][]string{
+		"gateways":      {"3"},
+		"chart-version": {"14.2.0"},
+		"set":           {"gateway.replicas=3", "queueWorker.replicas=2"},
+	}
+
+	want := []string{
+		"+ --chart-version=14.2.0",
+		"~ --gateways: 2 => 3",
+		"- --operator=false",
+		"~ --set gateway.replicas: 1 => 3",
+		"+ --set queueWorker.replicas=2",
+	}
+	if got := FlagsDiff(recorded, upgraded); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want diff:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if got := FlagsDiff(recorded, UpgradeFlags(recorded, nil)); len(got) != 0 {
+		t.Errorf("want no diff without changes, got %v", got)
+	}
+}
+
+func Test_FlagsDiff_RedactsSecrets(t *testing.T) {
+	recorded := map[string][]string{
+		"set":        {"basicAuth.password=REDACTED"},
+		"set-string": {"registry.token=old"},
+	}
+	upgraded := map[string][]string{
+		"set":        {"basicAuth.password=s3cr3t", "admin.password=hunter2"},
+		"set-string": {"registry.token=new"},
+	}
+
+	want := []string{
+		"+ --set admin.password=REDACTED",
+		"~ --set basicAuth.password=REDACTED (changed)",
+		"~ --set-string registry.token=REDACTED (changed)",
+	}
+	got := FlagsDiff(recorded, upgraded)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want diff:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if got := FlagsDiff(recorded, UpgradeFlags(recorded, nil)); len(got) != 0 {
+		t.Errorf("want no diff without changes, got %v", got)
+	}
+}
+
+func Test_FillRedacted(t *testing.T) {
+	flags := map[string][]string{
+		"set":        {"gateway.replicas=3", "basicAuth.password=REDACTED"},
+		"set-string": {"registry.token=REDACTED"},
+	}
+
+	var asked []string
+	err := FillRedacted(flags, func(key string) (string, error) {
+		asked = append(asked, key)
+		return "s3cr3t", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"set":        {"gateway.replicas=3", "basicAuth.password=s3cr3t"},
+		"set-string": {"registry.token=s3cr3t"},
+	}
+	if !reflect.DeepEqual(flags, want) {
+		t.Errorf("want %v, got %v", want, flags)
+	}
+	if strings.Join(asked, ",") != "basicAuth.password,registry.token" {
+		t.Errorf("want each redacted key asked for once, got %v", asked)
+	}
+}
+
+func Test_UpgradeArgs(t *testing.T) {
+	record := &state.Record{App: "nginx", Installer: "chart"}
+	flags := map[string][]string{
+		"repo-name": {"bitnami/nginx"},
+		"set":       {"replicaCount=2", "service.type=NodePort"},
+	}
+
+	want := []string{"chart", "--repo-name=bitnami/nginx", "--set=replicaCount=2", "--set=service.type=NodePort"}
+	if got := UpgradeArgs(record, flags); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
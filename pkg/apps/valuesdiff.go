@@ -0,0 +1,263 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	execute "github.com/alexellis/go-execute/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+// ValuesDiffExecutor compares the values each helm release would be
+// upgraded with against those of the release in the cluster, instead of
+// changing the cluster. The new values are rendered with "helm upgrade
+// --dry-run", and the current ones are the release's values from "helm
+// get values" over the defaults of its chart version from "helm show
+// values". Values of sub-charts are only compared when they are set
+// for the release.
+//
+// Read-only commands still run, any other command is skipped.
+type ValuesDiffExecutor struct {
+	// Executor runs the read-only commands, and the helm dry-runs.
+	Executor k8s.Executor
+
+	mu    sync.Mutex
+	diffs []ValuesDiff
+}
+
+// ValuesDiff is how the values of one helm release would change.
+type ValuesDiff struct {
+	Release   string
+	Namespace string
+
+	// Lines has one line per value, as for FlagsDiff.
+	Lines []string
+}
+
+// NewValuesDiffExecutor creates a ValuesDiffExecutor which runs
+// commands on the local machine.
+func NewValuesDiffExecutor() *ValuesDiffExecutor {
+	return &ValuesDiffExecutor{
+		Executor: k8s.TaskExecutor{},
+	}
+}
+
+// DryRun is always true, as nothing is changed in the cluster.
+func (e *ValuesDiffExecutor) DryRun() bool {
+	return true
+}
+
+// Diffs returns a ValuesDiff for each helm release, in the order they
+// would be upgraded.
+func (e *ValuesDiffExecutor) Diffs() []ValuesDiff {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return slices.Clone(e.diffs)
+}
+
+func (e *ValuesDiffExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	if k8s.IsReadOnly(task.Command, task.Args) {
+		return e.Executor.Execute(ctx, task)
+	}
+
+	positional, flags := k8s.SplitArgs(task.Args)
+	if filepath.Base(task.Command) != "helm" || len(positional) < 3 || (positional[0] != "upgrade" && positional[0] != "install") {
+		return execute.ExecResult{}, nil
+	}
+
+	diff, err := e.diffRelease(ctx, task, positional[1], flags["--namespace"], positional[2])
+	if err != nil {
+		return execute.ExecResult{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.diffs = append(e.diffs, diff)
+	return execute.ExecResult{}, nil
+}
+
+// renderedRelease is the part of a release printed by "helm upgrade
+// --dry-run --output=json" which holds its values.
+type renderedRelease struct {
+	Chart struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Values map[string]interface{} `json:"values"`
+	} `json:"chart"`
+	Config map[string]interface{} `json:"config"`
+}
+
+func (e *ValuesDiffExecutor) diffRelease(ctx context.Context, task execute.ExecTask, name, namespace, chart string) (ValuesDiff, error) {
+	render := task
+	render.Args = append(slices.Clone(task.Args), "--dry-run", "--output=json")
+	render.StreamStdio = false
+
+	res, err := e.Executor.Execute(ctx, render)
+	if err != nil {
+		return ValuesDiff{}, err
+	}
+	if res.ExitCode != 0 {
+		return ValuesDiff{}, fmt.Errorf("unable to render the values for %s: %s", name, strings.TrimSpace(res.Stderr))
+	}
+
+	var rendered renderedRelease
+	if err := json.Unmarshal([]byte(res.Stdout), &rendered); err != nil {
+		return ValuesDiff{}, fmt.Errorf("unable to read the values for %s: %w", name, err)
+	}
+
+	upgraded := map[string]interface{}{}
+	mergeValues(upgraded, rendered.Chart.Values)
+	mergeValues(upgraded, rendered.Config)
+
+	current, err := releaseValues(ctx, task, name, namespace, chart, rendered.Chart.Metadata.Name)
+	if err != nil {
+		return ValuesDiff{}, err
+	}
+
+	return ValuesDiff{
+		Release:   name,
+		Namespace: namespace,
+		Lines:     valuesDiffLines(current, upgraded),
+	}, nil
+}
+
+// releaseValues returns the values of an installed release, over the
+// defaults of the version of the chart it was installed from. They are
+// empty when the release is not installed.
+func releaseValues(ctx context.Context, task execute.ExecTask, name, namespace, chart, chartName string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	helm := func(args ...string) (string, error) {
+		res, err := k8s.Run(ctx, execute.ExecTask{
+			Command: task.Command,
+			Args:    args,
+			Env:     task.Env,
+			Cwd:     task.Cwd,
+		})
+		if err != nil {
+			return "", err
+		}
+		if res.ExitCode != 0 {
+			return "", fmt.Errorf("unable to run helm %s: %s", args[0], strings.TrimSpace(res.Stderr))
+		}
+		return res.Stdout, nil
+	}
+
+	out, err := helm("list", "--namespace", namespace, "--filter", "^"+name+"$", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var releases []struct {
+		Name  string `json:"name"`
+		Chart string `json:"chart"`
+	}
+	if err := json.Unmarshal([]byte(out), &releases); err != nil {
+		return nil, fmt.Errorf("unable to read the releases in %s: %w", namespace, err)
+	}
+	if len(releases) == 0 {
+		return values, nil
+	}
+
+	// The chart is listed as "NAME-VERSION", i.e. "openfaas-14.1.0"
+	version := strings.TrimPrefix(releases[0].Chart, chartName+"-")
+
+	out, err = helm("show", "values", chart, "--version", version)
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(out), &defaults); err != nil {
+		return nil, fmt.Errorf("unable to read the values of %s %s: %w", chart, version, err)
+	}
+	mergeValues(values, defaults)
+
+	out, err = helm("get", "values", name, "--namespace", namespace, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	set := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(out), &set); err != nil {
+		return nil, fmt.Errorf("unable to read the values of %s: %w", name, err)
+	}
+	mergeValues(values, set)
+
+	return values, nil
+}
+
+// valuesDiffLines describes each changed value by its path, prefixed by
+// "+", "-" or "~".
+func valuesDiffLines(current, upgraded map[string]interface{}) []string {
+	before := map[string]string{}
+	flattenValues(current, "", before)
+	after := map[string]string{}
+	flattenValues(upgraded, "", after)
+
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+
+	var lines []string
+	for _, k := range sortedKeys(keys) {
+		old, hadOld := before[k]
+		value, hasNew := after[k]
+
+		switch {
+		case hadOld && !hasNew:
+			lines = append(lines, fmt.Sprintf("- %s: %s", k, old))
+		case !hadOld && hasNew:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", k, value))
+		case old != value:
+			lines = append(lines, fmt.Sprintf("~ %s: %s => %s", k, old, value))
+		}
+	}
+	return lines
+}
+
+// flattenValues indexes each value by its path, i.e. "gateway.replicas",
+// with lists and empty maps kept whole. A null value removes a default,
+// as it does for helm.
+func flattenValues(values map[string]interface{}, prefix string, out map[string]string) {
+	for k, v := range values {
+		path := strings.ReplaceAll(k, ".", `\.`)
+		if len(prefix) > 0 {
+			path = prefix + "." + path
+		}
+
+		switch value := v.(type) {
+		case nil:
+		case map[string]interface{}:
+			if len(value) > 0 {
+				flattenValues(value, path, out)
+				continue
+			}
+			out[path] = "{}"
+		case string:
+			out[path] = value
+		default:
+			// Numbers are read as float64 from JSON, and int from YAML
+			data, err := json.Marshal(value)
+			if err != nil {
+				data = []byte(fmt.Sprint(value))
+			}
+			out[path] = string(bytes.TrimSpace(data))
+		}
+	}
+}
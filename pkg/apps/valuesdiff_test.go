@@ -0,0 +1,106 @@
+package apps
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	execute "github.com/alexellis/go-execute/v2"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+// helmOutputs returns canned output for each helm command, by the start
+// of its arguments.
+type helmOutputs struct {
+	outputs map[string]string
+	tasks   []string
+}
+
+func (h *helmOutputs) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	args := strings.Join(task.Args, " ")
+	h.tasks = append(h.tasks, args)
+
+	for prefix, out := range h.outputs {
+		if strings.HasPrefix(args, prefix) {
+			return execute.ExecResult{Stdout: out}, nil
+		}
+	}
+	return execute.ExecResult{ExitCode: 1, Stderr: "unexpected command: " + args}, nil
+}
+
+func Test_ValuesDiffExecutor(t *testing.T) {
+	rendered := `{"name": "openfaas", "chart": {"metadata": {"name": "openfaas", "version": "14.2.0"},
+  "values": {"gateway": {"replicas": 1, "image": "gateway:0.27.0"}, "queueWorker": {"replicas": 1}, "psp": false}},
+  "config": {"gateway": {"replicas": 3}, "psp": null}}`
+
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		want    []string
+	}{
+		{
+			name: "installed",
+			outputs: map[string]string{
+				"list --namespace openfaas --filter ^openfaas$":      `[{"name": "openfaas", "chart": "openfaas-14.1.0"}]`,
+				"show values openfaas/openfaas --version 14.1.0":     "gateway:\n  replicas: 1\n  image: gateway:0.26.0\npsp: true\n",
+				"get values openfaas --namespace openfaas":           `{"gateway": {"replicas": 2}}`,
+				"upgrade --install openfaas openfaas/openfaas --nam": rendered,
+			},
+			want: []string{
+				"~ gateway.image: gateway:0.26.0 => gateway:0.27.0",
+				"~ gateway.replicas: 2 => 3",
+				"- psp: true",
+				"+ queueWorker.replicas: 1",
+			},
+		},
+		{
+			name: "not installed",
+			outputs: map[string]string{
+				"list --namespace openfaas --filter ^openfaas$":      `[]`,
+				"upgrade --install openfaas openfaas/openfaas --nam": rendered,
+			},
+			want: []string{
+				"+ gateway.image: gateway:0.27.0",
+				"+ gateway.replicas: 3",
+				"+ queueWorker.replicas: 1",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			helm := &helmOutputs{outputs: tc.outputs}
+			e := NewValuesDiffExecutor()
+			e.Executor = helm
+			k8s.SetExecutor(e)
+			defer k8s.SetExecutor(nil)
+
+			ctx := context.Background()
+			if _, err := k8s.Run(ctx, execute.ExecTask{Command: "kubectl", Args: []string{"create", "namespace", "openfaas"}}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := k8s.Run(ctx, execute.ExecTask{Command: "helm", Args: []string{"upgrade", "--install", "openfaas", "openfaas/openfaas",
+				"--namespace", "openfaas", "--set", "gateway.replicas=3"}}); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, task := range helm.tasks {
+				if strings.HasPrefix(task, "upgrade") && !strings.HasSuffix(task, "--dry-run --output=json") {
+					t.Errorf("want the upgrade to be a dry-run, got: %s", task)
+				}
+				if strings.HasPrefix(task, "create") {
+					t.Errorf("want kubectl create to be skipped")
+				}
+			}
+
+			diffs := e.Diffs()
+			if len(diffs) != 1 || diffs[0].Release != "openfaas" || diffs[0].Namespace != "openfaas" {
+				t.Fatalf("want a diff for openfaas/openfaas, got: %v", diffs)
+			}
+			if got := strings.Join(diffs[0].Lines, "\n"); got != strings.Join(tc.want, "\n") {
+				t.Errorf("want:\n%s\ngot:\n%s", strings.Join(tc.want, "\n"), got)
+			}
+		})
+	}
+}
@@ -8,38 +8,41 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 )
 
+// GetUserDir returns arkade's home directory, see GetPaths.
 func GetUserDir() string {
-	home := os.Getenv("HOME")
-	root := fmt.Sprintf("%s/.arkade/", home)
-	return root
+	return GetPaths().Root
 }
 
+// InitUserDir creates arkade's home, bin and helm directories and
+// returns the home directory.
 func InitUserDir() (string, error) {
-	home := os.Getenv("HOME")
-	root := fmt.Sprintf("%s/.arkade/", home)
+	paths := GetPaths()
 
-	if len(home) == 0 {
-		return home, fmt.Errorf("env-var HOME, not set")
-	}
-
-	binPath := path.Join(root, "/bin/")
-	err := os.MkdirAll(binPath, 0700)
-	if err != nil {
-		return binPath, err
-	}
-
-	helmPath := path.Join(root, "/.helm/")
-	helmErr := os.MkdirAll(helmPath, 0700)
-	if helmErr != nil {
-		return helmPath, helmErr
+	for _, dir := range []string{paths.Root, paths.Bin, paths.Helm} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return dir, err
+		}
 	}
 
-	return root, nil
+	return paths.Root, nil
 }
 
+var (
+	kubeconfig  string
+	kubeContext string
+	kubeMu      sync.RWMutex
+)
+
+// GetDefaultKubeconfig returns the kubeconfig set by SetKubeconfig, the
+// KUBECONFIG environment variable, or $HOME/.kube/config.
 func GetDefaultKubeconfig() string {
+	if explicit := Kubeconfig(); len(explicit) > 0 {
+		return explicit
+	}
+
 	kubeConfigPath := path.Join(os.Getenv("HOME"), ".kube/config")
 
 	if val, ok := os.LookupEnv("KUBECONFIG"); ok {
@@ -49,6 +52,33 @@ func GetDefaultKubeconfig() string {
 	return kubeConfigPath
 }
 
+// Kubeconfig returns the kubeconfig given to SetKubeconfig, or an empty
+// string when none was given.
+func Kubeconfig() string {
+	kubeMu.RLock()
+	defer kubeMu.RUnlock()
+
+	return kubeconfig
+}
+
+// SetKubeContext selects a context from the kubeconfig for this
+// invocation, instead of its current context. An empty string uses the
+// current context.
+func SetKubeContext(name string) {
+	kubeMu.Lock()
+	defer kubeMu.Unlock()
+
+	kubeContext = name
+}
+
+// KubeContext returns the context given to SetKubeContext.
+func KubeContext() string {
+	kubeMu.RLock()
+	defer kubeMu.RUnlock()
+
+	return kubeContext
+}
+
 func MergeFlags(existingMap map[string]string, setOverrides []string) error {
 	for _, setOverride := range setOverrides {
 		// Limit the number of parts to 2 to keep `=` characters in the value.
@@ -66,17 +96,22 @@ func MergeFlags(existingMap map[string]string, setOverrides []string) error {
 	return nil
 }
 
+// SetKubeconfig selects a kubeconfig file for this invocation. It is
+// passed to each kubectl and helm command, rather than set in the
+// process-wide KUBECONFIG environment variable.
 func SetKubeconfig(kubeconfigPath string) error {
 	// Favour explicitly set kubeconfig
 	if len(kubeconfigPath) > 0 {
-		err := os.Setenv("KUBECONFIG", kubeconfigPath)
-		if err != nil {
-			return err
-		}
+		kubeMu.Lock()
+		kubeconfig = kubeconfigPath
+		kubeMu.Unlock()
 	}
 
-	kubeconfig := GetDefaultKubeconfig()
+	if name := KubeContext(); len(name) > 0 {
+		fmt.Printf("Using Kubeconfig: %s (context: %s)\n", GetDefaultKubeconfig(), name)
+		return nil
+	}
 
-	fmt.Printf("Using Kubeconfig: %s\n", kubeconfig)
+	fmt.Printf("Using Kubeconfig: %s\n", GetDefaultKubeconfig())
 	return nil
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -108,3 +109,19 @@ func Test_MergeFlags(t *testing.T) {
 		})
 	}
 }
+
+func Test_SetKubeconfig_DoesNotChangeEnvironment(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/etc/kubeconfig")
+	defer func() { kubeconfig = "" }()
+
+	if err := SetKubeconfig("/tmp/edge-1.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := os.Getenv("KUBECONFIG"); got != "/etc/kubeconfig" {
+		t.Errorf("want KUBECONFIG unchanged, got %s", got)
+	}
+	if got := GetDefaultKubeconfig(); got != "/tmp/edge-1.yaml" {
+		t.Errorf("want /tmp/edge-1.yaml, got %s", got)
+	}
+}
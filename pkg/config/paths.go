@@ -0,0 +1,132 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Paths holds the directories arkade uses on the local machine.
+type Paths struct {
+	// Root holds arkade's state, $HOME/.arkade by default.
+	Root string
+
+	// Bin is where "arkade get" installs binaries.
+	Bin string
+
+	// Helm is used as HELM_HOME for helm downloads and repositories.
+	Helm string
+
+	// Cache holds data which can be downloaded again, such as charts.
+	Cache string
+
+	// State holds records of the apps installed to each cluster.
+	State string
+
+	// Apps holds the user's own app definitions for "arkade install".
+	Apps string
+}
+
+// Settings are read from the user's config file, which is
+// $XDG_CONFIG_HOME/arkade/config.yaml, or config.yaml in the
+// arkade home directory. ARKADE_CONFIG overrides the location.
+type Settings struct {
+	// BinPath is the default install path for "arkade get".
+	BinPath string `yaml:"bin_path"`
+}
+
+// GetPaths resolves arkade's directories in order of precedence:
+//
+//   - ARKADE_HOME, when set, holds everything
+//   - $HOME/.arkade, when it already exists
+//   - $XDG_DATA_HOME/arkade and $XDG_CACHE_HOME/arkade, when set
+//   - $HOME/.arkade
+//
+// When HOME cannot be determined, i.e. in some CI runners and systemd
+// units, a directory under os.TempDir() is used instead of failing.
+// A bin_path in the config file overrides the Bin directory.
+func GetPaths() Paths {
+	home, _ := os.UserHomeDir()
+
+	var p Paths
+	legacy := filepath.Join(home, ".arkade")
+
+	if v, ok := os.LookupEnv("ARKADE_HOME"); ok && len(v) > 0 {
+		p = pathsFromRoot(v)
+	} else if _, err := os.Stat(legacy); len(home) > 0 && err == nil {
+		p = pathsFromRoot(legacy)
+	} else if v, ok := os.LookupEnv("XDG_DATA_HOME"); ok && len(v) > 0 {
+		p = pathsFromRoot(filepath.Join(v, "arkade"))
+		if c, ok := os.LookupEnv("XDG_CACHE_HOME"); ok && len(c) > 0 {
+			p.Cache = filepath.Join(c, "arkade")
+		}
+	} else if len(home) > 0 {
+		p = pathsFromRoot(legacy)
+	} else {
+		p = pathsFromRoot(filepath.Join(os.TempDir(), "arkade"))
+	}
+
+	settings, err := LoadSettings(settingsFile(p.Root))
+	if err == nil && len(settings.BinPath) > 0 {
+		p.Bin = os.ExpandEnv(settings.BinPath)
+	}
+
+	return p
+}
+
+func pathsFromRoot(root string) Paths {
+	return Paths{
+		Root:  root,
+		Bin:   filepath.Join(root, "bin"),
+		Helm:  filepath.Join(root, ".helm"),
+		Cache: filepath.Join(root, "cache"),
+		State: filepath.Join(root, "state"),
+		Apps:  filepath.Join(root, "apps"),
+	}
+}
+
+// GetHelmDir returns the directory to use for HELM_HOME.
+func GetHelmDir() string {
+	return GetPaths().Helm
+}
+
+// LoadSettings reads the user's config file, a missing file gives
+// empty Settings.
+func LoadSettings(file string) (*Settings, error) {
+	settings := &Settings{}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return settings, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func settingsFile(root string) string {
+	if v, ok := os.LookupEnv("ARKADE_CONFIG"); ok && len(v) > 0 {
+		return v
+	}
+
+	// UserConfigDir honours XDG_CONFIG_HOME
+	if configHome, err := os.UserConfigDir(); err == nil {
+		file := filepath.Join(configHome, "arkade", "config.yaml")
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+
+	return filepath.Join(root, "config.yaml")
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setPathsEnv(t *testing.T, home string) {
+	t.Helper()
+
+	t.Setenv("HOME", home)
+	t.Setenv("ARKADE_HOME", "")
+	t.Setenv("ARKADE_CONFIG", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+}
+
+func Test_GetPaths_DefaultsToHome(t *testing.T) {
+	home := t.TempDir()
+	setPathsEnv(t, home)
+
+	got := GetPaths()
+	want := filepath.Join(home, ".arkade", "bin")
+	if got.Bin != want {
+		t.Fatalf("want bin %q, got %q", want, got.Bin)
+	}
+	if got.Helm != filepath.Join(home, ".arkade", ".helm") {
+		t.Fatalf("unexpected helm dir %q", got.Helm)
+	}
+}
+
+func Test_GetPaths_ArkadeHome(t *testing.T) {
+	home := t.TempDir()
+	setPathsEnv(t, home)
+
+	arkadeHome := t.TempDir()
+	t.Setenv("ARKADE_HOME", arkadeHome)
+
+	got := GetPaths()
+	if got.Root != arkadeHome {
+		t.Fatalf("want root %q, got %q", arkadeHome, got.Root)
+	}
+	if got.Bin != filepath.Join(arkadeHome, "bin") {
+		t.Fatalf("unexpected bin dir %q", got.Bin)
+	}
+}
+
+func Test_GetPaths_XDG(t *testing.T) {
+	home := t.TempDir()
+	setPathsEnv(t, home)
+
+	data := filepath.Join(home, "data")
+	cache := filepath.Join(home, "cache")
+	t.Setenv("XDG_DATA_HOME", data)
+	t.Setenv("XDG_CACHE_HOME", cache)
+
+	got := GetPaths()
+	if got.Root != filepath.Join(data, "arkade") {
+		t.Fatalf("unexpected root %q", got.Root)
+	}
+	if got.Cache != filepath.Join(cache, "arkade") {
+		t.Fatalf("unexpected cache %q", got.Cache)
+	}
+}
+
+func Test_GetPaths_ExistingLegacyDirWinsOverXDG(t *testing.T) {
+	home := t.TempDir()
+	setPathsEnv(t, home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	if err := os.MkdirAll(filepath.Join(home, ".arkade"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	got := GetPaths()
+	if got.Root != filepath.Join(home, ".arkade") {
+		t.Fatalf("want legacy root, got %q", got.Root)
+	}
+}
+
+func Test_GetPaths_NoHome(t *testing.T) {
+	setPathsEnv(t, "")
+
+	got := GetPaths()
+	if got.Root != filepath.Join(os.TempDir(), "arkade") {
+		t.Fatalf("unexpected root without HOME %q", got.Root)
+	}
+}
+
+func Test_GetPaths_SettingsBinPath(t *testing.T) {
+	home := t.TempDir()
+	setPathsEnv(t, home)
+
+	configDir := filepath.Join(home, ".config", "arkade")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("bin_path: $HOME/bin\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := GetPaths()
+	if got.Bin != filepath.Join(home, "bin") {
+		t.Fatalf("want bin from config file, got %q", got.Bin)
+	}
+}
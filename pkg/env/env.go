@@ -6,11 +6,11 @@ package env
 import (
 	"context"
 	"log"
-	"os"
 	"path"
 	"runtime"
 	"strings"
 
+	"github.com/alexellis/arkade/pkg/config"
 	execute "github.com/alexellis/go-execute/v2"
 )
 
@@ -50,9 +50,10 @@ func getClientArchFromUname() (arch string, os string) {
 	return archResult, osResult
 }
 
+// LocalBinary returns the path to a binary in arkade's bin directory,
+// see config.GetPaths.
 func LocalBinary(name, subdir string) string {
-	home := os.Getenv("HOME")
-	val := path.Join(home, ".arkade/bin/")
+	val := config.GetPaths().Bin
 	if len(subdir) > 0 {
 		val = path.Join(val, subdir)
 	}
@@ -0,0 +1,68 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+const (
+	// LibcGNU is glibc, used by most Linux distributions.
+	LibcGNU = "gnu"
+
+	// LibcMusl is musl, used by Alpine Linux and many container images.
+	LibcMusl = "musl"
+)
+
+var (
+	clientLibc     string
+	clientLibcOnce sync.Once
+)
+
+// GetClientLibc returns the C library of the client machine, either
+// LibcGNU or LibcMusl. The ARKADE_LIBC environment variable overrides
+// detection, which is useful when downloading for another machine.
+// Non-Linux clients always report LibcGNU.
+func GetClientLibc() string {
+	if v, ok := os.LookupEnv("ARKADE_LIBC"); ok && len(v) > 0 {
+		return strings.ToLower(v)
+	}
+
+	clientLibcOnce.Do(func() {
+		clientLibc = detectLibc()
+	})
+
+	return clientLibc
+}
+
+func detectLibc() string {
+	if runtime.GOOS != "linux" {
+		return LibcGNU
+	}
+
+	// musl's dynamic loader is installed as /lib/ld-musl-<arch>.so.1
+	if matches, _ := filepath.Glob("/lib/ld-musl-*"); len(matches) > 0 {
+		return LibcMusl
+	}
+
+	// Fall back to ldd, which prints "musl libc" on stderr for musl
+	task := execute.ExecTask{
+		Command:     "ldd",
+		Args:        []string{"--version"},
+		StreamStdio: false,
+	}
+	res, err := task.Execute(context.Background())
+	if err == nil && strings.Contains(strings.ToLower(res.Stdout+res.Stderr), "musl") {
+		return LibcMusl
+	}
+
+	return LibcGNU
+}
@@ -0,0 +1,20 @@
+package env
+
+import "testing"
+
+func Test_GetClientLibc_Override(t *testing.T) {
+	t.Setenv("ARKADE_LIBC", "MUSL")
+
+	if got := GetClientLibc(); got != LibcMusl {
+		t.Fatalf("want %q, got %q", LibcMusl, got)
+	}
+}
+
+func Test_GetClientLibc_Detected(t *testing.T) {
+	t.Setenv("ARKADE_LIBC", "")
+
+	got := GetClientLibc()
+	if got != LibcGNU && got != LibcMusl {
+		t.Fatalf("want %q or %q, got %q", LibcGNU, LibcMusl, got)
+	}
+}
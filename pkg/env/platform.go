@@ -0,0 +1,152 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Platform is a canonical operating system and CPU architecture pair.
+// OS and Arch follow Go's GOOS/GOARCH naming, Variant is only set for
+// 32-bit ARM, where it is either "v6" or "v7". Libc is only set for
+// Linux, when known.
+type Platform struct {
+	OS      string
+	Arch    string
+	Variant string
+	Libc    string
+}
+
+type archAlias struct {
+	arch    string
+	variant string
+}
+
+// archAliases maps the names returned by uname, Go and vendors'
+// release pages to a canonical architecture and variant.
+var archAliases = map[string]archAlias{
+	"x86_64":  {arch: "amd64"},
+	"amd64":   {arch: "amd64"},
+	"x64":     {arch: "amd64"},
+	"aarch64": {arch: "arm64"},
+	"arm64":   {arch: "arm64"},
+	"armv8":   {arch: "arm64"},
+	"arm":     {arch: "arm", variant: "v7"},
+	"armhf":   {arch: "arm", variant: "v7"},
+	"armv7":   {arch: "arm", variant: "v7"},
+	"armv7l":  {arch: "arm", variant: "v7"},
+	"armv6":   {arch: "arm", variant: "v6"},
+	"armv6l":  {arch: "arm", variant: "v6"},
+	"i386":    {arch: "386"},
+	"i686":    {arch: "386"},
+	"x86":     {arch: "386"},
+	"386":     {arch: "386"},
+}
+
+// NewPlatform builds a Platform from any known alias of an OS and
+// architecture such as "Linux" and "x86_64", or "darwin" and "arm64".
+// Unknown values are passed through in lower case.
+func NewPlatform(operatingSystem, arch string) Platform {
+	a, variant := NormaliseArch(arch)
+	return Platform{
+		OS:      NormaliseOS(operatingSystem),
+		Arch:    a,
+		Variant: variant,
+	}
+}
+
+// ParsePlatform parses a platform in the "os/arch[/variant]" form
+// used by container registries, i.e. "linux/amd64" or "linux/arm/v7".
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform %q, want os/arch[/variant] i.e. linux/amd64", s)
+	}
+
+	p := NewPlatform(parts[0], parts[1])
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return Platform{}, fmt.Errorf("invalid platform %q, variant is empty", s)
+		}
+		p.Variant = strings.ToLower(parts[2])
+	}
+
+	return p, nil
+}
+
+// GetClientPlatform returns the canonical Platform of the client machine.
+func GetClientPlatform() Platform {
+	arch, operatingSystem := GetClientArch()
+	p := NewPlatform(operatingSystem, arch)
+	if p.OS == "linux" {
+		p.Libc = GetClientLibc()
+	}
+	return p
+}
+
+// NormaliseArch returns the canonical Go architecture name and ARM
+// variant for an alias such as "x86_64", "aarch64" or "armv7l".
+func NormaliseArch(arch string) (string, string) {
+	a := strings.ToLower(strings.TrimSpace(arch))
+	if alias, ok := archAliases[a]; ok {
+		return alias.arch, alias.variant
+	}
+	return a, ""
+}
+
+// NormaliseOS returns the canonical Go name for an operating system,
+// including the MINGW/MSYS/Cygwin names reported by uname on Windows.
+func NormaliseOS(operatingSystem string) string {
+	o := strings.ToLower(strings.TrimSpace(operatingSystem))
+	switch {
+	case strings.HasPrefix(o, "ming"), strings.HasPrefix(o, "msys"), strings.HasPrefix(o, "cygwin"),
+		strings.HasPrefix(o, "microsoft windows"):
+		return "windows"
+	case o == "macos", o == "osx":
+		return "darwin"
+	}
+	return o
+}
+
+// String renders the platform as "os/arch[/variant]".
+func (p Platform) String() string {
+	if len(p.Variant) > 0 {
+		return p.OS + "/" + p.Arch + "/" + p.Variant
+	}
+	return p.OS + "/" + p.Arch
+}
+
+// ArchVariant joins the architecture and variant, i.e. "armv7" or
+// "armv6" for 32-bit ARM, and the plain architecture otherwise.
+func (p Platform) ArchVariant() string {
+	return p.Arch + p.Variant
+}
+
+// UnameArch renders the architecture as reported by `uname -m`
+// on Linux, i.e. "x86_64", "aarch64" or "armv7l".
+func (p Platform) UnameArch() string {
+	switch p.Arch {
+	case "amd64":
+		return "x86_64"
+	case "arm64":
+		if p.OS == "darwin" {
+			return "arm64"
+		}
+		return "aarch64"
+	case "arm":
+		if p.Variant == "v6" {
+			return "armv6l"
+		}
+		return "armv7l"
+	case "386":
+		return "i386"
+	}
+	return p.Arch
+}
+
+// IsArm returns true for both 32-bit and 64-bit ARM.
+func (p Platform) IsArm() bool {
+	return p.Arch == "arm" || p.Arch == "arm64"
+}
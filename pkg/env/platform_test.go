@@ -0,0 +1,68 @@
+package env
+
+import "testing"
+
+func Test_NewPlatform(t *testing.T) {
+	tests := []struct {
+		os, arch  string
+		want      Platform
+		wantUname string
+	}{
+		{"Linux", "x86_64", Platform{OS: "linux", Arch: "amd64"}, "x86_64"},
+		{"linux", "amd64", Platform{OS: "linux", Arch: "amd64"}, "x86_64"},
+		{"Linux", "aarch64", Platform{OS: "linux", Arch: "arm64"}, "aarch64"},
+		{"Darwin", "arm64", Platform{OS: "darwin", Arch: "arm64"}, "arm64"},
+		{"Linux", "armv7l", Platform{OS: "linux", Arch: "arm", Variant: "v7"}, "armv7l"},
+		{"linux", "armv6l", Platform{OS: "linux", Arch: "arm", Variant: "v6"}, "armv6l"},
+		{"MINGW64_NT-10.0-19045", "x86_64", Platform{OS: "windows", Arch: "amd64"}, "x86_64"},
+		{"Microsoft Windows 11 Pro", "amd64", Platform{OS: "windows", Arch: "amd64"}, "x86_64"},
+		{"linux", "riscv64", Platform{OS: "linux", Arch: "riscv64"}, "riscv64"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.os+"/"+tc.arch, func(t *testing.T) {
+			got := NewPlatform(tc.os, tc.arch)
+			if got != tc.want {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+			if uname := got.UnameArch(); uname != tc.wantUname {
+				t.Fatalf("want uname arch %q, got %q", tc.wantUname, uname)
+			}
+		})
+	}
+}
+
+func Test_ParsePlatform(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "linux/amd64", want: "linux/amd64"},
+		{in: "linux/x86_64", want: "linux/amd64"},
+		{in: "linux/arm/v6", want: "linux/arm/v6"},
+		{in: "linux/armv7l", want: "linux/arm/v7"},
+		{in: "linux", wantErr: true},
+		{in: "/amd64", wantErr: true},
+		{in: "linux/arm/", wantErr: true},
+		{in: "linux/arm/v7/extra", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParsePlatform(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("want error for %q", tc.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.String() != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,587 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package get
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	units "github.com/docker/go-units"
+
+	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/env"
+)
+
+const defaultGitHubURL = "https://github.com"
+
+// Logger receives informational messages from a Client, *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// VerifyInput describes a downloaded file which needs to be verified.
+type VerifyInput struct {
+	Tool     *Tool
+	OS       string
+	Arch     string
+	Version  string
+	FilePath string
+}
+
+// Verifier checks a downloaded file before it is installed.
+type Verifier interface {
+	Verify(ctx context.Context, input VerifyInput) error
+}
+
+// Options configures a Client, all fields are optional.
+type Options struct {
+	// HTTPClient is used for every request, http.DefaultClient when nil.
+	HTTPClient *http.Client
+
+	// Logger receives progress messages, log.Default() when nil.
+	Logger Logger
+
+	// Quiet suppresses informational messages to the Logger,
+	// including retries.
+	Quiet bool
+
+	// CacheDir is where files are downloaded to before they are
+	// extracted and installed, os.TempDir() when empty.
+	CacheDir string
+
+	// InstallDir is where binaries are installed, arkade's bin
+	// directory when empty.
+	InstallDir string
+
+	// Verify enables checksum verification of downloads.
+	Verify bool
+
+	// Verifier is used when Verify is set, when nil the tool's
+	// VerifyStrategy decides how the checksum is fetched.
+	Verifier Verifier
+
+	// Progress receives byte counts during downloads, and replaces
+	// the built-in progress bar.
+	Progress ProgressCallback
+
+	// DisplayProgress renders the built-in progress bar to stderr.
+	DisplayProgress bool
+
+	// ReleaseLocations overrides where the latest version of a tool
+	// is looked up, keyed by VersionStrategy.
+	ReleaseLocations map[string]ReleaseLocation
+
+	// GitHubURL is the base URL for GitHub release downloads, for
+	// both BinaryTemplate and URLTemplate tools, https://github.com
+	// when empty.
+	GitHubURL string
+
+	// MaxRetries is the number of retries for failed requests,
+	// 10 when zero.
+	MaxRetries int
+}
+
+// Client resolves versions and downloads tools. Its zero value is not
+// usable, create one with NewClient.
+type Client struct {
+	opts       Options
+	httpClient *http.Client
+	logger     Logger
+	verifier   Verifier
+	locations  map[string]ReleaseLocation
+}
+
+// NewClient creates a Client with defaults for any unset Options.
+func NewClient(opts Options) *Client {
+	c := &Client{
+		opts:       opts,
+		httpClient: opts.HTTPClient,
+		logger:     opts.Logger,
+		verifier:   opts.Verifier,
+		locations:  map[string]ReleaseLocation{},
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.logger == nil {
+		c.logger = log.Default()
+	}
+	if c.verifier == nil {
+		c.verifier = &strategyVerifier{client: c}
+	}
+	if len(c.opts.GitHubURL) == 0 {
+		c.opts.GitHubURL = defaultGitHubURL
+	}
+	if c.opts.MaxRetries == 0 {
+		c.opts.MaxRetries = 10
+	}
+
+	for k, v := range releaseLocations {
+		c.locations[k] = v
+	}
+	for k, v := range opts.ReleaseLocations {
+		c.locations[k] = v
+	}
+
+	return c
+}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	if !c.opts.Quiet {
+		c.logger.Printf(format, v...)
+	}
+}
+
+func (c *Client) debugf(format string, args ...interface{}) {
+	if v, ok := os.LookupEnv("ARK_DEBUG"); ok && v == "1" {
+		c.logger.Printf(format, args...)
+	}
+}
+
+func (c *Client) retry(ctx context.Context, fn func() (string, error)) (string, error) {
+	return retryWithBackoffContext(ctx, c.logf, fn, c.opts.MaxRetries, 100*time.Millisecond)
+}
+
+func (c *Client) releaseType(tool *Tool) string {
+	var releaseType string
+	if len(tool.URLTemplate) == 0 ||
+		strings.Contains(tool.URLTemplate, "https://github.com/") {
+		releaseType = GitHubVersionStrategy
+	}
+	if len(tool.VersionStrategy) > 0 {
+		releaseType = tool.VersionStrategy
+	}
+	return releaseType
+}
+
+// ResolveVersion determines the version for a tool. When version is
+// non-empty it is returned as-is. Otherwise the latest release is
+// looked up using the tool's VersionStrategy.
+func (c *Client) ResolveVersion(ctx context.Context, tool *Tool, version string) (string, error) {
+	ver := GetToolVersion(tool, version)
+	if len(ver) > 0 {
+		return ver, nil
+	}
+
+	releaseType := c.releaseType(tool)
+	if _, supported := c.locations[releaseType]; supported {
+		return c.FindRelease(ctx, releaseType, tool.Owner, tool.Repo)
+	}
+
+	return ver, nil
+}
+
+// GetURL returns the download URL and the resolved version of a tool
+// for an OS and architecture. When version is empty, the latest
+// version is looked up.
+func (c *Client) GetURL(ctx context.Context, tool *Tool, os, arch, version string) (string, string, error) {
+	version = GetToolVersion(tool, version)
+
+	if len(version) == 0 {
+		c.logf("Looking up version for: %s", tool.Name)
+
+		releaseType := c.releaseType(tool)
+		if _, supported := c.locations[releaseType]; supported {
+			start := time.Now()
+			v, err := c.FindRelease(ctx, releaseType, tool.Owner, tool.Repo)
+			if err != nil {
+				return "", "", err
+			}
+			version = v
+			c.logf("Found %s version: %s (in %s)", tool.Name, version, time.Since(start).Round(time.Millisecond))
+		}
+	}
+
+	if len(tool.URLTemplate) > 0 {
+		res, err := getByDownloadTemplate(*tool, os, arch, version)
+		if err != nil {
+			return "", "", err
+		}
+		return c.gitHubURL(res), version, nil
+	}
+
+	downloadName, err := getDownloadNameByGithubTemplate(*tool, os, arch, version)
+	if err != nil {
+		return "", "", err
+	}
+
+	return c.gitHubURL(getBinaryURL(tool.Owner, tool.Repo, version, downloadName)), version, nil
+}
+
+// gitHubURL moves a download from https://github.com to the GitHubURL
+// option, other URLs are returned as-is.
+func (c *Client) gitHubURL(u string) string {
+	if c.opts.GitHubURL != defaultGitHubURL && strings.HasPrefix(u, defaultGitHubURL+"/") {
+		u = strings.TrimSuffix(c.opts.GitHubURL, "/") + strings.TrimPrefix(u, defaultGitHubURL)
+	}
+	return u
+}
+
+// FindRelease looks up the latest version of a tool using the release
+// location registered for a VersionStrategy.
+func (c *Client) FindRelease(ctx context.Context, location, owner, repo string) (string, error) {
+	loc, ok := c.locations[location]
+	if !ok {
+		return "", fmt.Errorf("unknown release location: %q", location)
+	}
+
+	url := formatUrl(loc.Url, owner, repo)
+
+	// HEAD strategies read the version from the redirect, text files
+	// such as stable.txt may be served from a CDN after a redirect
+	client := *c.httpClient
+	if loc.Method == http.MethodHead {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return c.retry(ctx, func() (string, error) {
+		reqCtx := ctx
+		if loc.Timeout > 0 {
+			var cancel context.CancelFunc
+			reqCtx, cancel = context.WithTimeout(ctx, loc.Timeout)
+			defer cancel()
+		}
+
+		req, err := http.NewRequestWithContext(reqCtx, loc.Method, url, nil)
+		if err != nil {
+			return "", err
+		}
+
+		setRequestHeaders(req)
+
+		res, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		defer res.Body.Close()
+
+		if loc.Method == http.MethodHead {
+
+			if res.StatusCode != http.StatusMovedPermanently && res.StatusCode != http.StatusFound {
+				return "", newHTTPError(res, "")
+			}
+
+			location := res.Header.Get("Location")
+			if len(location) == 0 {
+				return "", fmt.Errorf("unable to determine release of tool")
+			}
+
+			version := location[strings.LastIndex(location, "/")+1:]
+			return version, nil
+		}
+
+		bodyBytes, err := io.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+
+		if res.StatusCode != http.StatusOK {
+			return "", newHTTPError(res, string(bodyBytes))
+		}
+
+		version := strings.TrimSpace(string(bodyBytes))
+		return version, nil
+	})
+}
+
+// DownloadFile downloads a URL into a new directory under CacheDir and
+// returns the path to the file.
+func (c *Client) DownloadFile(ctx context.Context, downloadURL string) (string, error) {
+	return c.retry(ctx, func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+		if err != nil {
+			return "", err
+		}
+
+		setRequestHeaders(req)
+
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return "", newHTTPError(res, "")
+		}
+
+		_, fileName := path.Split(downloadURL)
+
+		cacheDir := c.opts.CacheDir
+		if len(cacheDir) == 0 {
+			cacheDir = os.TempDir()
+		}
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return "", err
+		}
+
+		customTmp, err := os.MkdirTemp(cacheDir, "arkade-*")
+		if err != nil {
+			return "", err
+		}
+
+		outFilePath := path.Join(customTmp, fileName)
+
+		var wrappedReader io.ReadCloser
+		if c.opts.Progress != nil {
+			wrappedReader = newCallbackReader(res.Body, res.ContentLength, c.opts.Progress)
+		} else {
+			wrappedReader = withProgressBar(res.Body, int(res.ContentLength), c.opts.DisplayProgress)
+		}
+
+		// Owner/Group read/write/execute
+		// World - execute
+		out, err := os.OpenFile(outFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0775)
+		if err != nil {
+			return "", err
+		}
+
+		defer out.Close()
+		defer wrappedReader.Close()
+
+		if _, err := io.Copy(out, wrappedReader); err != nil {
+			os.RemoveAll(customTmp)
+			return "", err
+		}
+
+		return outFilePath, nil
+	})
+}
+
+func (c *Client) fetchText(ctx context.Context, url string) (string, error) {
+	return c.retry(ctx, func() (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", err
+		}
+		setRequestHeaders(req)
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+
+		if res.StatusCode != http.StatusOK {
+			return "", newHTTPError(res, string(body))
+		}
+
+		return string(body), nil
+	})
+}
+
+// Download resolves, downloads, verifies and extracts a tool, then
+// installs it into InstallDir. It returns the path to the installed
+// binary and its file name.
+func (c *Client) Download(ctx context.Context, tool *Tool, arch, operatingSystem, version string) (string, string, error) {
+	downloadURL, resolvedVersion, err := c.GetURL(ctx, tool,
+		strings.ToLower(operatingSystem),
+		strings.ToLower(arch),
+		version)
+	if err != nil {
+		return "", "", err
+	}
+
+	c.logf("Downloading: %s", downloadURL)
+
+	start := time.Now()
+
+	outFilePath, err := c.DownloadFile(ctx, downloadURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Remove the temporary directory of the download on every path
+	tempPath := filepath.Dir(outFilePath)
+	defer func() {
+		if err := os.RemoveAll(tempPath); err != nil {
+			c.logf("Error removing temporary directory: %s", err)
+		}
+	}()
+
+	if stat, err := os.Stat(outFilePath); err == nil {
+		c.logf("Downloaded %s (%s) in %s.", path.Base(downloadURL),
+			units.HumanSize(float64(stat.Size())),
+			time.Since(start).Round(time.Millisecond))
+	}
+
+	if c.opts.Verify {
+		if err := c.verifier.Verify(ctx, VerifyInput{
+			Tool:     tool,
+			OS:       operatingSystem,
+			Arch:     arch,
+			Version:  resolvedVersion,
+			FilePath: outFilePath,
+		}); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	if isArchiveStr(downloadURL) {
+		outPath, err := c.decompress(tool, downloadURL, outFilePath, operatingSystem, arch, version)
+		if err != nil {
+			return "", "", err
+		}
+
+		outFilePath = outPath
+		c.debugf("Extracted: %s", outFilePath)
+	}
+
+	finalName := tool.Name
+	if strings.Contains(strings.ToLower(operatingSystem), "mingw") && !tool.NoExtension {
+		finalName = finalName + ".exe"
+	}
+
+	var localPath string
+
+	if c.opts.InstallDir == "" {
+		if _, err := config.InitUserDir(); err != nil {
+			return "", "", err
+		}
+
+		localPath = env.LocalBinary(finalName, "")
+	} else {
+		localPath = filepath.Join(c.opts.InstallDir, finalName)
+	}
+
+	c.debugf("Copying %s to %s\n", outFilePath, localPath)
+
+	if _, err = atomicCopyFile(outFilePath, localPath, 0755); err != nil {
+		return "", "", err
+	}
+
+	return localPath, finalName, nil
+}
+
+// strategyVerifier verifies a download with the checksum published
+// for the tool's VerifyStrategy, tools without one are not verified.
+type strategyVerifier struct {
+	client *Client
+}
+
+func (s *strategyVerifier) Verify(ctx context.Context, input VerifyInput) error {
+	tool := input.Tool
+
+	switch tool.VerifyStrategy {
+	case ClaudeShasumStrategy, HashicorpShasumStrategy, AmpShasumStrategy:
+	default:
+		return nil
+	}
+
+	st := time.Now()
+	tmpl := template.New(tool.Name + "sha")
+	tmpl = tmpl.Funcs(templateFuncs)
+	t, err := tmpl.Parse(tool.VerifyTemplate)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	inputs := map[string]string{
+		"Name":          tool.Name,
+		"Owner":         tool.Owner,
+		"Repo":          tool.Repo,
+		"Version":       input.Version,
+		"VersionNumber": strings.TrimPrefix(input.Version, "v"),
+		"Arch":          input.Arch,
+		"OS":            input.OS,
+	}
+
+	if err = t.Execute(&buf, withPlatform(inputs)); err != nil {
+		return err
+	}
+
+	verifyURL := strings.TrimSpace(buf.String())
+	s.client.logf("Downloading SHA sum from: %s", verifyURL)
+
+	shaSum, err := s.client.fetchText(ctx, verifyURL)
+	if err != nil {
+		return err
+	}
+
+	if tool.VerifyStrategy == ClaudeShasumStrategy {
+		if shaSum, err = claudeChecksum(shaSum, input.OS, input.Arch); err != nil {
+			return err
+		}
+		shaSum = shaSum + " " + filepath.Base(input.FilePath)
+	}
+
+	if err := verifySHA(shaSum, input.FilePath); err != nil {
+		return err
+	}
+
+	s.client.logf("SHA sum verified in %s.", time.Since(st).Round(time.Millisecond))
+	return nil
+}
+
+// claudeChecksum finds the checksum for a platform in Claude's
+// release manifest.
+func claudeChecksum(manifestJSON, operatingSystem, arch string) (string, error) {
+	var manifest struct {
+		Version   string `json:"version"`
+		BuildDate string `json:"buildDate"`
+		Platforms map[string]struct {
+			Checksum string `json:"checksum"`
+			Size     int64  `json:"size"`
+		} `json:"platforms"`
+	}
+	if err := json.Unmarshal([]byte(manifestJSON), &manifest); err != nil {
+		return "", err
+	}
+
+	// Claude's manifest names amd64 as "x64"
+	platform := env.NewPlatform(operatingSystem, arch)
+	claudeArch := platform.Arch
+	if claudeArch == "amd64" {
+		claudeArch = "x64"
+	}
+
+	platformKey := fmt.Sprintf("%s-%s", platform.OS, claudeArch)
+
+	platformInfo, found := manifest.Platforms[platformKey]
+	if !found {
+		return "", fmt.Errorf("no checksum info found for platform: %s", platformKey)
+	}
+
+	return platformInfo.Checksum, nil
+}
+
+// setRequestHeaders sets arkade's User-Agent, and when GITHUB_TOKEN is
+// set, authenticates requests to GitHub for a higher rate limit.
+func setRequestHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", pkg.UserAgent())
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if len(token) == 0 || req.URL.Scheme != "https" {
+		return
+	}
+
+	switch req.URL.Hostname() {
+	case "github.com", "api.github.com":
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
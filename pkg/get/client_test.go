@@ -0,0 +1,409 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, server *httptest.Server, opts Options) *Client {
+	t.Helper()
+
+	opts.HTTPClient = server.Client()
+	opts.GitHubURL = server.URL
+	opts.ReleaseLocations = map[string]ReleaseLocation{
+		GitHubVersionStrategy: {
+			Url:     server.URL + "/%s/%s/releases/latest",
+			Timeout: time.Second * 5,
+			Method:  http.MethodHead,
+		},
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.New(io.Discard, "", 0)
+	}
+	if len(opts.InstallDir) == 0 {
+		opts.InstallDir = t.TempDir()
+	}
+	if len(opts.CacheDir) == 0 {
+		opts.CacheDir = t.TempDir()
+	}
+	opts.MaxRetries = 1
+
+	return NewClient(opts)
+}
+
+func testTool() *Tool {
+	return &Tool{
+		Owner:          "acme",
+		Repo:           "widget",
+		Name:           "widget",
+		BinaryTemplate: `{{.Name}}-{{.GoOS}}-{{.GoArch}}`,
+	}
+}
+
+func Test_Client_FindRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/acme/widget/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Location", "/acme/widget/releases/tag/v1.2.3")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, Options{})
+
+	got, err := c.FindRelease(context.Background(), GitHubVersionStrategy, "acme", "widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "v1.2.3" {
+		t.Fatalf("want v1.2.3, got %q", got)
+	}
+}
+
+func Test_Client_Download(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme/widget/releases/latest":
+			w.Header().Set("Location", "/acme/widget/releases/tag/v1.2.3")
+			w.WriteHeader(http.StatusFound)
+		case "/acme/widget/releases/download/v1.2.3/widget-linux-arm64":
+			fmt.Fprint(w, "#!/bin/sh\necho widget\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var total int64
+	installDir := t.TempDir()
+	c := newTestClient(t, server, Options{
+		InstallDir: installDir,
+		Progress: func(bytesRead, totalBytes int64) {
+			total = bytesRead
+		},
+	})
+
+	got, name, err := c.Download(context.Background(), testTool(), "aarch64", "Linux", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := filepath.Join(installDir, "widget"); got != want || name != "widget" {
+		t.Fatalf("want %s and widget, got %s and %s", want, got, name)
+	}
+
+	data, err := os.ReadFile(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "#!/bin/sh\necho widget\n" {
+		t.Fatalf("unexpected content: %q", string(data))
+	}
+	if total != int64(len(data)) {
+		t.Fatalf("want progress of %d bytes, got %d", len(data), total)
+	}
+}
+
+func Test_Client_Download_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	c := newTestClient(t, server, Options{})
+
+	_, _, err := c.Download(context.Background(), testTool(), "x86_64", "linux", "v1.0.0")
+
+	if !errors.Is(err, &ErrNotFound{}) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("want HTTPError with 404, got %v", err)
+	}
+}
+
+func Test_Client_FindRelease_RetriesServerErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Location", "/acme/widget/releases/tag/v1.2.3")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, Options{})
+
+	got, err := c.FindRelease(context.Background(), GitHubVersionStrategy, "acme", "widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "v1.2.3" || attempts != 2 {
+		t.Fatalf("want v1.2.3 after 2 attempts, got %q after %d", got, attempts)
+	}
+}
+
+func Test_Client_FindRelease_QuietRetries(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		t.Run(fmt.Sprintf("quiet=%t", quiet), func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Header().Set("Location", "/acme/widget/releases/tag/v1.2.3")
+				w.WriteHeader(http.StatusFound)
+			}))
+			defer server.Close()
+
+			var out strings.Builder
+			c := newTestClient(t, server, Options{Quiet: quiet, Logger: log.New(&out, "", 0)})
+
+			if _, err := c.FindRelease(context.Background(), GitHubVersionStrategy, "acme", "widget"); err != nil {
+				t.Fatal(err)
+			}
+			if logged := strings.Contains(out.String(), "Retrying"); logged == quiet {
+				t.Fatalf("want the retry logged: %t, got: %q", !quiet, out.String())
+			}
+		})
+	}
+}
+
+func Test_Client_GetURL_GitHubURL(t *testing.T) {
+	c := NewClient(Options{GitHubURL: "https://mirror.example.com/gh/"})
+
+	tests := []struct {
+		name string
+		tool *Tool
+		want string
+	}{
+		{
+			name: "binary template",
+			tool: testTool(),
+			want: "https://mirror.example.com/gh/acme/widget/releases/download/v1.2.3/widget-linux-amd64",
+		},
+		{
+			name: "URL template on GitHub",
+			tool: &Tool{
+				Name:        "widget",
+				URLTemplate: `https://github.com/acme/widget/releases/download/{{.Version}}/widget-{{.GoOS}}.tgz`,
+			},
+			want: "https://mirror.example.com/gh/acme/widget/releases/download/v1.2.3/widget-linux.tgz",
+		},
+		{
+			name: "URL template elsewhere",
+			tool: &Tool{
+				Name:        "widget",
+				URLTemplate: `https://dl.example.com/widget/{{.Version}}/widget-{{.GoOS}}.tgz`,
+			},
+			want: "https://dl.example.com/widget/v1.2.3/widget-linux.tgz",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := c.GetURL(context.Background(), tc.tool, "linux", "x86_64", "v1.2.3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_Client_FindRelease_RetriesAfterRateLimit(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Location", "/acme/widget/releases/tag/v1.2.3")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, Options{})
+
+	if _, err := c.FindRelease(context.Background(), GitHubVersionStrategy, "acme", "widget"); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("want 2 attempts, got %d", attempts)
+	}
+}
+
+func Test_Client_FindRelease_RateLimitResetTooFarAway(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, Options{})
+
+	_, err := c.FindRelease(context.Background(), GitHubVersionStrategy, "acme", "widget")
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !httpErr.RateLimited() {
+		t.Fatalf("want rate limit error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Fatalf("want GITHUB_TOKEN in error, got %q", err.Error())
+	}
+	if attempts != 1 {
+		t.Fatalf("want 1 attempt, got %d", attempts)
+	}
+}
+
+func Test_HTTPError_TokenHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		wantHint bool
+	}{
+		{name: "no token", wantHint: true},
+		{name: "token sent", token: "secret", wantHint: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/acme/widget/releases/latest", nil)
+			if len(tc.token) > 0 {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			res := &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"X-Ratelimit-Remaining": []string{"0"}},
+				Request:    req,
+			}
+
+			err := newHTTPError(res, "")
+			if !err.RateLimited() {
+				t.Fatalf("want a rate limit error, got %v", err)
+			}
+			if got := strings.Contains(err.Error(), "GITHUB_TOKEN"); got != tc.wantHint {
+				t.Fatalf("want GITHUB_TOKEN hint: %t, got %q", tc.wantHint, err.Error())
+			}
+		})
+	}
+}
+
+func Test_Client_Download_DoesNotRetryNotFound(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, Options{})
+	c.opts.MaxRetries = 3
+
+	if _, err := c.DownloadFile(context.Background(), server.URL+"/widget"); err == nil {
+		t.Fatal("want error")
+	}
+	if attempts != 1 {
+		t.Fatalf("want 1 attempt, got %d", attempts)
+	}
+}
+
+func Test_setRequestHeaders_GitHubToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	testCases := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://github.com/acme/widget/releases/latest", want: "Bearer secret"},
+		{url: "https://api.github.com/repos/acme/widget", want: "Bearer secret"},
+		{url: "http://github.com/acme/widget", want: ""},
+		{url: "https://example.com/widget", want: ""},
+	}
+
+	for _, tc := range testCases {
+		req, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+		setRequestHeaders(req)
+		if got := req.Header.Get("Authorization"); got != tc.want {
+			t.Errorf("%s: want Authorization %q, got %q", tc.url, tc.want, got)
+		}
+	}
+}
+
+type failingVerifier struct {
+	called bool
+}
+
+func (f *failingVerifier) Verify(ctx context.Context, input VerifyInput) error {
+	f.called = true
+	return fmt.Errorf("checksum mismatch for %s", filepath.Base(input.FilePath))
+}
+
+func Test_Client_Download_Verifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "binary")
+	}))
+	defer server.Close()
+
+	verifier := &failingVerifier{}
+	installDir := t.TempDir()
+	c := newTestClient(t, server, Options{
+		Verify:     true,
+		Verifier:   verifier,
+		InstallDir: installDir,
+	})
+
+	_, _, err := c.Download(context.Background(), testTool(), "x86_64", "linux", "v1.0.0")
+	if err == nil {
+		t.Fatal("want verification error")
+	}
+	if !verifier.called {
+		t.Fatal("want verifier to be called")
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "widget")); !os.IsNotExist(err) {
+		t.Fatalf("want no binary installed after failed verification, got: %v", err)
+	}
+}
+
+func Test_Client_Download_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := newTestClient(t, server, Options{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, _, err := c.Download(ctx, testTool(), "x86_64", "linux", "v1.0.0")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -1,31 +1,24 @@
 package get
 
 import (
-	"bytes"
 	"compress/bzip2"
 	"compress/gzip"
+	"context"
 	"crypto/sha256"
-	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"math"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"text/template"
 	"time"
 
 	units "github.com/docker/go-units"
 	"github.com/ulikunitz/xz"
 
-	"github.com/alexellis/arkade/pkg"
 	"github.com/alexellis/arkade/pkg/archive"
-	"github.com/alexellis/arkade/pkg/config"
-	"github.com/alexellis/arkade/pkg/env"
 	"github.com/mattn/go-isatty"
 )
 
@@ -38,13 +31,6 @@ const (
 	DownloadArkadeDir = iota
 )
 
-type ErrNotFound struct {
-}
-
-func (e *ErrNotFound) Error() string {
-	return "server returned status: 404"
-}
-
 // callbackReader wraps an io.ReadCloser and calls a ProgressCallback
 // on every Read so the caller can track bytes transferred.
 type callbackReader struct {
@@ -91,358 +77,31 @@ func (c *callbackReader) Close() error {
 // the built-in progress bar is suppressed and the callback receives
 // byte counts instead, letting the caller render progress centrally.
 func DownloadWithProgress(tool *Tool, arch, operatingSystem, version string, movePath string, quiet, verify bool, cb ProgressCallback) (string, string, error) {
-	return downloadTool(tool, arch, operatingSystem, version, movePath, false, quiet, verify, cb)
+	client := NewClient(Options{
+		Quiet:      quiet,
+		Verify:     verify,
+		InstallDir: movePath,
+		Progress:   cb,
+	})
+	return client.Download(context.Background(), tool, arch, operatingSystem, version)
 }
 
+// Download downloads a tool and installs it into movePath, or arkade's
+// bin directory when movePath is empty. New code should use a Client.
 func Download(tool *Tool, arch, operatingSystem, version string, movePath string, displayProgress, quiet, verify bool) (string, string, error) {
-	return downloadTool(tool, arch, operatingSystem, version, movePath, displayProgress, quiet, verify, nil)
-}
-
-func downloadTool(tool *Tool, arch, operatingSystem, version string, movePath string, displayProgress, quiet, verify bool, cb ProgressCallback) (string, string, error) {
-
-	downloadURL, resolvedVersion, err := GetDownloadURL(tool,
-		strings.ToLower(operatingSystem),
-		strings.ToLower(arch),
-		version, quiet)
-	if err != nil {
-		return "", "", err
-	}
-
-	if !quiet {
-		log.Printf("Downloading: %s", downloadURL)
-	}
-
-	start := time.Now()
-
-	// When a ProgressCallback is provided the caller owns the display,
-	// so we suppress the built-in per-file progress bar.
-	var outFilePath string
-	if cb != nil {
-		outFilePath, err = downloadFileWithCallback(downloadURL, cb)
-	} else {
-		outFilePath, err = downloadFile(downloadURL, displayProgress)
-	}
-	if err != nil {
-		return "", "", err
-	}
-
-	if !quiet {
-		filename := path.Base(downloadURL)
-		stat, err := os.Stat(outFilePath)
-		size := ""
-		if err == nil {
-			size = "(" + units.HumanSize(float64(stat.Size())) + ")"
-		}
-		log.Printf("Downloaded %s %s in %s.", filename, size, time.Since(start).Round(time.Millisecond))
-	}
-
-	if verify {
-		if tool.VerifyStrategy == ClaudeShasumStrategy {
-			st := time.Now()
-			tmpl := template.New(tool.Name + "sha")
-			tmpl = tmpl.Funcs(templateFuncs)
-			t, err := tmpl.Parse(tool.VerifyTemplate)
-			if err != nil {
-				return "", "", err
-			}
-
-			var buf bytes.Buffer
-			inputs := map[string]string{
-				"Name":          tool.Name,
-				"Owner":         tool.Owner,
-				"Repo":          tool.Repo,
-				"Version":       resolvedVersion,
-				"VersionNumber": strings.TrimPrefix(resolvedVersion, "v"),
-				"Arch":          arch,
-				"OS":            operatingSystem,
-			}
-
-			if err = t.Execute(&buf, inputs); err != nil {
-				return "", "", err
-			}
-
-			verifyURL := strings.TrimSpace(buf.String())
-			if !quiet {
-				log.Printf("Downloading SHA sum from: %s", verifyURL)
-			}
-			shaSumManifest, err := fetchText(verifyURL)
-			if err != nil {
-				return "", "", err
-			}
-
-			var manifest struct {
-				Version   string `json:"version"`
-				BuildDate string `json:"buildDate"`
-				Platforms map[string]struct {
-					Checksum string `json:"checksum"`
-					Size     int64  `json:"size"`
-				} `json:"platforms"`
-			}
-			if err := json.Unmarshal([]byte(shaSumManifest), &manifest); err != nil {
-				return "", "", err
-			}
-
-			var archMappingForClaude = map[string]string{
-				"amd64":   "amd64",
-				"x86_64":  "x64",
-				"arm64":   "arm64",
-				"aarch64": "arm64",
-			}
-
-			platformKey := fmt.Sprintf("%s-%s", strings.ToLower(operatingSystem), archMappingForClaude[arch])
-
-			platformInfo, found := manifest.Platforms[platformKey]
-			if !found {
-				return "", "", fmt.Errorf("no checksum info found for platform: %s", platformKey)
-			}
-
-			if err := verifySHA(platformInfo.Checksum, outFilePath); err != nil {
-				return "", "", err
-			} else if !quiet {
-				log.Printf("SHA sum verified in %s.", time.Since(st).Round(time.Millisecond))
-			}
-
-		} else if tool.VerifyStrategy == HashicorpShasumStrategy {
-			st := time.Now()
-			tmpl := template.New(tool.Name + "sha")
-			tmpl = tmpl.Funcs(templateFuncs)
-			t, err := tmpl.Parse(tool.VerifyTemplate)
-			if err != nil {
-				return "", "", err
-			}
-
-			var buf bytes.Buffer
-			inputs := map[string]string{
-				"Name":          tool.Name,
-				"Owner":         tool.Owner,
-				"Repo":          tool.Repo,
-				"Version":       resolvedVersion,
-				"VersionNumber": strings.TrimPrefix(resolvedVersion, "v"),
-				"Arch":          arch,
-				"OS":            operatingSystem,
-			}
-
-			if err = t.Execute(&buf, inputs); err != nil {
-				return "", "", err
-			}
-
-			verifyURL := strings.TrimSpace(buf.String())
-			if !quiet {
-				log.Printf("Downloading SHA sum from: %s", verifyURL)
-			}
-			shaSum, err := fetchText(verifyURL)
-			if err != nil {
-				return "", "", err
-			}
-			if err := verifySHA(shaSum, outFilePath); err != nil {
-				return "", "", err
-			} else if !quiet {
-				log.Printf("SHA sum verified in %s.", time.Since(st).Round(time.Millisecond))
-			}
-		} else if tool.VerifyStrategy == AmpShasumStrategy {
-			st := time.Now()
-			tmpl := template.New(tool.Name + "sha")
-			tmpl = tmpl.Funcs(templateFuncs)
-			t, err := tmpl.Parse(tool.VerifyTemplate)
-			if err != nil {
-				return "", "", err
-			}
-
-			var buf bytes.Buffer
-			inputs := map[string]string{
-				"Name":          tool.Name,
-				"Owner":         tool.Owner,
-				"Repo":          tool.Repo,
-				"Version":       resolvedVersion,
-				"VersionNumber": strings.TrimPrefix(resolvedVersion, "v"),
-				"Arch":          arch,
-				"OS":            operatingSystem,
-			}
-
-			if err = t.Execute(&buf, inputs); err != nil {
-				return "", "", err
-			}
-
-			verifyURL := strings.TrimSpace(buf.String())
-			if !quiet {
-				log.Printf("Downloading SHA sum from: %s", verifyURL)
-			}
-			shaSum, err := fetchText(verifyURL)
-			if err != nil {
-				return "", "", err
-			}
-			if err := verifySHA(shaSum, outFilePath); err != nil {
-				return "", "", err
-			} else if !quiet {
-				log.Printf("SHA sum verified in %s.", time.Since(st).Round(time.Millisecond))
-			}
-		}
-	}
-
-	if isArchiveStr(downloadURL) {
-
-		outPath, err := decompress(tool, downloadURL, outFilePath, operatingSystem, arch, version, quiet)
-		if err != nil {
-			return "", "", err
-		}
-
-		outFilePath = outPath
-		if v, ok := os.LookupEnv("ARK_DEBUG"); ok && v == "1" {
-			log.Printf("Extracted: %s", outFilePath)
-		}
-	}
-
-	finalName := tool.Name
-	if strings.Contains(strings.ToLower(operatingSystem), "mingw") && !tool.NoExtension {
-		finalName = finalName + ".exe"
-	}
-
-	var localPath string
-
-	if movePath == "" {
-		_, err := config.InitUserDir()
-		if err != nil {
-			return "", "", err
-		}
-
-		localPath = env.LocalBinary(finalName, "")
-	} else {
-		localPath = filepath.Join(movePath, finalName)
-	}
-
-	if v, ok := os.LookupEnv("ARK_DEBUG"); ok && v == "1" {
-		log.Printf("Copying %s to %s\n", outFilePath, localPath)
-	}
-
-	if _, err = atomicCopyFile(outFilePath, localPath, 0755); err != nil {
-		return "", "", err
-	}
-
-	// Remove parent folder of the binary
-	tempPath := filepath.Dir(outFilePath)
-	if err := os.RemoveAll(tempPath); err != nil {
-		log.Printf("Error removing temporary directory: %s", err)
-	}
-
-	outFilePath = localPath
-
-	return outFilePath, finalName, nil
+	client := NewClient(Options{
+		Quiet:           quiet,
+		Verify:          verify,
+		InstallDir:      movePath,
+		DisplayProgress: displayProgress,
+	})
+	return client.Download(context.Background(), tool, arch, operatingSystem, version)
 }
 
 // DownloadFile downloads a file to a temporary directory
 // and returns the path to the file and any error.
 func DownloadFileP(downloadURL string, displayProgress bool) (string, error) {
-	return downloadFile(downloadURL, displayProgress)
-}
-
-func downloadFile(downloadURL string, displayProgress bool) (string, error) {
-	return retryWithBackoff(func() (string, error) {
-		req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
-		if err != nil {
-			return "", err
-		}
-
-		req.Header.Set("User-Agent", pkg.UserAgent())
-
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return "", err
-		}
-
-		if res.Body != nil {
-			defer res.Body.Close()
-		}
-
-		if res.StatusCode == http.StatusNotFound {
-			return "", &ErrNotFound{}
-		}
-
-		if res.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("server returned status: %d", res.StatusCode)
-		}
-
-		_, fileName := path.Split(downloadURL)
-		tmp := os.TempDir()
-
-		customTmp, err := os.MkdirTemp(tmp, "arkade-*")
-		if err != nil {
-			return "", err
-		}
-
-		outFilePath := path.Join(customTmp, fileName)
-		wrappedReader := withProgressBar(res.Body, int(res.ContentLength), displayProgress)
-
-		// Owner/Group read/write/execute
-		// World - execute
-		out, err := os.OpenFile(outFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0775)
-		if err != nil {
-			return "", err
-		}
-
-		defer out.Close()
-		defer wrappedReader.Close()
-
-		if _, err := io.Copy(out, wrappedReader); err != nil {
-			return "", err
-		}
-
-		return outFilePath, nil
-	}, 10, 100*time.Millisecond)
-}
-
-// downloadFileWithCallback is like downloadFile but wraps the response
-// body with a callbackReader so the caller gets byte-level progress.
-func downloadFileWithCallback(downloadURL string, cb ProgressCallback) (string, error) {
-	return retryWithBackoff(func() (string, error) {
-		req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
-		if err != nil {
-			return "", err
-		}
-
-		req.Header.Set("User-Agent", pkg.UserAgent())
-
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return "", err
-		}
-
-		if res.Body != nil {
-			defer res.Body.Close()
-		}
-
-		if res.StatusCode == http.StatusNotFound {
-			return "", &ErrNotFound{}
-		}
-
-		if res.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("server returned status: %d", res.StatusCode)
-		}
-
-		_, fileName := path.Split(downloadURL)
-		tmp := os.TempDir()
-
-		customTmp, err := os.MkdirTemp(tmp, "arkade-*")
-		if err != nil {
-			return "", err
-		}
-
-		outFilePath := path.Join(customTmp, fileName)
-		wrappedReader := newCallbackReader(res.Body, res.ContentLength, cb)
-
-		out, err := os.OpenFile(outFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0775)
-		if err != nil {
-			return "", err
-		}
-
-		defer out.Close()
-		defer wrappedReader.Close()
-
-		if _, err := io.Copy(out, wrappedReader); err != nil {
-			return "", err
-		}
-
-		return outFilePath, nil
-	}, 10, 100*time.Millisecond)
+	return NewClient(Options{DisplayProgress: displayProgress}).DownloadFile(context.Background(), downloadURL)
 }
 
 func CopyFile(src, dst string) (int64, error) {
@@ -757,7 +416,7 @@ func (l *lineProgressReader) report() {
 	}
 }
 
-func decompress(tool *Tool, downloadURL, outFilePath, operatingSystem, arch, version string, quiet bool) (string, error) {
+func (c *Client) decompress(tool *Tool, downloadURL, outFilePath, operatingSystem, arch, version string) (string, error) {
 
 	archiveFile, err := os.Open(outFilePath)
 	if err != nil {
@@ -810,9 +469,7 @@ func decompress(tool *Tool, downloadURL, outFilePath, operatingSystem, arch, ver
 			return "", err
 		}
 
-		if !quiet {
-			log.Printf("Name: %s, size: %d", fInfo.Name(), fInfo.Size())
-		}
+		c.logf("Name: %s, size: %d", fInfo.Name(), fInfo.Size())
 
 		if err := archive.Unzip(archiveFile, fInfo.Size(), outFilePathDir, forceQuiet); err != nil {
 			return "", err
@@ -874,32 +531,6 @@ func unbzip(archiveFile *os.File, outFilePath string) error {
 	return out.Close()
 }
 
-func fetchText(url string) (string, error) {
-	return retryWithBackoff(func() (string, error) {
-		req, err := http.NewRequest(http.MethodGet, url, nil)
-		if err != nil {
-			return "", err
-		}
-		req.Header.Set("User-Agent", pkg.UserAgent())
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return "", err
-		}
-
-		var body []byte
-		if res.Body != nil {
-			defer res.Body.Close()
-			body, _ = io.ReadAll(res.Body)
-		}
-
-		if res.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("unexpected status code %d, body: %s", res.StatusCode, string(body))
-		}
-
-		return string(body), nil
-	}, 10, 100*time.Millisecond)
-}
-
 func verifySHA(shaSum, outFilePath string) error {
 
 	outFileBaseName := filepath.Base(outFilePath)
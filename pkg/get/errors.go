@@ -0,0 +1,192 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package get
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRateLimitWait is the longest a retry will wait for a rate limit
+// to reset, beyond that the error is returned to the user instead.
+const maxRateLimitWait = time.Minute
+
+// ErrNotFound is matched by errors.Is for any HTTP 404 response.
+type ErrNotFound struct {
+}
+
+func (e *ErrNotFound) Error() string {
+	return "server returned status: 404"
+}
+
+func (e *ErrNotFound) Is(target error) bool {
+	_, ok := target.(*ErrNotFound)
+	return ok
+}
+
+// HTTPError is returned when a server responds with an unexpected
+// status code.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+
+	// Body holds the start of the response body, if it was read.
+	Body string
+
+	// RetryAfter is parsed from the Retry-After header.
+	RetryAfter time.Duration
+
+	// RateLimitRemaining and RateLimitReset are parsed from GitHub's
+	// X-RateLimit-Remaining and X-RateLimit-Reset headers.
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+
+	// Authenticated is true when the request sent a GITHUB_TOKEN.
+	Authenticated bool
+
+	hasRateLimitHeaders bool
+}
+
+func newHTTPError(res *http.Response, body string) *HTTPError {
+	e := &HTTPError{
+		StatusCode: res.StatusCode,
+		Body:       strings.TrimSpace(body),
+	}
+	if res.Request != nil {
+		if res.Request.URL != nil {
+			e.URL = res.Request.URL.String()
+		}
+		e.Authenticated = len(res.Request.Header.Get("Authorization")) > 0
+	}
+	if len(e.Body) > 512 {
+		e.Body = e.Body[:512]
+	}
+
+	if v := res.Header.Get("Retry-After"); len(v) > 0 {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		} else if t, err := http.ParseTime(v); err == nil {
+			e.RetryAfter = time.Until(t)
+		}
+	}
+
+	if v := res.Header.Get("X-RateLimit-Remaining"); len(v) > 0 {
+		if remaining, err := strconv.Atoi(v); err == nil {
+			e.RateLimitRemaining = remaining
+			e.hasRateLimitHeaders = true
+		}
+	}
+	if v := res.Header.Get("X-RateLimit-Reset"); len(v) > 0 {
+		if reset, err := strconv.ParseInt(v, 10, 64); err == nil {
+			e.RateLimitReset = time.Unix(reset, 0)
+		}
+	}
+
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.RateLimited() {
+		msg := fmt.Sprintf("rate limited by %s (status: %d)", e.host(), e.StatusCode)
+		if !e.RateLimitReset.IsZero() {
+			msg += fmt.Sprintf(", resets at %s", e.RateLimitReset.Local().Format(time.Kitchen))
+		}
+		if !e.Authenticated && (e.hasRateLimitHeaders || strings.Contains(e.host(), "github")) {
+			msg += ", set GITHUB_TOKEN to raise the limit"
+		}
+		return msg
+	}
+
+	msg := fmt.Sprintf("server returned status: %d", e.StatusCode)
+	if len(e.URL) > 0 {
+		msg += fmt.Sprintf(" for %s", e.URL)
+	}
+	if len(e.Body) > 0 {
+		msg += fmt.Sprintf(", body: %s", e.Body)
+	}
+	return msg
+}
+
+// Is allows errors.Is(err, &ErrNotFound{}) to match a 404.
+func (e *HTTPError) Is(target error) bool {
+	_, ok := target.(*ErrNotFound)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// RateLimited is true for a 429, or a 403 from GitHub once the
+// remaining quota reaches zero.
+func (e *HTTPError) RateLimited() bool {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return e.StatusCode == http.StatusForbidden &&
+		e.hasRateLimitHeaders && e.RateLimitRemaining == 0
+}
+
+// Temporary is true when the request may succeed if retried.
+func (e *HTTPError) Temporary() bool {
+	return e.RateLimited() || e.StatusCode >= http.StatusInternalServerError
+}
+
+// RetryDelay returns how long the server asked the client to wait, or
+// zero when it gave no hint.
+func (e *HTTPError) RetryDelay() time.Duration {
+	if e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+	if e.RateLimited() && !e.RateLimitReset.IsZero() {
+		if d := time.Until(e.RateLimitReset); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func (e *HTTPError) host() string {
+	if u, err := url.Parse(e.URL); err == nil && len(u.Host) > 0 {
+		return u.Host
+	}
+	return "server"
+}
+
+func isPermanentError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.Op == "parse" {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		if !httpErr.Temporary() {
+			return true
+		}
+
+		// Don't sit waiting for a rate limit which resets in an hour
+		return httpErr.RetryDelay() > maxRateLimitWait
+	}
+
+	return false
+}
+
+// retryDelay returns the wait before the next attempt, preferring the
+// server's hint over exponential backoff.
+func retryDelay(err error, attempt int, initialBackoff time.Duration) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		if d := httpErr.RetryDelay(); d > 0 {
+			return d
+		}
+	}
+
+	backoff := initialBackoff * time.Duration(1<<uint(attempt-1))
+	if backoff > 10*time.Second {
+		backoff = 10 * time.Second
+	}
+	return backoff
+}
@@ -2,14 +2,12 @@ package get
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
-	"net"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,6 +15,7 @@ import (
 	"time"
 
 	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/config"
 	"github.com/alexellis/arkade/pkg/env"
 )
 
@@ -34,15 +33,23 @@ var supportedArchitectures = [...]string{"x86_64", "arm", "amd64", "armv6l", "ar
 
 // retryWithBackoff implements exponential backoff with retry logic
 func retryWithBackoff(fn func() (string, error), maxRetries int, initialBackoff time.Duration) (string, error) {
+	return retryWithBackoffContext(context.Background(), log.Printf, fn, maxRetries, initialBackoff)
+}
+
+// retryWithBackoffContext is like retryWithBackoff, but stops waiting
+// between attempts when ctx is cancelled.
+func retryWithBackoffContext(ctx context.Context, logf func(format string, v ...interface{}), fn func() (string, error), maxRetries int, initialBackoff time.Duration) (string, error) {
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			backoff := initialBackoff * time.Duration(1<<uint(attempt-1))
-			if backoff > 10*time.Second {
-				backoff = 10 * time.Second
+			backoff := retryDelay(lastErr, attempt, initialBackoff)
+			logf("Attempt %d: Retrying after %v due to: %v", attempt, backoff, lastErr)
+
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(backoff):
 			}
-			log.Printf("Attempt %d: Retrying after %v due to: %v", attempt, backoff, lastErr)
-			time.Sleep(backoff)
 		}
 
 		result, err := fn()
@@ -52,30 +59,14 @@ func retryWithBackoff(fn func() (string, error), maxRetries int, initialBackoff
 
 		lastErr = err
 
-		// Don't retry permanent errors
-		if isPermanentError(err) {
+		// Don't retry permanent errors, or once the caller gave up
+		if isPermanentError(err) || ctx.Err() != nil {
 			return "", err
 		}
 	}
 	return "", fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
 }
 
-func isPermanentError(err error) bool {
-	var urlErr *url.Error
-	if errors.As(err, &urlErr) && urlErr.Op == "parse" {
-		return true
-	}
-
-	// 404, 429 are permanent errors
-	if strings.Contains(err.Error(), "404") {
-		return true
-	}
-	if strings.Contains(err.Error(), "429") {
-		return true
-	}
-	return false
-}
-
 // Tool describes how to download a CLI tool from a binary
 // release - whether a single binary, or an archive.
 type Tool struct {
@@ -156,6 +147,25 @@ type ToolLocal struct {
 
 var templateFuncs = map[string]interface{}{
 	"HasPrefix": func(s, prefix string) bool { return strings.HasPrefix(s, prefix) },
+	"GoOS":      env.NormaliseOS,
+	"GoArch":    func(arch string) string { a, _ := env.NormaliseArch(arch); return a },
+	"UnameArch": func(arch string) string { return env.NewPlatform("linux", arch).UnameArch() },
+}
+
+// withPlatform adds the canonical platform values to a template's inputs
+// so that templates can use .GoOS, .GoArch and .Variant instead of
+// matching every alias of .OS and .Arch. .Libc is "gnu" or "musl" when
+// the target is Linux, so that tools can prefer -musl assets on Alpine.
+func withPlatform(inputs map[string]string) map[string]string {
+	p := env.NewPlatform(inputs["OS"], inputs["Arch"])
+	inputs["GoOS"] = p.OS
+	inputs["GoArch"] = p.Arch
+	inputs["Variant"] = p.Variant
+	inputs["Libc"] = ""
+	if p.OS == "linux" {
+		inputs["Libc"] = env.GetClientLibc()
+	}
+	return inputs
 }
 
 func (tool Tool) IsArchive(quiet bool) (bool, error) {
@@ -186,29 +196,7 @@ func isArchiveStr(downloadURL string) bool {
 // non-empty it is returned as-is. Otherwise the latest release is
 // looked up using the tool's VersionStrategy.
 func ResolveVersion(tool *Tool, version string) (string, error) {
-	ver := GetToolVersion(tool, version)
-	if len(ver) > 0 {
-		return ver, nil
-	}
-
-	var releaseType string
-	if len(tool.URLTemplate) == 0 ||
-		strings.Contains(tool.URLTemplate, "https://github.com/") {
-		releaseType = GitHubVersionStrategy
-	}
-	if len(tool.VersionStrategy) > 0 {
-		releaseType = tool.VersionStrategy
-	}
-
-	if _, supported := releaseLocations[releaseType]; supported {
-		v, err := FindRelease(releaseType, tool.Owner, tool.Repo)
-		if err != nil {
-			return "", err
-		}
-		return v, nil
-	}
-
-	return ver, nil
+	return NewClient(Options{}).ResolveVersion(context.Background(), tool, version)
 }
 
 // GetDownloadURL fetches the download URL for a release of a tool
@@ -256,74 +244,10 @@ func (tool Tool) Head(uri string) (int, string, http.Header, error) {
 }
 
 func (tool Tool) GetURL(os, arch, version string, quiet bool) (string, string, error) {
-	resolvedVersion := version
-
-	if len(version) == 0 {
-
-		if !quiet {
-			log.Printf("Looking up version for: %s", tool.Name)
-		}
-
-		var releaseType string
-		if len(tool.URLTemplate) == 0 ||
-			strings.Contains(tool.URLTemplate, "https://github.com/") {
-
-			releaseType = GitHubVersionStrategy
-
-		}
-
-		if len(tool.VersionStrategy) > 0 {
-			releaseType = tool.VersionStrategy
-		}
-
-		if _, supported := releaseLocations[releaseType]; supported {
-
-			start := time.Now()
-			v, err := FindRelease(releaseType, tool.Owner, tool.Repo)
-			if err != nil {
-				return "", "", err
-			}
-			version = v
-			if !quiet {
-				log.Printf("Found %s version: %s (in %s)", tool.Name, version, time.Since(start).Round(time.Millisecond))
-			}
-		}
-
-		resolvedVersion = version
-
-		if len(tool.URLTemplate) > 0 {
-			res, err := getByDownloadTemplate(tool, os, arch, version)
-			if err != nil {
-				return "", "", err
-			}
-			return res, resolvedVersion, nil
-		}
-
-		res, err := getURLByGithubTemplate(tool, os, arch, version)
-		if err != nil {
-			return "", "", err
-		}
-		return res, resolvedVersion, nil
-	}
-
-	resolvedVersion = version
-
-	if len(tool.URLTemplate) > 0 {
-		res, err := getByDownloadTemplate(tool, os, arch, version)
-		if err != nil {
-			return "", "", err
-		}
-		return res, resolvedVersion, nil
-	}
-
-	res, err := getURLByGithubTemplate(tool, os, arch, version)
-	if err != nil {
-		return "", "", err
-	}
-	return res, resolvedVersion, nil
+	return NewClient(Options{Quiet: quiet}).GetURL(context.Background(), &tool, os, arch, version)
 }
 
-func getURLByGithubTemplate(tool Tool, os, arch, version string) (string, error) {
+func getDownloadNameByGithubTemplate(tool Tool, os, arch, version string) (string, error) {
 
 	var err error
 	t := template.New(tool.Name + "binary")
@@ -342,71 +266,22 @@ func getURLByGithubTemplate(tool Tool, os, arch, version string) (string, error)
 		"VersionNumber": strings.TrimPrefix(version, "v"),
 	}
 
-	err = t.Execute(&buf, pref)
+	err = t.Execute(&buf, withPlatform(pref))
 	if err != nil {
 		return "", err
 	}
 
-	downloadName := sanitizeTemplateResult(buf.String())
-
-	return getBinaryURL(tool.Owner, tool.Repo, version, downloadName), nil
+	return sanitizeTemplateResult(buf.String()), nil
 }
 
 func FindGitHubRelease(owner, repo string) (string, error) {
 	return FindRelease(GitHubVersionStrategy, owner, repo)
 }
 
+// FindRelease looks up the latest version of a tool using the release
+// location registered for a VersionStrategy.
 func FindRelease(location, owner, repo string) (string, error) {
-	url := formatUrl(releaseLocations[location].Url, owner, repo)
-
-	clientTimeout := releaseLocations[location].Timeout
-	client := makeHTTPClient(&clientTimeout, false)
-	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		return http.ErrUseLastResponse
-	}
-
-	return retryWithBackoff(func() (string, error) {
-		req, err := http.NewRequest(releaseLocations[location].Method, url, nil)
-		if err != nil {
-			return "", err
-		}
-
-		req.Header.Set("User-Agent", pkg.UserAgent())
-
-		res, err := client.Do(req)
-		if err != nil {
-			return "", err
-		}
-
-		defer res.Body.Close()
-
-		if releaseLocations[location].Method == http.MethodHead {
-
-			if res.StatusCode != http.StatusMovedPermanently && res.StatusCode != http.StatusFound {
-				return "", fmt.Errorf("server returned status: %d", res.StatusCode)
-			}
-
-			loc := res.Header.Get("Location")
-			if len(loc) == 0 {
-				return "", fmt.Errorf("unable to determine release of tool")
-			}
-
-			version := loc[strings.LastIndex(loc, "/")+1:]
-			return version, nil
-		}
-
-		if res.Body == nil {
-			return "", fmt.Errorf("unable to determine release of tool")
-		}
-
-		bodyBytes, err := io.ReadAll(res.Body)
-		if err != nil {
-			return "", err
-		}
-
-		version := strings.TrimSpace(string(bodyBytes))
-		return version, nil
-	}, 10, 100*time.Millisecond)
+	return NewClient(Options{}).FindRelease(context.Background(), location, owner, repo)
 }
 
 func formatUrl(url, owner, repo string) string {
@@ -447,7 +322,7 @@ func getByDownloadTemplate(tool Tool, os, arch, version string) (string, error)
 		"Name":          tool.Name,
 	}
 
-	if err := t.Execute(&buf, inputs); err != nil {
+	if err := t.Execute(&buf, withPlatform(inputs)); err != nil {
 		return "", err
 	}
 
@@ -455,43 +330,6 @@ func getByDownloadTemplate(tool Tool, os, arch, version string) (string, error)
 	return res, nil
 }
 
-// makeHTTPClient makes a HTTP client with good defaults for timeouts.
-func makeHTTPClient(timeout *time.Duration, tlsInsecure bool) http.Client {
-	return makeHTTPClientWithDisableKeepAlives(timeout, tlsInsecure, false)
-}
-
-// makeHTTPClientWithDisableKeepAlives makes a HTTP client with good defaults for timeouts.
-func makeHTTPClientWithDisableKeepAlives(timeout *time.Duration, tlsInsecure bool, disableKeepAlives bool) http.Client {
-	client := http.Client{}
-
-	if timeout != nil || tlsInsecure {
-		tr := &http.Transport{
-			Proxy:             http.ProxyFromEnvironment,
-			DisableKeepAlives: disableKeepAlives,
-		}
-
-		if timeout != nil {
-			client.Timeout = *timeout
-			tr.DialContext = (&net.Dialer{
-				Timeout: *timeout,
-			}).DialContext
-
-			tr.IdleConnTimeout = 120 * time.Millisecond
-			tr.ExpectContinueTimeout = 1500 * time.Millisecond
-		}
-
-		if tlsInsecure {
-			tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: tlsInsecure}
-		}
-
-		tr.DisableKeepAlives = disableKeepAlives
-
-		client.Transport = tr
-	}
-
-	return client
-}
-
 // GetBinaryName returns the name of a binary for the given tool or an
 // error if the tool's template cannot be parsed or executed.
 func GetBinaryName(tool *Tool, os, arch, version string) (string, error) {
@@ -507,13 +345,13 @@ func GetBinaryName(tool *Tool, os, arch, version string) (string, error) {
 
 		var buf bytes.Buffer
 		ver := GetToolVersion(tool, version)
-		if err := t.Execute(&buf, map[string]string{
+		if err := t.Execute(&buf, withPlatform(map[string]string{
 			"OS":            os,
 			"Arch":          arch,
 			"Name":          tool.Name,
 			"Version":       ver,
 			"VersionNumber": strings.TrimPrefix(ver, "v"),
-		}); err != nil {
+		})); err != nil {
 			return "", err
 		}
 
@@ -589,7 +427,7 @@ func PostInstallationMsg(movePath string, localToolsStore []ToolLocal) ([]byte,
 	} else if ArkadeInPath() {
 		if multi {
 			buf.WriteString("# Install to system (optional):\n")
-			buf.WriteString("sudo mv $HOME/.arkade/bin/* /usr/local/bin/\n")
+			fmt.Fprintf(&buf, "sudo mv %s/* /usr/local/bin/\n", displayBinDir())
 		} else {
 			buf.WriteString("# Install to system (optional):\n")
 			for _, tl := range localToolsStore {
@@ -602,7 +440,7 @@ func PostInstallationMsg(movePath string, localToolsStore []ToolLocal) ([]byte,
 		buf.WriteString("\n")
 		if multi {
 			buf.WriteString("# Install to system (optional):\n")
-			buf.WriteString("sudo mv $HOME/.arkade/bin/* /usr/local/bin/\n")
+			fmt.Fprintf(&buf, "sudo mv %s/* /usr/local/bin/\n", displayBinDir())
 		} else {
 			buf.WriteString("# Install to system (optional):\n")
 			for _, tl := range localToolsStore {
@@ -617,14 +455,28 @@ func PostInstallationMsg(movePath string, localToolsStore []ToolLocal) ([]byte,
 func pathExportInstructions() string {
 	initFile := detectShellInitFile()
 
+	binDir := displayBinDir()
+
 	switch initFile {
 	case "~/.config/fish/config.fish":
-		return "set -U fish_user_paths $HOME/.arkade/bin $fish_user_paths\n"
+		return fmt.Sprintf("set -U fish_user_paths %s $fish_user_paths\n", binDir)
 	default:
-		return fmt.Sprintf("echo 'export PATH=\"$HOME/.arkade/bin:$PATH\"' >> %s\nexport PATH=\"$HOME/.arkade/bin:$PATH\"\n", initFile)
+		return fmt.Sprintf("echo 'export PATH=\"%s:$PATH\"' >> %s\nexport PATH=\"%s:$PATH\"\n", binDir, initFile, binDir)
 	}
 }
 
+// displayBinDir returns arkade's bin directory for use in instructions,
+// with the home directory written as $HOME where possible.
+func displayBinDir() string {
+	binDir := config.GetPaths().Bin
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 0 {
+		if rel, err := filepath.Rel(home, binDir); err == nil && !strings.HasPrefix(rel, "..") {
+			return "$HOME/" + filepath.ToSlash(rel)
+		}
+	}
+	return binDir
+}
+
 func detectShellInitFile() string {
 	shell := filepath.Base(os.Getenv("SHELL"))
 
@@ -660,9 +512,16 @@ func detectShellInitFile() string {
 	return "~/.profile"
 }
 
-// ArkadeInPath returns true when $PATH already contains .arkade/bin.
+// ArkadeInPath returns true when $PATH already contains arkade's bin
+// directory, or a .arkade/bin directory.
 func ArkadeInPath() bool {
-	return strings.Contains(os.Getenv("PATH"), ".arkade/bin")
+	binDir := filepath.Clean(config.GetPaths().Bin)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(dir) == binDir || strings.Contains(dir, ".arkade/bin") {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateOS returns whether a given operating system is supported
@@ -666,6 +666,9 @@ sudo install -m 755 /tmp/bin/jq-linux64 /usr/local/bin/jq`,
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Setenv("HOME", t.TempDir())
+			t.Setenv("ARKADE_HOME", "")
+			t.Setenv("XDG_DATA_HOME", "")
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 			if tt.pathEnv != "" {
 				t.Setenv("PATH", tt.pathEnv)
 			}
@@ -889,6 +892,10 @@ func Test_DownloadArkade(t *testing.T) {
 			arch:    arch64bit,
 			version: "0.8.28",
 			url:     "https://github.com/alexellis/arkade/releases/download/0.8.28/arkade.exe"},
+		{os: "windows",
+			arch:    "amd64",
+			version: "0.8.28",
+			url:     "https://github.com/alexellis/arkade/releases/download/0.8.28/arkade.exe"},
 		{os: "darwin",
 			arch:    arch64bit,
 			version: "0.8.28",
@@ -8081,6 +8088,8 @@ func Test_DownloadOpenshiftCLI(t *testing.T) {
 }
 
 func Test_DownloadAtuin(t *testing.T) {
+	t.Setenv("ARKADE_LIBC", "gnu")
+
 	tools := MakeTools()
 	name := "atuin"
 
@@ -8127,6 +8136,48 @@ func Test_DownloadAtuin(t *testing.T) {
 
 }
 
+func Test_DownloadAtuinMusl(t *testing.T) {
+	t.Setenv("ARKADE_LIBC", "musl")
+
+	tools := MakeTools()
+	name := "atuin"
+
+	tool := getTool(name, tools)
+
+	const toolVersion = "v18.2.0"
+
+	tests := []test{
+		{
+			os:      "linux",
+			arch:    archARM64,
+			version: toolVersion,
+			url:     "https://github.com/atuinsh/atuin/releases/download/v18.2.0/atuin-aarch64-unknown-linux-musl.tar.gz",
+		},
+		{
+			os:      "linux",
+			arch:    arch64bit,
+			version: toolVersion,
+			url:     "https://github.com/atuinsh/atuin/releases/download/v18.2.0/atuin-x86_64-unknown-linux-musl.tar.gz",
+		},
+		{
+			os:      "darwin",
+			arch:    arch64bit,
+			version: toolVersion,
+			url:     "https://github.com/atuinsh/atuin/releases/download/v18.2.0/atuin-x86_64-apple-darwin.tar.gz",
+		},
+	}
+
+	for _, tc := range tests {
+		got, _, err := tool.GetURL(tc.os, tc.arch, tc.version, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.url {
+			t.Errorf("\nwant: %s, \n got: %s", tc.url, got)
+		}
+	}
+}
+
 func Test_Copacetic(t *testing.T) {
 	tools := MakeTools()
 	name := "copa"
@@ -9777,6 +9828,8 @@ func Test_Download_pulumi(t *testing.T) {
 }
 
 func Test_DownloadNushell(t *testing.T) {
+	t.Setenv("ARKADE_LIBC", "gnu")
+
 	tools := MakeTools()
 	name := "nu"
 
@@ -9828,6 +9881,41 @@ func Test_DownloadNushell(t *testing.T) {
 	}
 }
 
+func Test_DownloadNushellMusl(t *testing.T) {
+	t.Setenv("ARKADE_LIBC", "musl")
+
+	tools := MakeTools()
+	name := "nu"
+
+	tool := getTool(name, tools)
+
+	const toolVersion = "0.108.0"
+
+	tests := []test{
+		{
+			os:      "linux",
+			arch:    arch64bit,
+			version: toolVersion,
+			url:     "https://github.com/nushell/nushell/releases/download/0.108.0/nu-0.108.0-x86_64-unknown-linux-musl.tar.gz",
+		},
+		{
+			os:      "linux",
+			arch:    archARM64,
+			version: toolVersion,
+			url:     "https://github.com/nushell/nushell/releases/download/0.108.0/nu-0.108.0-aarch64-unknown-linux-musl.tar.gz",
+		},
+	}
+	for _, tc := range tests {
+		got, _, err := tool.GetURL(tc.os, tc.arch, tc.version, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.url {
+			t.Errorf("want: %s, got: %s", tc.url, got)
+		}
+	}
+}
+
 func Test_DownloadDirenv(t *testing.T) {
 	tools := MakeTools()
 	name := "direnv"
@@ -230,24 +230,12 @@ https://github.com/{{.Owner}}/{{.Repo}}/releases/download/{{.Version}}/{{$fileNa
 			VerifyStrategy: ClaudeShasumStrategy,
 			VerifyTemplate: `https://storage.googleapis.com/claude-code-dist-86c565f3-f756-42ad-8dfa-d59b1c096819/claude-code-releases/{{.VersionNumber}}/manifest.json`, VersionStrategy: GitHubVersionStrategy,
 			URLTemplate: `
-{{$os := .OS}}
-{{$arch := .Arch}}
-
-{{ if or (eq .Arch "x86_64") (eq .Arch "amd64") -}}
+{{$arch := .GoArch}}
+{{ if eq .GoArch "amd64" -}}
 {{ $arch = "x64" }}
-{{- else if or (eq .Arch "arm64") (eq .Arch "aarch64") -}}
-{{ $arch = "arm64" }}
 {{- end}}
 
-{{ if HasPrefix .OS "ming" -}}
-{{ $os = "windows" }}
-{{- else if eq .OS "darwin" -}}
-{{ $os = "darwin" }}
-{{- else -}}
-{{ $os = "linux" }}
-{{- end -}}
-
-https://storage.googleapis.com/claude-code-dist-86c565f3-f756-42ad-8dfa-d59b1c096819/claude-code-releases/{{.VersionNumber}}/{{$os}}-{{$arch}}/claude
+https://storage.googleapis.com/claude-code-dist-86c565f3-f756-42ad-8dfa-d59b1c096819/claude-code-releases/{{.VersionNumber}}/{{.GoOS}}-{{$arch}}/claude
 `})
 
 	// Amp CLI
@@ -769,7 +757,7 @@ https://github.com/{{.Owner}}/{{.Repo}}/releases/download/{{.Version}}/k8sgpt_{{
 			Repo:        "arkade",
 			Name:        "arkade",
 			Description: "Portable marketplace for downloading your favourite DevOps CLIs and installing helm charts, with a single command.",
-			BinaryTemplate: `{{ if HasPrefix .OS "ming" -}}
+			BinaryTemplate: `{{ if eq .GoOS "windows" -}}
 			{{.Name}}.exe
 			{{- else if eq .OS "darwin" -}}
 				{{ if eq .Arch "arm64" -}}
@@ -4470,6 +4458,8 @@ https://github.com/{{.Owner}}/{{.Repo}}/releases/download/{{.Version}}/{{.Name}}
 
 					{{- if eq .OS "darwin" -}}
 						{{$os = "apple-darwin"}}
+					{{- else if and (eq .OS "linux") (eq .Libc "musl") -}}
+						{{$os = "unknown-linux-musl"}}
 					{{- else if eq .OS "linux" -}}
 						{{$os = "unknown-linux-gnu"}}
 					{{- end -}}
@@ -5361,6 +5351,8 @@ https://github.com/BurntSushi/ripgrep/releases/download/{{.Version}}/ripgrep-{{.
 {{- if eq .OS "linux" -}}
 	{{- if eq .Arch "x86_64" -}}
 		{{$target = "x86_64-unknown-linux-musl"}}
+	{{ else if and (eq .Arch "aarch64") (eq .Libc "musl") -}}
+		{{$target = "aarch64-unknown-linux-musl"}}
 	{{ else if eq .Arch "aarch64" -}}
 		{{$target = "aarch64-unknown-linux-gnu"}}
 	{{ else if eq .Arch "armv7l" -}}
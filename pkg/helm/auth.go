@@ -0,0 +1,120 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// AddHelmRepoWithAuth adds a private chart repository. The password is
+// given to helm on stdin, so it is never part of the command, and it is
+// stored with the repository for later pulls and upgrades.
+func AddHelmRepoWithAuth(name, url string, update bool, auth types.HelmRepoAuth) error {
+	if len(auth.Password) > 0 && len(auth.Username) == 0 {
+		return errors.New("a username is required with a password for a chart repository")
+	}
+
+	if index := strings.Index(name, "/"); index > -1 {
+		name = name[:index]
+	}
+
+	task := execute.ExecTask{
+		Command:     "helm",
+		Args:        append([]string{"repo", "add", name, url, "--force-update"}, repoAuthArgs(auth)...),
+		Env:         os.Environ(),
+		StreamStdio: true,
+	}
+	if len(auth.Password) > 0 {
+		task.Stdin = strings.NewReader(auth.Password)
+	}
+
+	res, err := k8s.Run(context.Background(), task)
+	if err := checkResult("repo add", name, res, err); err != nil {
+		return err
+	}
+
+	if update {
+		task := execute.ExecTask{
+			Command:     "helm",
+			Args:        []string{"repo", "update"},
+			Env:         os.Environ(),
+			StreamStdio: true,
+		}
+		res, err := k8s.Run(context.Background(), task)
+		if err := checkResult("repo update", "", res, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// repoAuthArgs are the flags for "helm repo add", the password is read
+// from stdin.
+func repoAuthArgs(auth types.HelmRepoAuth) []string {
+	var args []string
+	if len(auth.Username) > 0 {
+		args = append(args, "--username", auth.Username)
+	}
+	if len(auth.Password) > 0 {
+		args = append(args, "--password-stdin")
+	}
+	return append(args, tlsArgs(auth)...)
+}
+
+func tlsArgs(auth types.HelmRepoAuth) []string {
+	var args []string
+	if len(auth.CAFile) > 0 {
+		args = append(args, "--ca-file", auth.CAFile)
+	}
+	if len(auth.CertFile) > 0 {
+		args = append(args, "--cert-file", auth.CertFile)
+	}
+	if len(auth.KeyFile) > 0 {
+		args = append(args, "--key-file", auth.KeyFile)
+	}
+	return args
+}
+
+// ociAuthArgs are the flags for helm to pull an OCI chart. Credentials
+// come from the Docker config written by "arkade oci login", or
+// "docker login", including any credential helper configured there.
+func ociAuthArgs(auth types.HelmRepoAuth) ([]string, error) {
+	if len(auth.Username) > 0 || len(auth.Password) > 0 {
+		return nil, errors.New(`a username and password can't be given for an OCI registry, run "arkade oci login" first`)
+	}
+
+	var args []string
+	if registryConfig := dockerConfigFile(); len(registryConfig) > 0 {
+		args = append(args, "--registry-config", registryConfig)
+	}
+	return append(args, tlsArgs(auth)...), nil
+}
+
+// dockerConfigFile returns the path of the Docker config file when it
+// exists, honouring DOCKER_CONFIG.
+func dockerConfigFile() string {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	file := filepath.Join(dir, "config.json")
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
@@ -0,0 +1,108 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
+)
+
+func Test_AddHelmRepoWithAuth_PasswordOnStdin(t *testing.T) {
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	auth := types.HelmRepoAuth{
+		Username: "ci",
+		Password: "s3cr3t",
+		CAFile:   "/etc/ssl/ca.crt",
+	}
+	if err := AddHelmRepoWithAuth("internal/api", "https://charts.example.com", false, auth); err != nil {
+		t.Fatal(err)
+	}
+
+	task := fake.tasks[0]
+	want := []string{"repo", "add", "internal", "https://charts.example.com", "--force-update",
+		"--username", "ci", "--password-stdin", "--ca-file", "/etc/ssl/ca.crt"}
+	if fmt.Sprint(task.Args) != fmt.Sprint(want) {
+		t.Errorf("want args %v, got %v", want, task.Args)
+	}
+
+	if task.Stdin == nil {
+		t.Fatal("want the password on stdin")
+	}
+	stdin, _ := io.ReadAll(task.Stdin)
+	if string(stdin) != "s3cr3t" {
+		t.Errorf("want password on stdin, got %q", stdin)
+	}
+}
+
+func Test_AddHelmRepoWithAuth_PasswordNeedsUsername(t *testing.T) {
+	k8s.SetExecutor(&recordingExecutor{})
+	defer k8s.SetExecutor(nil)
+
+	err := AddHelmRepoWithAuth("internal", "https://charts.example.com", false, types.HelmRepoAuth{Password: "s3cr3t"})
+	if err == nil {
+		t.Fatal("want an error without a username")
+	}
+}
+
+func Test_Helm3OCIUpgradeWithAuth_UsesDockerConfig(t *testing.T) {
+	dockerConfig := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dockerConfig)
+	configFile := filepath.Join(dockerConfig, "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"auths":{}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	auth := types.HelmRepoAuth{CAFile: "/etc/ssl/ca.crt"}
+	if err := Helm3OCIUpgradeWithAuth("oci://registry.example.com/charts/api", "api", nil, "", nil, false, auth); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(fake.tasks[0].Args, " ")
+	want := "--registry-config " + configFile + " --ca-file /etc/ssl/ca.crt"
+	if !strings.Contains(got, want) {
+		t.Errorf("want %q in args, got %q", want, got)
+	}
+}
+
+func Test_Helm3OCIUpgradeWithAuth_NoDockerConfig(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	if err := Helm3OCIUpgradeWithAuth("oci://registry.example.com/charts/api", "api", nil, "", nil, false, types.HelmRepoAuth{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(fake.tasks[0].Args, " "); strings.Contains(got, "--registry-config") {
+		t.Errorf("want no --registry-config without a Docker config, got %q", got)
+	}
+}
+
+func Test_Helm3OCIUpgradeWithAuth_RejectsPassword(t *testing.T) {
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	auth := types.HelmRepoAuth{Username: "ci", Password: "s3cr3t"}
+	err := Helm3OCIUpgradeWithAuth("oci://registry.example.com/charts/api", "api", nil, "", nil, false, auth)
+	if err == nil || !strings.Contains(err.Error(), "arkade oci login") {
+		t.Errorf("want an error suggesting arkade oci login, got: %v", err)
+	}
+	if len(fake.tasks) > 0 {
+		t.Errorf("want helm not to be run, got: %v", fake.tasks[0].Args)
+	}
+}
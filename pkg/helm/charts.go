@@ -0,0 +1,221 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/alexellis/arkade/pkg/archive"
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/k8s"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+var (
+	chartVersion      string
+	chartVersionChart string
+	chartVersionMu    sync.Mutex
+)
+
+// SetChartVersion overrides the version of the app's chart, as set by
+// "arkade install APP --chart-version". It applies to the first chart
+// which the app fetches or installs, and not to any other chart which
+// it installs along with it. An empty string keeps each app's own
+// version.
+func SetChartVersion(version string) {
+	chartVersionMu.Lock()
+	defer chartVersionMu.Unlock()
+
+	chartVersion = version
+	chartVersionChart = ""
+}
+
+// ChartVersion returns the version given to SetChartVersion.
+func ChartVersion() string {
+	chartVersionMu.Lock()
+	defer chartVersionMu.Unlock()
+
+	return chartVersion
+}
+
+// resolveVersion returns the version set by SetChartVersion for the
+// app's chart, or version for any other chart.
+func resolveVersion(chart, version string) string {
+	chartVersionMu.Lock()
+	defer chartVersionMu.Unlock()
+
+	if len(chartVersion) == 0 {
+		return version
+	}
+	if len(chartVersionChart) == 0 {
+		chartVersionChart = chart
+	}
+	if chartVersionChart != chart {
+		return version
+	}
+	return chartVersion
+}
+
+var (
+	chartsDir     string
+	chartsDirErr  error
+	chartsDirOnce sync.Once
+)
+
+// ChartsDir is where charts are unpacked for this install. It is unique
+// to each arkade process, so that concurrent installs do not remove or
+// overwrite each other's charts.
+func ChartsDir() (string, error) {
+	chartsDirOnce.Do(func() {
+		chartsDir, chartsDirErr = os.MkdirTemp("", "arkade-charts-")
+	})
+	return chartsDir, chartsDirErr
+}
+
+// ChartDir returns the directory of an unpacked chart, for a chart
+// such as "openfaas/openfaas".
+func ChartDir(chart string) (string, error) {
+	dir, err := ChartsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, chartName(chart)), nil
+}
+
+// CleanCharts removes the charts unpacked by this install.
+func CleanCharts() error {
+	if len(chartsDir) == 0 {
+		return nil
+	}
+	return os.RemoveAll(chartsDir)
+}
+
+// chartCacheDir holds the packaged charts for a repository. Charts are
+// only cached for a fixed version, since "latest" changes over time.
+func chartCacheDir(chart string) string {
+	repo := "oci"
+	if index := strings.Index(chart, "/"); index > -1 && !IsOCI(chart) {
+		repo = chart[:index]
+	}
+	return filepath.Join(config.GetPaths().Cache, "charts", repo)
+}
+
+func chartName(chart string) string {
+	if index := strings.LastIndex(chart, "/"); index > -1 {
+		return chart[index+1:]
+	}
+	return chart
+}
+
+// FetchChart unpacks a chart into ChartDir. When a version is given,
+// the packaged chart is kept in arkade's cache so that it is only
+// downloaded once, and later installs work offline.
+func FetchChart(chart, version string) error {
+	version = resolveVersion(chart, version)
+
+	dir, err := ChartDir(chart)
+	if err != nil {
+		return err
+	}
+
+	// Remove any earlier copy, i.e. from another app in a stack
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	if !exactVersion(version) {
+		return pullChart(chart, version, filepath.Dir(dir), true)
+	}
+
+	cacheDir := chartCacheDir(chart)
+	archiveFile := filepath.Join(cacheDir, fmt.Sprintf("%s-%s.tgz", chartName(chart), version))
+
+	if _, err := os.Stat(archiveFile); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(cacheDir, 0700); err != nil {
+			return err
+		}
+		if err := cacheChart(chart, version, cacheDir, archiveFile); err != nil {
+			return err
+		}
+	}
+
+	return unpackChart(archiveFile, filepath.Dir(dir))
+}
+
+func pullChart(chart, version, dest string, untar bool) error {
+	args := []string{"fetch", chart}
+	if untar {
+		args = append(args, "--untar=true", "--untardir", dest)
+	} else {
+		args = append(args, "--destination", dest)
+	}
+	if len(version) > 0 {
+		args = append(args, "--version", version)
+	}
+
+	task := execute.ExecTask{
+		Command:     "helm",
+		Args:        args,
+		Env:         os.Environ(),
+		StreamStdio: true,
+	}
+
+	res, err := k8s.Run(context.Background(), task)
+	return checkResult("fetch", chart, res, err)
+}
+
+// exactVersion is true for a fixed version such as "1.2.3", rather
+// than none or a range such as "^1.2".
+func exactVersion(version string) bool {
+	return len(version) > 0 && !strings.ContainsAny(version, "^~*<>=|, xX")
+}
+
+// cacheChart downloads a chart to archiveFile. helm names the archive
+// after the version in the chart, which may differ from the version
+// requested, i.e. "v1.0.0" or a range, so it is downloaded on its own
+// then renamed.
+func cacheChart(chart, version, cacheDir, archiveFile string) error {
+	tmp, err := os.MkdirTemp(cacheDir, "fetch-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := pullChart(chart, version, tmp, false); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(tmp, "*.tgz"))
+	if err != nil || len(files) != 1 {
+		if k8s.IsDryRun() {
+			return nil
+		}
+		return fmt.Errorf("unable to find the chart downloaded for %s %s", chart, version)
+	}
+
+	return os.Rename(files[0], archiveFile)
+}
+
+func unpackChart(archiveFile, dest string) error {
+	f, err := os.Open(archiveFile)
+	if err != nil {
+		// A dry-run may not have downloaded the chart
+		if errors.Is(err, os.ErrNotExist) && k8s.IsDryRun() {
+			return nil
+		}
+		return fmt.Errorf("unable to open cached chart: %w", err)
+	}
+	defer f.Close()
+
+	if err := archive.UntarNested(f, dest, true, true, false, false); err != nil {
+		return fmt.Errorf("unable to unpack %s: %w", archiveFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	CleanCharts()
+	os.Exit(code)
+}
+
+type recordingExecutor struct {
+	tasks []execute.ExecTask
+	res   execute.ExecResult
+}
+
+func (r *recordingExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	r.tasks = append(r.tasks, task)
+	return r.res, nil
+}
+
+func writeChartArchive(t *testing.T, file, name string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, entry := range []string{name + "/Chart.yaml", name + "/values.yaml"} {
+		body := []byte(fmt.Sprintf("# %s\n", entry))
+		if err := tw.WriteHeader(&tar.Header{Name: entry, Mode: 0600, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func Test_FetchChart_UsesCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("ARKADE_HOME", home)
+
+	writeChartArchive(t, filepath.Join(home, "cache", "charts", "openfaas", "openfaas-14.2.0.tgz"), "openfaas")
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	if err := FetchChart("openfaas/openfaas", "14.2.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.tasks) != 0 {
+		t.Errorf("want no helm commands for a cached chart, got: %v", fake.tasks)
+	}
+
+	dir, err := ChartDir("openfaas/openfaas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "values.yaml")); err != nil {
+		t.Errorf("want chart unpacked into %s: %s", dir, err)
+	}
+}
+
+func Test_FetchChart_WithoutVersionIsNotCached(t *testing.T) {
+	t.Setenv("ARKADE_HOME", t.TempDir())
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	if err := FetchChart("openfaas/openfaas", "^14.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.tasks) != 1 {
+		t.Fatalf("want one helm fetch, got %d", len(fake.tasks))
+	}
+
+	dir, _ := ChartsDir()
+	want := []string{"fetch", "openfaas/openfaas", "--untar=true", "--untardir", dir, "--version", "^14.0.0"}
+	if fmt.Sprint(fake.tasks[0].Args) != fmt.Sprint(want) {
+		t.Errorf("want args %v, got %v", want, fake.tasks[0].Args)
+	}
+}
+
+func Test_ChartsDir_IsUniqueToInstall(t *testing.T) {
+	dir, err := ChartsDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Dir(dir) != filepath.Clean(os.TempDir()) || filepath.Base(dir) == "charts" {
+		t.Errorf("want a unique directory under %s, got %s", os.TempDir(), dir)
+	}
+}
+
+func Test_Error(t *testing.T) {
+	fakeConfiguration(t)
+
+	err := Uninstall("nginx", "default", false)
+
+	var helmErr *Error
+	if !errors.As(err, &helmErr) {
+		t.Fatalf("want *Error, got %T: %v", err, err)
+	}
+	if helmErr.Op != "uninstall" || helmErr.Name != "nginx" {
+		t.Errorf("unexpected error fields: %+v", helmErr)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, got %v", err)
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("want not ErrUnauthorized")
+	}
+}
+
+func Test_SetChartVersion(t *testing.T) {
+	t.Setenv("ARKADE_HOME", t.TempDir())
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	SetChartVersion("14.2.0")
+	defer SetChartVersion("")
+
+	if err := Helm3OCIUpgrade("oci://ghcr.io/openfaas/charts/openfaas", "openfaas", nil, "", nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"upgrade", "--install", "openfaas", "oci://ghcr.io/openfaas/charts/openfaas", "--namespace", "openfaas", "--version", "14.2.0"}
+	if fmt.Sprint(fake.tasks[0].Args) != fmt.Sprint(want) {
+		t.Errorf("want args %v, got %v", want, fake.tasks[0].Args)
+	}
+}
+
+func Test_SetChartVersion_OnlyAppChart(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("ARKADE_HOME", home)
+
+	writeChartArchive(t, filepath.Join(home, "cache", "charts", "openfaas", "openfaas-14.2.0.tgz"), "openfaas")
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	SetChartVersion("14.2.0")
+	defer SetChartVersion("")
+
+	if err := FetchChart("openfaas/openfaas", "^14.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := FetchChart("jetstack/cert-manager", "^1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.tasks) != 1 {
+		t.Fatalf("want one helm fetch for cert-manager, got %d", len(fake.tasks))
+	}
+	want := "--version ^1.0.0"
+	if got := strings.Join(fake.tasks[0].Args, " "); !strings.HasSuffix(got, want) {
+		t.Errorf("want cert-manager to keep its version %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetValues sets scalar values in a YAML file, such as values.yaml or
+// Chart.yaml, by their path, i.e. "image.tag". Only the bytes of each
+// value are changed, so comments, anchors, ordering and each value's
+// quoting are kept. A value held by an anchor is set once, and so
+// changes for each of its aliases too.
+func SetValues(data []byte, values map[string]string) ([]byte, error) {
+	if len(values) == 0 {
+		return data, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse values: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("no values found")
+	}
+	root := resolve(doc.Content[0])
+
+	type edit struct {
+		start, end int
+		value      string
+	}
+	edits := []edit{}
+	seen := map[int]bool{}
+
+	for path, value := range values {
+		node := lookup(root, strings.Split(path, "."))
+		if node == nil || node.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("no value found at %s", path)
+		}
+		if strings.Contains(node.Value, "\n") || node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return nil, fmt.Errorf("value at %s spans several lines", path)
+		}
+
+		pos, err := offset(data, node.Line, node.Column)
+		if err != nil {
+			return nil, fmt.Errorf("value at %s: %w", path, err)
+		}
+		start, end, err := scalarSpan(data, pos, node)
+		if err != nil {
+			return nil, fmt.Errorf("value at %s: %w", path, err)
+		}
+
+		// An anchor's value is shared by its aliases
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+
+		edits = append(edits, edit{start: start, end: end, value: quote(value, node)})
+	}
+
+	// Edit from the end, so that earlier offsets stay the same
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+
+	out := bytes.Clone(data)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.value), out[e.end:]...)...)
+	}
+
+	return out, nil
+}
+
+// lookup finds the node at a path, where a key may itself contain dots.
+func lookup(node *yaml.Node, parts []string) *yaml.Node {
+	if len(parts) == 0 {
+		return node
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 1; i <= len(parts); i++ {
+		if value := mapValue(node, strings.Join(parts[:i], ".")); value != nil {
+			if found := lookup(value, parts[i:]); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// offset converts a node's line and column, which counts characters
+// from 1, into a byte offset.
+func offset(data []byte, line, column int) (int, error) {
+	pos := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(data[pos:], '\n')
+		if i < 0 {
+			return 0, fmt.Errorf("line %d is out of range", line)
+		}
+		pos += i + 1
+	}
+
+	for c := 1; c < column; c++ {
+		if pos >= len(data) || data[pos] == '\n' {
+			return 0, fmt.Errorf("column %d is out of range on line %d", column, line)
+		}
+		_, size := utf8.DecodeRune(data[pos:])
+		pos += size
+	}
+
+	return pos, nil
+}
+
+// scalarSpan returns the offsets of a single-line scalar whose node
+// starts at pos, after any anchor or tag, i.e. "&tag !!str 1.0.0".
+func scalarSpan(data []byte, pos int, node *yaml.Node) (int, int, error) {
+	start := pos
+	for start < len(data) && (data[start] == '&' || data[start] == '!') {
+		for start < len(data) && data[start] != ' ' && data[start] != '\t' && data[start] != '\n' {
+			start++
+		}
+		for start < len(data) && (data[start] == ' ' || data[start] == '\t') {
+			start++
+		}
+	}
+
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(data) && data[i] != '\n'; i++ {
+			if data[i] == '\\' {
+				i++
+				continue
+			}
+			if data[i] == '"' {
+				return start, i + 1, nil
+			}
+		}
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(data) && data[i] != '\n'; i++ {
+			if data[i] == '\'' {
+				if i+1 < len(data) && data[i+1] == '\'' {
+					i++
+					continue
+				}
+				return start, i + 1, nil
+			}
+		}
+	default:
+		if bytes.HasPrefix(data[start:], []byte(node.Value)) {
+			return start, start + len(node.Value), nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("unable to find the end of %q", node.Value)
+}
+
+// quote formats value in the style of the node it replaces. A plain
+// value is quoted when it would not be read back as itself, or when the
+// node was a string and the value would be read as another type, i.e.
+// "1.10", "1e3" or "true" in place of "v1.9".
+func quote(value string, node *yaml.Node) string {
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		return strconv.Quote(value)
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || len(parsed.Content) != 1 || strings.ContainsAny(value, "#\n") {
+		return strconv.Quote(value)
+	}
+	scalar := parsed.Content[0]
+	if scalar.Kind != yaml.ScalarNode || scalar.Style != 0 || scalar.Value != value {
+		return strconv.Quote(value)
+	}
+
+	// An explicit tag, i.e. "!!str 1.0.0", is kept, so the type can't change
+	if node.Tag == "!!str" && node.Style&yaml.TaggedStyle == 0 && scalar.Tag != "!!str" {
+		return strconv.Quote(value)
+	}
+	return value
+}
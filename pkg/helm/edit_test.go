@@ -0,0 +1,201 @@
+package helm
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// Test_SetValues_Golden sets values in each file in testdata/set-values
+// and compares the result with its .golden.yaml file, run with -update to
+// rewrite them.
+func Test_SetValues_Golden(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+	}{
+		{
+			name: "comments",
+			values: map[string]string{
+				"gateway.image": "ghcr.io/openfaas/gateway:0.28.1",
+			},
+		},
+		{
+			name: "anchors",
+			values: map[string]string{
+				"nats.image.tag":   "2.10.22",
+				"natsDefaults.tag": "2.10.22",
+				"redis.tag":        "7.2.10",
+				"exporter.tag":     "1.62",
+			},
+		},
+		{
+			name: "quoting",
+			values: map[string]string{
+				"image.tag":         "7.2.10",
+				"metrics.image.tag": "1.62.0",
+				"sidecar.tag":       "1.11",
+				"kubectl.tag":       "1.29: latest",
+			},
+		},
+		{
+			name: "types",
+			values: map[string]string{
+				"app.tag":      "1.10",
+				"exporter.tag": "1e3",
+				"feature.mode": "true",
+				"explicit.tag": "1.1",
+				"replicas":     "2",
+			},
+		},
+		{
+			name: "chart",
+			values: map[string]string{
+				"version":    "14.2.1",
+				"appVersion": "0.28.1",
+			},
+		},
+		{
+			name: "crlf",
+			values: map[string]string{
+				"café":      "brûlée",
+				"image.tag": "1.1.0",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "set-values", tc.name+".yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := SetValues(input, tc.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "set-values", tc.name+".golden.yaml")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("want:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func Test_SetValues(t *testing.T) {
+	values := `# Redis
+image:
+  registry: docker.io # the registry
+  repository: bitnami/redis
+  tag: 7.2.4 # pinned
+metrics:
+  image: {repository: bitnami/redis-exporter, tag: "1.58.0"}
+sidecar:
+  image:
+    repository: "ghcr.io/openfaas/watchdog"
+    tag: '0.9.15'
+defaults: &defaults
+  tag: 2.10.9
+nats:
+  image: *defaults
+`
+
+	got, err := SetValues([]byte(values), map[string]string{
+		"image.tag":         "7.2.10",
+		"metrics.image.tag": "1.62.0",
+		"sidecar.image.tag": "0.10.1",
+		"nats.image.tag":    "2.10.22",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# Redis
+image:
+  registry: docker.io # the registry
+  repository: bitnami/redis
+  tag: 7.2.10 # pinned
+metrics:
+  image: {repository: bitnami/redis-exporter, tag: "1.62.0"}
+sidecar:
+  image:
+    repository: "ghcr.io/openfaas/watchdog"
+    tag: '0.10.1'
+defaults: &defaults
+  tag: 2.10.22
+nats:
+  image: *defaults
+`
+	if string(got) != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func Test_SetValues_MultiLine(t *testing.T) {
+	values := "notes: |\n  line one\n  line two\n"
+	if _, err := SetValues([]byte(values), map[string]string{"notes": "one line"}); err == nil {
+		t.Errorf("want an error for a value which spans several lines")
+	}
+}
+
+func Test_SetValues_Missing(t *testing.T) {
+	if _, err := SetValues([]byte("image:\n  repository: nats\n"), map[string]string{"image.tag": "2.10.9"}); err == nil {
+		t.Errorf("want an error for a missing value")
+	}
+}
+
+func Test_SetValues_DottedKey(t *testing.T) {
+	values := "app.kubernetes.io:\n  tag: 1.0.0\n"
+	got, err := SetValues([]byte(values), map[string]string{"app.kubernetes.io.tag": "1.1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "app.kubernetes.io:\n  tag: 1.1.0\n"; string(got) != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func Test_quote(t *testing.T) {
+	str := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+	float := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float"}
+	tagged := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.TaggedStyle}
+
+	tests := []struct {
+		value string
+		node  *yaml.Node
+		want  string
+	}{
+		{value: "7.2.4", node: str, want: "7.2.4"},
+		{value: "1.10", node: str, want: `"1.10"`},
+		{value: "1e3", node: str, want: `"1e3"`},
+		{value: "true", node: str, want: `"true"`},
+		{value: "~", node: str, want: `"~"`},
+		{value: "", node: str, want: `""`},
+		{value: "1.10", node: float, want: "1.10"},
+		{value: "1.10", node: tagged, want: "1.10"},
+		{value: "v1: latest", node: float, want: `"v1: latest"`},
+		{value: "- latest", node: str, want: `"- latest"`},
+	}
+	for _, tc := range tests {
+		if got := quote(tc.value, tc.node); got != tc.want {
+			t.Errorf("%s (%s): want %s, got %s", tc.value, tc.node.Tag, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+var (
+	// ErrNotFound is returned when a release, chart or repository
+	// does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnauthorized is returned when a registry rejects the
+	// credentials, or requires them.
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is returned when a helm command fails, so that callers can
+// check the operation and cause with errors.Is. Err is the error from
+// the Helm SDK.
+type Error struct {
+	// Op is the helm command, i.e. "upgrade" or "repo add".
+	Op string
+
+	// Name is the release, chart or repository.
+	Name string
+
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("helm %s %s failed: %s", e.Op, e.Name, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrNotFound and ErrUnauthorized by the errors of the Helm
+// SDK and of the registry which it talks to.
+func (e *Error) Is(target error) bool {
+	var res *errcode.ErrorResponse
+	status := 0
+	if errors.As(e.Err, &res) {
+		status = res.StatusCode
+	}
+
+	switch target {
+	case ErrNotFound:
+		return errors.Is(e.Err, driver.ErrReleaseNotFound) || errors.Is(e.Err, repo.ErrNoChartName) ||
+			errors.Is(e.Err, repo.ErrNoChartVersion) || errors.Is(e.Err, os.ErrNotExist) ||
+			status == http.StatusNotFound
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized || status == http.StatusForbidden
+	}
+	return false
+}
+
+// checkResult returns an *Error when a helm command did not succeed.
+func checkResult(op, name string, res execute.ExecResult, err error) error {
+	if err != nil {
+		return &Error{Op: op, Name: name, Err: err}
+	}
+	if res.ExitCode != 0 {
+		return &Error{Op: op, Name: name, Err: fmt.Errorf("exit code %d: %s", res.ExitCode, strings.TrimSpace(res.Stderr))}
+	}
+	return nil
+}
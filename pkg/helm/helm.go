@@ -5,177 +5,32 @@ package helm
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"os"
-	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
-	"github.com/alexellis/arkade/pkg/env"
-	"github.com/alexellis/arkade/pkg/get"
+	"github.com/alexellis/arkade/pkg/k8s"
+	"github.com/alexellis/arkade/pkg/types"
 	execute "github.com/alexellis/go-execute/v2"
 )
 
-func TryDownloadHelm(userPath, clientArch, clientOS string) (string, error) {
-	helmVal := "helm"
-	subdir := ""
-
-	helmBinaryPath := path.Join(path.Join(userPath, "bin"), helmVal)
-	if _, statErr := os.Stat(helmBinaryPath); statErr != nil {
-		if err := DownloadHelm(userPath, clientArch, clientOS, subdir); err != nil {
-			return "", err
-		}
-
-	}
-	return helmBinaryPath, nil
-}
-
-func DownloadHelm(userPath, clientArch, clientOS, subdir string) error {
-	tools := get.MakeTools()
-	var tool *get.Tool
-	for _, t := range tools {
-		if t.Name == "helm" {
-			tool = &t
-			break
-		}
-	}
-	if tool == nil {
-		return fmt.Errorf("unable to find tool definition")
-	}
-
-	if _, err := os.Stat(env.LocalBinary(tool.Name, "")); errors.Is(err, os.ErrNotExist) {
-
-		var (
-			progress bool
-			quiet    bool
-		)
-
-		verify := false
-		defaultMovePath := ""
-		outPath, finalName, err := get.Download(tool,
-			clientArch,
-			clientOS,
-			tool.Version,
-			defaultMovePath,
-			progress,
-			quiet,
-			verify)
-		if err != nil {
-			return err
-		}
-
-		fmt.Println("Downloaded to: ", outPath, finalName)
-	} else {
-		fmt.Printf("%s already exists, skipping download.\n", tool.Name)
-	}
-
-	return nil
-}
-
 func UpdateHelmRepos(helm3 bool) error {
-	subdir := ""
-
 	task := execute.ExecTask{
-		Command:     env.LocalBinary("helm", subdir),
+		Command:     "helm",
 		Args:        []string{"repo", "update"},
 		Env:         os.Environ(),
 		StreamStdio: true,
 	}
 
-	res, err := task.Execute(context.Background())
-
-	if err != nil {
-		return err
-	}
-
-	if res.ExitCode != 0 {
-		return fmt.Errorf("exit code %d", res.ExitCode)
-	}
-	return nil
+	res, err := k8s.Run(context.Background(), task)
+	return checkResult("repo update", "", res, err)
 }
 
 func AddHelmRepo(name, url string, update bool) error {
-	subdir := ""
-
-	if index := strings.Index(name, "/"); index > -1 {
-		name = name[:index]
-	}
-
-	task := execute.ExecTask{
-		Command:     env.LocalBinary("helm", subdir),
-		Args:        []string{"repo", "add", name, url, "--force-update"},
-		Env:         os.Environ(),
-		StreamStdio: true,
-	}
-	res, err := task.Execute(context.Background())
-	if err != nil {
-		return err
-	}
-
-	println(res.Stderr)
-
-	if res.ExitCode != 0 {
-		return fmt.Errorf("exit code %d", res.ExitCode)
-	}
-
-	if update {
-		task := execute.ExecTask{
-			Command:     env.LocalBinary("helm", subdir),
-			Args:        []string{"repo", "update"},
-			Env:         os.Environ(),
-			StreamStdio: true,
-		}
-		res, err := task.Execute(context.Background())
-
-		if err != nil {
-			return err
-		}
-
-		println(res.Stderr)
-
-		if res.ExitCode != 0 {
-			return fmt.Errorf("exit code %d", res.ExitCode)
-		}
-	}
-
-	return nil
-}
-
-func FetchChart(chart, version string) error {
-	chartsPath := path.Join(os.TempDir(), "charts")
-
-	subdir := ""
-
-	// First remove any existing folder
-	os.RemoveAll(chartsPath)
-
-	if err := os.MkdirAll(chartsPath, 0700); err != nil {
-		return err
-	}
-
-	task := execute.ExecTask{
-		Command:     env.LocalBinary("helm", subdir),
-		Args:        []string{"fetch", chart, "--untar=true", "--untardir", chartsPath},
-		Env:         os.Environ(),
-		StreamStdio: true,
-	}
-
-	if len(version) > 0 {
-		task.Args = append(task.Args, "--version", version)
-	}
-
-	res, err := task.Execute(context.Background())
-
-	if err != nil {
-		return err
-	}
-
-	if res.ExitCode != 0 {
-		return fmt.Errorf("exit code %d", res.ExitCode)
-	}
-	return nil
+	return AddHelmRepoWithAuth(name, url, update, types.HelmRepoAuth{})
 }
 
 func IsOCI(chart string) bool {
@@ -195,6 +50,22 @@ func isURL(str string) bool {
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
 
+// setArgs returns --set flags for overrides, sorted by key so that the
+// command is the same on every run.
+func setArgs(overrides map[string]string) []string {
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	for _, k := range keys {
+		args = append(args, "--set", fmt.Sprintf("%s=%s", k, overrides[k]))
+	}
+	return args
+}
+
 func Helm3Upgrade(chart, namespace string, valuesFile []string, version string, overrides map[string]string, wait bool) error {
 
 	chartName := chart
@@ -202,7 +73,10 @@ func Helm3Upgrade(chart, namespace string, valuesFile []string, version string,
 		chartName = chartName[index+1:]
 	}
 
-	basePath := path.Join(os.TempDir(), "charts", chartName)
+	basePath, err := ChartDir(chart)
+	if err != nil {
+		return err
+	}
 
 	// Process values files to download any URLs
 	processedValuesFiles, err := processValuesFiles(valuesFile)
@@ -210,6 +84,8 @@ func Helm3Upgrade(chart, namespace string, valuesFile []string, version string,
 		return err
 	}
 
+	version = resolveVersion(chart, version)
+
 	args := []string{"upgrade", "--install", chartName, chart, "--namespace", namespace}
 	if len(version) > 0 {
 		args = append(args, "--version", version)
@@ -225,44 +101,46 @@ func Helm3Upgrade(chart, namespace string, valuesFile []string, version string,
 			// Helm supports URLs directly, pass through unchanged
 			args = append(args, valueFile)
 		} else if !strings.HasPrefix(valueFile, "/") {
-			args = append(args, path.Join(basePath, valueFile))
+			args = append(args, filepath.Join(basePath, valueFile))
 		} else {
 			args = append(args, valueFile)
 		}
 	}
 
-	for k, v := range overrides {
-		args = append(args, "--set")
-		args = append(args, fmt.Sprintf("%s=%s", k, v))
+	args = append(args, setArgs(overrides)...)
+
+	postArgs, err := postRenderArgs()
+	if err != nil {
+		return err
 	}
+	args = append(args, postArgs...)
 
 	task := execute.ExecTask{
-		Command:     env.LocalBinary("helm", ""),
+		Command:     "helm",
 		Args:        args,
 		Env:         os.Environ(),
 		Cwd:         basePath,
 		StreamStdio: true,
 	}
 
-	fmt.Printf("Command: %s %s\n", task.Command, task.Args)
-	res, err := task.Execute(context.Background())
-
-	if err != nil {
-		return err
-	}
-
-	if res.ExitCode != 0 {
-		return fmt.Errorf("exit code %d, stderr: %s", res.ExitCode, res.Stderr)
+	if !k8s.IsDryRun() {
+		fmt.Printf("Command: %s %s\n", task.Command, task.Args)
 	}
-
-	if len(res.Stderr) > 0 {
-		log.Printf("stderr: %s\n", res.Stderr)
+	res, err := k8s.Run(context.Background(), task)
+	if err := checkResult("upgrade", chartName, res, err); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func Helm3OCIUpgrade(chart, namespace string, valuesFiles []string, version string, overrides map[string]string, wait bool) error {
+	return Helm3OCIUpgradeWithAuth(chart, namespace, valuesFiles, version, overrides, wait, types.HelmRepoAuth{})
+}
+
+// Helm3OCIUpgradeWithAuth installs or upgrades an OCI chart, using the
+// TLS files in auth to reach the registry.
+func Helm3OCIUpgradeWithAuth(chart, namespace string, valuesFiles []string, version string, overrides map[string]string, wait bool, auth types.HelmRepoAuth) error {
 
 	if !IsOCI(chart) {
 		return fmt.Errorf("chart %s is not an OCI chart URL", chart)
@@ -280,6 +158,8 @@ func Helm3OCIUpgrade(chart, namespace string, valuesFiles []string, version stri
 		return err
 	}
 
+	version = resolveVersion(chart, version)
+
 	args := []string{"upgrade", "--install", name, chart, "--namespace", namespace}
 	if len(version) > 0 {
 		args = append(args, "--version", version)
@@ -294,32 +174,52 @@ func Helm3OCIUpgrade(chart, namespace string, valuesFiles []string, version stri
 		args = append(args, valueFile)
 	}
 
-	for k, v := range overrides {
-		args = append(args, "--set")
-		args = append(args, fmt.Sprintf("%s=%s", k, v))
+	authArgs, err := ociAuthArgs(auth)
+	if err != nil {
+		return err
+	}
+	args = append(args, authArgs...)
+
+	args = append(args, setArgs(overrides)...)
+
+	postArgs, err := postRenderArgs()
+	if err != nil {
+		return err
 	}
+	args = append(args, postArgs...)
 
 	task := execute.ExecTask{
-		Command:     env.LocalBinary("helm", ""),
+		Command:     "helm",
 		Args:        args,
 		Env:         os.Environ(),
 		StreamStdio: true,
 	}
 
-	fmt.Printf("Command: %s %s\n", task.Command, task.Args)
-	res, err := task.Execute(context.TODO())
-
-	if err != nil {
+	if !k8s.IsDryRun() {
+		fmt.Printf("Command: %s %s\n", task.Command, task.Args)
+	}
+	res, err := k8s.Run(context.TODO(), task)
+	if err := checkResult("upgrade", name, res, err); err != nil {
 		return err
 	}
 
-	if res.ExitCode != 0 {
-		return fmt.Errorf("exit code %d, stderr: %s", res.ExitCode, res.Stderr)
+	return nil
+}
+
+// Uninstall removes a helm release.
+func Uninstall(name, namespace string, wait bool) error {
+	args := []string{"uninstall", name, "--namespace", namespace}
+	if wait {
+		args = append(args, "--wait")
 	}
 
-	if len(res.Stderr) > 0 {
-		log.Printf("stderr: %s\n", res.Stderr)
+	task := execute.ExecTask{
+		Command:     "helm",
+		Args:        args,
+		Env:         os.Environ(),
+		StreamStdio: true,
 	}
 
-	return nil
+	res, err := k8s.Run(context.Background(), task)
+	return checkResult("uninstall", name, res, err)
 }
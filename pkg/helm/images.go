@@ -0,0 +1,180 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alexellis/arkade/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Image is a container image found in a values.yaml file, either as a
+// string:
+//
+//	image: ghcr.io/openfaas/gateway:0.27.0
+//
+// or as a map, as used by Bitnami and most upstream charts, where the
+// registry defaults to global.imageRegistry:
+//
+//	image:
+//	  registry: docker.io
+//	  repository: bitnami/redis
+//	  tag: 7.2.4
+type Image struct {
+	// Path of the image in the values, i.e. "gateway.image".
+	Path string
+
+	// Ref is the full reference, i.e. "docker.io/bitnami/redis:7.2.4".
+	Ref string
+
+	// Tag is the image's tag, i.e. "7.2.4".
+	Tag string
+
+	// TagPath is the path of the tag within a map, i.e. "image.tag",
+	// and is empty for an image given as a string.
+	TagPath string
+
+	// Digest pins a map's image, so its tag can't be upgraded.
+	Digest string
+}
+
+// Structured is true for an image given as a map with its own tag.
+func (i Image) Structured() bool {
+	return len(i.TagPath) > 0
+}
+
+// FindImagesUptoDepth returns the images in a values.yaml file up to
+// depth levels down, sorted by path. Paths listed in cfg.Ignore are
+// skipped.
+func FindImagesUptoDepth(data []byte, depth int, cfg *config.ArkadeConfig) ([]Image, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse values: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := resolve(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	f := &imageFinder{}
+	if global := mapValue(root, "global"); global != nil {
+		if registry := mapValue(global, "imageRegistry"); registry != nil && registry.Kind == yaml.ScalarNode {
+			f.globalRegistry = registry.Value
+		}
+	}
+	if cfg != nil {
+		f.ignore = cfg.Ignore
+	}
+
+	f.find(root, depth, "")
+	slices.SortFunc(f.images, func(a, b Image) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return f.images, nil
+}
+
+type imageFinder struct {
+	globalRegistry string
+	ignore         []string
+	images         []Image
+}
+
+func (f *imageFinder) find(node *yaml.Node, depth int, path string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		value := resolve(node.Content[i+1])
+
+		fullPath := key
+		if len(path) > 0 {
+			fullPath = path + "." + key
+		}
+
+		if strings.Contains(strings.ToLower(key), "image") && !slices.Contains(f.ignore, fullPath) {
+			if image, ok := f.image(key, value, fullPath); ok {
+				f.images = append(f.images, image)
+				continue
+			}
+		}
+
+		if value.Kind == yaml.MappingNode && depth > 0 {
+			f.find(value, depth-1, fullPath)
+		}
+	}
+}
+
+// image reads a string image under an "image" key, or a map with a
+// repository and a tag under any key naming an image.
+func (f *imageFinder) image(key string, node *yaml.Node, path string) (Image, bool) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if key != "image" || node.Tag != "!!str" || len(node.Value) == 0 {
+			return Image{}, false
+		}
+		image := Image{Path: path, Ref: node.Value}
+		// A registry may have a port, i.e. "registry:5000/gateway:0.27.0"
+		if i := strings.LastIndex(node.Value, ":"); i > strings.LastIndex(node.Value, "/") {
+			image.Tag = node.Value[i+1:]
+		}
+		return image, true
+
+	case yaml.MappingNode:
+		repository := mapValue(node, "repository")
+		tag := mapValue(node, "tag")
+		if repository == nil || tag == nil || repository.Kind != yaml.ScalarNode || tag.Kind != yaml.ScalarNode {
+			return Image{}, false
+		}
+
+		// An empty tag is usually the chart's appVersion
+		if len(repository.Value) == 0 || len(tag.Value) == 0 {
+			return Image{}, false
+		}
+
+		name := repository.Value
+		registry := f.globalRegistry
+		if r := mapValue(node, "registry"); r != nil && r.Kind == yaml.ScalarNode && len(r.Value) > 0 {
+			registry = r.Value
+		}
+		if len(registry) > 0 {
+			name = strings.TrimSuffix(registry, "/") + "/" + name
+		}
+
+		image := Image{
+			Path:    path,
+			Ref:     name + ":" + tag.Value,
+			Tag:     tag.Value,
+			TagPath: path + ".tag",
+		}
+		if digest := mapValue(node, "digest"); digest != nil && digest.Kind == yaml.ScalarNode {
+			image.Digest = digest.Value
+		}
+		return image, true
+	}
+
+	return Image{}, false
+}
+
+// mapValue returns the value for key in a mapping node, or nil.
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolve(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// resolve follows an alias to its anchor.
+func resolve(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias
+	}
+	return node
+}
@@ -0,0 +1,86 @@
+package helm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/config"
+)
+
+func Test_FindImagesUptoDepth(t *testing.T) {
+	values := `global:
+  imageRegistry: registry.example.com
+image:
+  registry: docker.io
+  repository: bitnami/redis
+  tag: 7.2.4
+metrics:
+  image:
+    repository: bitnami/redis-exporter
+    tag: "1.58.0"
+sentinel:
+  image:
+    repository: bitnami/redis-sentinel
+    tag: 7.2.4
+    digest: sha256:abc
+gateway:
+  image: ghcr.io/openfaas/gateway:0.27.0
+  sidecarImage:
+    repository: ghcr.io/openfaas/watchdog
+    tag: 0.9.15
+local:
+  image: localhost:5000/queue-worker:0.14.0
+defaults: &defaults
+  repository: nats
+  tag: 2.10.9
+nats:
+  image: *defaults
+chart:
+  image:
+    repository: bitnami/kubectl
+    tag: ""
+`
+
+	got, err := FindImagesUptoDepth([]byte(values), 3, &config.ArkadeConfig{Ignore: []string{"local.image"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Image{
+		{Path: "gateway.image", Ref: "ghcr.io/openfaas/gateway:0.27.0", Tag: "0.27.0"},
+		{Path: "gateway.sidecarImage", Ref: "registry.example.com/ghcr.io/openfaas/watchdog:0.9.15", Tag: "0.9.15", TagPath: "gateway.sidecarImage.tag"},
+		{Path: "image", Ref: "docker.io/bitnami/redis:7.2.4", Tag: "7.2.4", TagPath: "image.tag"},
+		{Path: "metrics.image", Ref: "registry.example.com/bitnami/redis-exporter:1.58.0", Tag: "1.58.0", TagPath: "metrics.image.tag"},
+		{Path: "nats.image", Ref: "registry.example.com/nats:2.10.9", Tag: "2.10.9", TagPath: "nats.image.tag"},
+		{Path: "sentinel.image", Ref: "registry.example.com/bitnami/redis-sentinel:7.2.4", Tag: "7.2.4", TagPath: "sentinel.image.tag", Digest: "sha256:abc"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want images:\n%v\ngot:\n%v", want, got)
+	}
+}
+
+func Test_FindImagesUptoDepth_RegistryPort(t *testing.T) {
+	got, err := FindImagesUptoDepth([]byte("image: localhost:5000/queue-worker:0.14.0\n"), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Tag != "0.14.0" {
+		t.Errorf("want tag 0.14.0, got %v", got)
+	}
+}
+
+func Test_FindImagesUptoDepth_Depth(t *testing.T) {
+	values := `a:
+  b:
+    image:
+      repository: nats
+      tag: 2.10.9
+`
+	got, err := FindImagesUptoDepth([]byte(values), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("want no images beyond the depth, got %v", got)
+	}
+}
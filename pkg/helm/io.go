@@ -3,10 +3,7 @@ package helm
 import (
 	"fmt"
 	"os"
-	"reflect"
-	"strings"
 
-	"github.com/alexellis/arkade/pkg/config"
 	"gopkg.in/yaml.v3"
 )
 
@@ -42,62 +39,3 @@ func LoadFrom(yamlText string) (ValuesMap, error) {
 
 	return values, nil
 }
-
-// ReplaceValuesInHelmValuesFile takes a values.yaml file and replaces values in it with the values provided in the map
-// and returns the updated values.yaml file as a string
-func ReplaceValuesInHelmValuesFile(values map[string]string, yamlPath string) (string, error) {
-	readFile, err := os.ReadFile(yamlPath)
-	if err != nil {
-		return "", err
-	}
-
-	fileContent := string(readFile)
-	for k, v := range values {
-		fileContent = strings.ReplaceAll(fileContent, k, v)
-	}
-	return fileContent, nil
-}
-
-// FilterImagesUptoDepth takes a ValuesMap and returns a map of images that
-// were found upto max level
-func FilterImagesUptoDepth(values ValuesMap, depth int, path string, cfg *config.ArkadeConfig) map[string]string {
-	images := map[string]string{}
-	for k, v := range values {
-
-		prefix := ""
-		if len(path) > 0 {
-			prefix = path + "."
-		}
-
-		if k == "image" && reflect.TypeOf(v).Kind() == reflect.String {
-			fullPath := prefix + k
-
-			ignoreItem := false
-			if cfg != nil {
-				for _, ignore := range cfg.Ignore {
-					if fullPath == ignore {
-						ignoreItem = true
-						break
-					}
-				}
-			}
-
-			if !ignoreItem {
-				imageUrl := v.(string)
-				images[imageUrl] = imageUrl
-			}
-		}
-
-		if c, ok := v.(ValuesMap); ok && depth > 0 {
-			images = mergeMaps(images, FilterImagesUptoDepth(c, depth-1, prefix+k, cfg))
-		}
-	}
-	return images
-}
-
-func mergeMaps(original, latest map[string]string) map[string]string {
-	for k, v := range latest {
-		original[k] = v
-	}
-	return original
-}
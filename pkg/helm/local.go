@@ -0,0 +1,214 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexellis/arkade/pkg/get"
+	"github.com/alexellis/arkade/pkg/k8s"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// IsLocalChart is true for a chart directory with a Chart.yaml, a
+// packaged chart such as "api-0.1.0.tgz", or a URL to a packaged chart,
+// rather than a chart from a repository.
+func IsLocalChart(chart string) bool {
+	if strings.HasPrefix(chart, "https://") || strings.HasPrefix(chart, "http://") {
+		return true
+	}
+	if strings.HasSuffix(chart, ".tgz") || strings.HasSuffix(chart, ".tar.gz") {
+		return true
+	}
+
+	_, err := os.Stat(filepath.Join(chart, "Chart.yaml"))
+	return err == nil
+}
+
+// LocalChart returns the path of a local chart for helm, and its name
+// from Chart.yaml. A chart from a URL is downloaded into ChartsDir first,
+// so that it is removed by CleanCharts, except for a dry-run, where the
+// URL is given to helm and the name is read from the file name.
+func LocalChart(chart string) (string, string, error) {
+	if strings.HasPrefix(chart, "https://") || strings.HasPrefix(chart, "http://") {
+		if k8s.IsDryRun() {
+			return chart, chartURLName(chart), nil
+		}
+
+		dir, err := ChartsDir()
+		if err != nil {
+			return "", "", err
+		}
+		file, err := get.NewClient(get.Options{CacheDir: dir}).DownloadFile(context.Background(), chart)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to download chart %s: %w", chart, err)
+		}
+		chart = file
+	}
+
+	info, err := os.Stat(chart)
+	if err != nil {
+		return "", "", err
+	}
+
+	var name string
+	if info.IsDir() {
+		name, err = chartDirName(chart)
+	} else {
+		name, err = chartArchiveName(chart)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return chart, name, nil
+}
+
+// Helm3LocalUpgrade installs or upgrades a local chart, see IsLocalChart.
+// The release is named after the chart. Values files are read relative
+// to the working directory, as with helm.
+func Helm3LocalUpgrade(chart, namespace string, valuesFiles []string, overrides map[string]string, wait bool) error {
+	path, name, err := LocalChart(chart)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"upgrade", "--install", name, path, "--namespace", namespace}
+
+	// Fetch the dependencies of an unpackaged chart, a packaged chart
+	// includes them
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		args = append(args, "--dependency-update")
+	}
+
+	if wait {
+		args = append(args, "--wait")
+	}
+
+	for _, valueFile := range valuesFiles {
+		args = append(args, "--values", valueFile)
+	}
+
+	args = append(args, setArgs(overrides)...)
+
+	postArgs, err := postRenderArgs()
+	if err != nil {
+		return err
+	}
+	args = append(args, postArgs...)
+
+	task := execute.ExecTask{
+		Command:     "helm",
+		Args:        args,
+		Env:         os.Environ(),
+		StreamStdio: true,
+	}
+
+	if !k8s.IsDryRun() {
+		fmt.Printf("Command: %s %s\n", task.Command, task.Args)
+	}
+	res, err := k8s.Run(context.Background(), task)
+	if err := checkResult("upgrade", name, res, err); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// chartURLName returns the name of a packaged chart from its URL, i.e.
+// "api" for ".../api-0.1.0.tgz".
+func chartURLName(chartURL string) string {
+	name := path.Base(chartURL)
+	if i := strings.IndexAny(name, "?#"); i > -1 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".tgz"), ".tar.gz")
+
+	for i := 0; i < len(name); i++ {
+		if name[i] != '-' {
+			continue
+		}
+		if _, err := semver.StrictNewVersion(name[i+1:]); err == nil {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+type chartMetadata struct {
+	Name string `yaml:"name"`
+}
+
+func parseChartName(data []byte, source string) (string, error) {
+	var meta chartMetadata
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return "", fmt.Errorf("unable to parse %s: %w", source, err)
+	}
+	if len(meta.Name) == 0 {
+		return "", fmt.Errorf("no name in %s", source)
+	}
+	return meta.Name, nil
+}
+
+func chartDirName(dir string) (string, error) {
+	file := filepath.Join(dir, "Chart.yaml")
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return parseChartName(data, file)
+}
+
+// chartArchiveName reads the name from the top-level Chart.yaml of a
+// packaged chart, as the archive may also hold its dependencies.
+func chartArchiveName(archive string) (string, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("unable to read chart %s: %w", archive, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("unable to read chart %s: %w", archive, err)
+		}
+
+		parts := strings.Split(strings.TrimPrefix(header.Name, "./"), "/")
+		if len(parts) != 2 || parts[1] != "Chart.yaml" {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return "", err
+		}
+		return parseChartName(data, archive+":"+header.Name)
+	}
+
+	return "", fmt.Errorf("no Chart.yaml found in %s", archive)
+}
@@ -0,0 +1,213 @@
+package helm
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+func writeChartDir(t *testing.T, dir, name string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	chart := fmt.Sprintf("apiVersion: v2\nname: %s\nversion: 0.1.0\n", name)
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chart), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writePackagedChart writes a chart named name, with a dependency in
+// its charts folder, as "helm package" would.
+func writePackagedChart(t *testing.T, file, name string) {
+	t.Helper()
+
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	entries := []struct{ name, body string }{
+		{name + "/charts/redis/Chart.yaml", "name: redis\n"},
+		{name + "/Chart.yaml", fmt.Sprintf("apiVersion: v2\nname: %s\nversion: 0.1.0\n", name)},
+		{name + "/values.yaml", "replicas: 1\n"},
+	}
+	for _, entry := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0600, Size: int64(len(entry.body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func Test_IsLocalChart(t *testing.T) {
+	dir := t.TempDir()
+	writeChartDir(t, filepath.Join(dir, "api"), "api")
+	if err := os.MkdirAll(filepath.Join(dir, "openfaas", "openfaas"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		chart string
+		want  bool
+	}{
+		{chart: filepath.Join(dir, "api"), want: true},
+		{chart: "./api-0.1.0.tgz", want: true},
+		{chart: "https://example.com/charts/api-0.1.0.tgz", want: true},
+		{chart: "openfaas/openfaas", want: false},
+		{chart: filepath.Join(dir, "openfaas", "openfaas"), want: false},
+		{chart: "oci://ghcr.io/openfaas/charts/openfaas", want: false},
+	}
+
+	for _, tc := range cases {
+		if got := IsLocalChart(tc.chart); got != tc.want {
+			t.Errorf("%s: want %v, got %v", tc.chart, tc.want, got)
+		}
+	}
+}
+
+func Test_LocalChart_PackagedChartName(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api-0.1.0.tgz")
+	writePackagedChart(t, file, "api")
+
+	path, name, err := LocalChart(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != file {
+		t.Errorf("want path %s, got %s", file, path)
+	}
+	if name != "api" {
+		t.Errorf("want name api, got %s", name)
+	}
+}
+
+func Test_LocalChart_URL(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api-0.1.0.tgz")
+	writePackagedChart(t, file, "api")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, file)
+	}))
+	defer server.Close()
+
+	path, name, err := LocalChart(server.URL + "/charts/api-0.1.0.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name != "api" {
+		t.Errorf("want name api, got %s", name)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("want the chart downloaded: %s", err)
+	}
+
+	// Downloaded into ChartsDir, so that CleanCharts removes it
+	dir, _ := ChartsDir()
+	if !strings.HasPrefix(path, dir+string(os.PathSeparator)) {
+		t.Errorf("want the chart downloaded into %s, got %s", dir, path)
+	}
+}
+
+func Test_LocalChart_URLDryRun(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	d, err := k8s.NewDryRunExecutor(io.Discard, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8s.SetExecutor(d)
+	defer k8s.SetExecutor(nil)
+
+	url := server.URL + "/charts/api-gateway-0.1.0.tgz"
+	path, name, err := LocalChart(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != url || name != "api-gateway" {
+		t.Errorf("want %s and api-gateway, got %s and %s", url, path, name)
+	}
+	if requests > 0 {
+		t.Errorf("want no download for a dry-run, got %d request(s)", requests)
+	}
+}
+
+func Test_chartURLName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/charts/api-0.1.0.tgz", "api"},
+		{"https://example.com/charts/api-gateway-1.2.3-rc.1.tgz", "api-gateway"},
+		{"https://example.com/charts/api-v2-0.1.0.tar.gz?token=x", "api-v2"},
+		{"https://example.com/charts/api.tgz", "api"},
+	}
+
+	for _, tc := range tests {
+		if got := chartURLName(tc.url); got != tc.want {
+			t.Errorf("%s: want %s, got %s", tc.url, tc.want, got)
+		}
+	}
+}
+
+func Test_Helm3LocalUpgrade_Directory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "chart")
+	writeChartDir(t, dir, "api")
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	err := Helm3LocalUpgrade(dir, "dev", []string{"dev.yaml"}, map[string]string{"replicas": "2"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"upgrade", "--install", "api", dir, "--namespace", "dev", "--dependency-update", "--wait",
+		"--values", "dev.yaml", "--set", "replicas=2"}
+	if fmt.Sprint(fake.tasks[0].Args) != fmt.Sprint(want) {
+		t.Errorf("want args %v, got %v", want, fake.tasks[0].Args)
+	}
+}
+
+func Test_Helm3LocalUpgrade_PackagedChart(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api-0.1.0.tgz")
+	writePackagedChart(t, file, "api")
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	if err := Helm3LocalUpgrade(file, "dev", nil, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"upgrade", "--install", "api", file, "--namespace", "dev"}
+	if fmt.Sprint(fake.tasks[0].Args) != fmt.Sprint(want) {
+		t.Errorf("want args %v, got %v", want, fake.tasks[0].Args)
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexellis/arkade/pkg/images"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// PostRenderCommand is the hidden arkade command which helm runs to
+// post-render a chart, see PostRender.
+const PostRenderCommand = "helm-post-render"
+
+// PostRender holds kustomize patches, and a kustomize directory, to
+// apply to a chart's rendered manifests before helm installs them, and
+// a registry to mirror the chart's images to.
+type PostRender struct {
+	// Patches are strategic merge patch files, each of which names the
+	// objects it patches. A JSON 6902 patch needs a target, so it must be
+	// listed in the kustomization.yaml of Kustomize instead.
+	Patches []string
+
+	// Kustomize is a directory with a kustomization.yaml, which is
+	// given the rendered manifests as a resource. Its files are read
+	// from where they are, so that paths such as "../base" work.
+	Kustomize string
+
+	// Mirror is a registry, such as "registry.example.com:5000", which
+	// the images are copied to and then installed from.
+	Mirror string
+
+	// MirrorInsecure allows a Mirror served over plain HTTP.
+	MirrorInsecure bool
+}
+
+// Empty is true when there is nothing to post-render.
+func (p PostRender) Empty() bool {
+	return len(p.Patches) == 0 && len(p.Kustomize) == 0 && len(p.Mirror) == 0
+}
+
+func (p PostRender) kustomize() bool {
+	return len(p.Patches) > 0 || len(p.Kustomize) > 0
+}
+
+// Args are the arguments for the PostRenderCommand.
+func (p PostRender) Args() []string {
+	var args []string
+	for _, patch := range p.Patches {
+		args = append(args, "--patch="+patch)
+	}
+	if len(p.Kustomize) > 0 {
+		args = append(args, "--kustomize="+p.Kustomize)
+	}
+	if len(p.Mirror) > 0 {
+		args = append(args, "--mirror="+p.Mirror)
+	}
+	if p.MirrorInsecure {
+		args = append(args, "--mirror-insecure")
+	}
+	return args
+}
+
+var (
+	postRender   PostRender
+	postRenderMu sync.RWMutex
+)
+
+// Abs checks the patches and kustomize directory exist, and makes their
+// paths absolute, as helm runs in the chart's directory.
+func (p PostRender) Abs() (PostRender, error) {
+	resolved := PostRender{Mirror: p.Mirror, MirrorInsecure: p.MirrorInsecure}
+	if len(p.Mirror) > 0 {
+		if err := images.ValidateMirror(p.Mirror); err != nil {
+			return PostRender{}, err
+		}
+	}
+	for _, patch := range p.Patches {
+		abs, err := existingPath(patch)
+		if err != nil {
+			return PostRender{}, fmt.Errorf("unable to read patch: %w", err)
+		}
+		if _, err := readPatch(abs); err != nil {
+			return PostRender{}, err
+		}
+		resolved.Patches = append(resolved.Patches, abs)
+	}
+	if len(p.Kustomize) > 0 {
+		abs, err := existingPath(p.Kustomize)
+		if err != nil {
+			return PostRender{}, fmt.Errorf("unable to read kustomize directory: %w", err)
+		}
+		resolved.Kustomize = abs
+	}
+	return resolved, nil
+}
+
+// SetPostRender post-renders every chart installed, as set by
+// "arkade install APP --patch/--kustomize/--mirror". An empty PostRender
+// turns it off.
+func SetPostRender(p PostRender) error {
+	resolved, err := p.Abs()
+	if err != nil {
+		return err
+	}
+
+	postRenderMu.Lock()
+	defer postRenderMu.Unlock()
+
+	postRender = resolved
+	return nil
+}
+
+// GetPostRender returns the PostRender given to SetPostRender.
+func GetPostRender() PostRender {
+	postRenderMu.RLock()
+	defer postRenderMu.RUnlock()
+
+	return postRender
+}
+
+// readPatch reads a strategic merge patch, and rejects a JSON 6902
+// patch, i.e. a list of "op" and "path", as it has no target.
+func readPatch(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []map[string]interface{}
+	if err := yaml.Unmarshal(data, &ops); err == nil && len(ops) > 0 {
+		if _, ok := ops[0]["op"]; ok {
+			return nil, fmt.Errorf("%s is a JSON 6902 patch, which needs a target: list it under patches with a target in a kustomization.yaml, and pass its directory with --kustomize", file)
+		}
+	}
+
+	return data, nil
+}
+
+func existingPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "", err
+	}
+	return abs, nil
+}
+
+// postRenderArgs are the flags for helm to run arkade as a post-renderer,
+// when SetPostRender was given patches or a mirror.
+func postRenderArgs() ([]string, error) {
+	postRenderMu.RLock()
+	p := postRender
+	postRenderMu.RUnlock()
+
+	if p.Empty() {
+		return nil, nil
+	}
+
+	arkade, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find arkade for --post-renderer: %w", err)
+	}
+
+	args := []string{"--post-renderer", arkade, "--post-renderer-args", PostRenderCommand}
+	for _, arg := range p.Args() {
+		args = append(args, "--post-renderer-args", arg)
+	}
+	return args, nil
+}
+
+// RenderManifests applies the patches to the manifests read from in
+// with "kubectl kustomize", then copies their images to the mirror and
+// points them at it, and writes the result to out.
+func RenderManifests(ctx context.Context, p PostRender, in io.Reader, out io.Writer, progress io.Writer) error {
+	manifests, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("unable to read rendered manifests: %w", err)
+	}
+
+	if p.kustomize() {
+		if manifests, err = kustomizeManifests(ctx, p, manifests); err != nil {
+			return err
+		}
+	}
+
+	if len(p.Mirror) > 0 {
+		if manifests, err = mirrorManifests(ctx, p, manifests, progress); err != nil {
+			return err
+		}
+	}
+
+	_, err = out.Write(manifests)
+	return err
+}
+
+func mirrorManifests(ctx context.Context, p PostRender, manifests []byte, progress io.Writer) ([]byte, error) {
+	found, err := images.ManifestImages(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrored, err := images.Mirror(ctx, found, p.Mirror, p.MirrorInsecure, progress)
+	if err != nil {
+		return nil, err
+	}
+	if len(mirrored) == 0 {
+		return manifests, nil
+	}
+
+	return images.RewriteImages(manifests, mirrored)
+}
+
+func kustomizeManifests(ctx context.Context, p PostRender, manifests []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "arkade-post-render-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(root, "helm-output.yaml"), manifests, 0600); err != nil {
+		return nil, err
+	}
+
+	kustomization, err := postRenderKustomization(p, root)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(root, "kustomization.yaml"), data, 0600); err != nil {
+		return nil, err
+	}
+
+	args := []string{"kustomize", root}
+	if len(p.Kustomize) > 0 {
+		// The kustomization refers to files outside of root
+		args = append(args, "--load-restrictor=LoadRestrictionsNone")
+	}
+
+	task := execute.ExecTask{
+		Command:     "kubectl",
+		Args:        args,
+		StreamStdio: false,
+	}
+	res, err := task.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("kubectl kustomize failed with exit code %d: %s", res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+
+	return []byte(res.Stdout), nil
+}
+
+// postRenderKustomization starts from the kustomization.yaml in the
+// Kustomize directory, with its paths made absolute so that it can be
+// used from root, or an empty one. The rendered manifests in root are
+// added as a resource, and each patch is copied in.
+func postRenderKustomization(p PostRender, root string) (map[string]interface{}, error) {
+	kustomization := map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+	}
+
+	if len(p.Kustomize) > 0 {
+		file := ""
+		for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+			if _, err := os.Stat(filepath.Join(p.Kustomize, name)); err == nil {
+				file = filepath.Join(p.Kustomize, name)
+				break
+			}
+		}
+		if len(file) == 0 {
+			return nil, fmt.Errorf("no kustomization.yaml found in %s", p.Kustomize)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &kustomization); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", file, err)
+		}
+		absKustomization(kustomization, p.Kustomize)
+	}
+
+	resources, _ := kustomization["resources"].([]interface{})
+	kustomization["resources"] = append([]interface{}{"helm-output.yaml"}, resources...)
+
+	patches, _ := kustomization["patches"].([]interface{})
+	for i, patch := range p.Patches {
+		data, err := readPatch(patch)
+		if err != nil {
+			return nil, err
+		}
+
+		name := fmt.Sprintf("arkade-patch-%d%s", i+1, filepath.Ext(patch))
+		if err := os.WriteFile(filepath.Join(root, name), data, 0600); err != nil {
+			return nil, err
+		}
+		patches = append(patches, map[string]interface{}{"path": name})
+	}
+	if len(patches) > 0 {
+		kustomization["patches"] = patches
+	}
+
+	return kustomization, nil
+}
+
+// kustomizationPaths are the fields of a kustomization.yaml which hold
+// a path, or a list of them.
+var kustomizationPaths = []string{
+	"resources", "components", "crds", "configurations", "patchesStrategicMerge",
+	"generators", "transformers", "validators",
+}
+
+// kustomizationPathItems are lists of objects with a "path".
+var kustomizationPathItems = []string{"patches", "patchesJson6902", "replacements"}
+
+// absKustomization makes each relative path in a kustomization absolute
+// to dir. Remote resources and inline patches are left as they are.
+func absKustomization(kustomization map[string]interface{}, dir string) {
+	for _, key := range kustomizationPaths {
+		if items, ok := kustomization[key].([]interface{}); ok {
+			for i, item := range items {
+				items[i] = absPath(item, dir)
+			}
+		}
+	}
+
+	for _, key := range kustomizationPathItems {
+		items, _ := kustomization[key].([]interface{})
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok && m["path"] != nil {
+				m["path"] = absPath(m["path"], dir)
+			}
+		}
+	}
+
+	if openapi, ok := kustomization["openapi"].(map[string]interface{}); ok && openapi["path"] != nil {
+		openapi["path"] = absPath(openapi["path"], dir)
+	}
+
+	for _, key := range []string{"configMapGenerator", "secretGenerator"} {
+		generators, _ := kustomization[key].([]interface{})
+		for _, item := range generators {
+			generator, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, field := range []string{"files", "envs"} {
+				files, _ := generator[field].([]interface{})
+				for i, file := range files {
+					// A file may be given a key, i.e. "config.json=app.json"
+					if s, ok := file.(string); ok {
+						if k, v, found := strings.Cut(s, "="); found {
+							files[i] = k + "=" + absPath(v, dir).(string)
+							continue
+						}
+					}
+					files[i] = absPath(file, dir)
+				}
+			}
+			if generator["env"] != nil {
+				generator["env"] = absPath(generator["env"], dir)
+			}
+		}
+	}
+}
+
+func absPath(value interface{}, dir string) interface{} {
+	path, ok := value.(string)
+	if !ok || len(path) == 0 || filepath.IsAbs(path) || strings.ContainsAny(path, "\n:?") ||
+		strings.HasPrefix(path, "github.com/") || strings.HasPrefix(path, "gitlab.com/") || strings.HasPrefix(path, "bitbucket.org/") {
+		return value
+	}
+	return filepath.Join(dir, path)
+}
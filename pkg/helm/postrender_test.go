@@ -0,0 +1,242 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+)
+
+func writeFile(t *testing.T, file, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_SetPostRender_MissingPatch(t *testing.T) {
+	err := SetPostRender(PostRender{Patches: []string{filepath.Join(t.TempDir(), "missing.yaml")}})
+	if err == nil {
+		t.Fatal("want error for a missing patch")
+	}
+}
+
+func Test_PostRender_Abs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "replicas.yaml"), "kind: Deployment\n")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := PostRender{Patches: []string{"replicas.yaml"}, Kustomize: "."}.Abs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := PostRender{Patches: []string{filepath.Join(dir, "replicas.yaml")}, Kustomize: dir}
+	if fmt.Sprint(p) != fmt.Sprint(want) {
+		t.Errorf("want %v, got %v", want, p)
+	}
+}
+
+func Test_Helm3Upgrade_PostRenderer(t *testing.T) {
+	patch := filepath.Join(t.TempDir(), "replicas.yaml")
+	writeFile(t, patch, "kind: Deployment\n")
+
+	if err := SetPostRender(PostRender{Patches: []string{patch}}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetPostRender(PostRender{})
+
+	fake := &recordingExecutor{}
+	k8s.SetExecutor(fake)
+	defer k8s.SetExecutor(nil)
+
+	if err := Helm3OCIUpgrade("oci://ghcr.io/openfaas/charts/openfaas", "openfaas", nil, "", nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	arkade, _ := os.Executable()
+	want := []string{"--post-renderer", arkade, "--post-renderer-args", PostRenderCommand, "--post-renderer-args", "--patch=" + patch}
+	args := fake.tasks[0].Args
+	if got := args[len(args)-len(want):]; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("want args ending %v, got %v", want, args)
+	}
+}
+
+func Test_postRenderKustomization_Patches(t *testing.T) {
+	patch := filepath.Join(t.TempDir(), "replicas.yaml")
+	writeFile(t, patch, "kind: Deployment\n")
+
+	root := t.TempDir()
+	kustomization, err := postRenderKustomization(PostRender{Patches: []string{patch}}, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := yaml.Marshal(kustomization)
+	want := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patches:
+    - path: arkade-patch-1.yaml
+resources:
+    - helm-output.yaml
+`
+	if string(got) != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "arkade-patch-1.yaml")); err != nil {
+		t.Errorf("want patch copied: %s", err)
+	}
+}
+
+func Test_postRenderKustomization_Directory(t *testing.T) {
+	overlays := t.TempDir()
+	dir := filepath.Join(overlays, "openfaas")
+	writeFile(t, filepath.Join(dir, "kustomization.yaml"), `resources:
+- network-policy.yaml
+- ../base
+- https://github.com/openfaas/faas-netes/raw/master/namespaces.yml
+patches:
+- path: labels.yaml
+configMapGenerator:
+- name: settings
+  files:
+  - settings.json=../shared/settings.json
+commonLabels:
+  team: payments
+`)
+	writeFile(t, filepath.Join(dir, "network-policy.yaml"), "kind: NetworkPolicy\n")
+	writeFile(t, filepath.Join(dir, "labels.yaml"), "kind: Deployment\n")
+
+	patch := filepath.Join(t.TempDir(), "replicas.yaml")
+	writeFile(t, patch, "kind: Deployment\n")
+
+	root := t.TempDir()
+	kustomization, err := postRenderKustomization(PostRender{Patches: []string{patch}, Kustomize: dir}, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := yaml.Marshal(kustomization)
+	want := fmt.Sprintf(`apiVersion: kustomize.config.k8s.io/v1beta1
+commonLabels:
+    team: payments
+configMapGenerator:
+    - files:
+        - settings.json=%[1]s/shared/settings.json
+      name: settings
+kind: Kustomization
+patches:
+    - path: %[1]s/openfaas/labels.yaml
+    - path: arkade-patch-1.yaml
+resources:
+    - helm-output.yaml
+    - %[1]s/openfaas/network-policy.yaml
+    - %[1]s/base
+    - https://github.com/openfaas/faas-netes/raw/master/namespaces.yml
+`, overlays)
+	if string(got) != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+
+	// The directory is used where it is, rather than copied
+	if _, err := os.Stat(filepath.Join(root, "network-policy.yaml")); err == nil {
+		t.Errorf("want network-policy.yaml left in %s", dir)
+	}
+}
+
+func Test_PostRender_AbsRejectsJSON6902(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "json6902.yaml"), "- op: replace\n  path: /spec/replicas\n  value: 3\n")
+	writeFile(t, filepath.Join(dir, "json6902.json"), `[{"op": "add", "path": "/metadata/labels/team", "value": "payments"}]`)
+	writeFile(t, filepath.Join(dir, "replicas.yaml"), "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: gateway\nspec:\n  replicas: 3\n")
+
+	for _, name := range []string{"json6902.yaml", "json6902.json"} {
+		_, err := PostRender{Patches: []string{filepath.Join(dir, name)}}.Abs()
+		if err == nil || !strings.Contains(err.Error(), "JSON 6902") {
+			t.Errorf("%s: want a JSON 6902 error, got: %v", name, err)
+		}
+	}
+
+	if _, err := (PostRender{Patches: []string{filepath.Join(dir, "replicas.yaml")}}).Abs(); err != nil {
+		t.Errorf("want a strategic merge patch accepted, got: %v", err)
+	}
+}
+
+func Test_postRenderKustomization_NoKustomization(t *testing.T) {
+	if _, err := postRenderKustomization(PostRender{Kustomize: t.TempDir()}, t.TempDir()); err == nil {
+		t.Fatal("want error without a kustomization.yaml")
+	}
+}
+
+func Test_RenderManifests_Mirror(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	image := host + "/openfaas/gateway:0.27.0"
+	if err := crane.Push(empty.Image, image); err != nil {
+		t.Fatal(err)
+	}
+
+	manifests := fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: gateway
+spec:
+  template:
+    spec:
+      containers:
+        - name: gateway
+          image: %s
+`, image)
+
+	out := &bytes.Buffer{}
+	p := PostRender{Mirror: host + "/airgap", MirrorInsecure: true}
+	if err := RenderManifests(context.Background(), p, strings.NewReader(manifests), out, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.ReplaceAll(manifests, image, host+"/airgap/openfaas/gateway:0.27.0")
+	if out.String() != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+func Test_PostRender_ArgsMirror(t *testing.T) {
+	p := PostRender{Mirror: "registry.local:5000", MirrorInsecure: true}
+
+	want := []string{"--mirror=registry.local:5000", "--mirror-insecure"}
+	if got := p.Args(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if _, err := (PostRender{Mirror: "https://registry.local"}).Abs(); err == nil {
+		t.Errorf("want error for a mirror URL")
+	}
+}
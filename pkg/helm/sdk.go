@@ -0,0 +1,593 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/spf13/pflag"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// Each helm command run by arkade is given to the Helm SDK in-process,
+// so that no helm binary is needed. The commands are still built as
+// tasks, so that a dry-run, export or recording sees what was run.
+func init() {
+	k8s.RegisterCommand("helm", Run)
+}
+
+// newConfiguration creates the Helm SDK configuration for a namespace,
+// it is replaced by tests to use a fake cluster.
+var newConfiguration = func(settings *cli.EnvSettings, namespace string) (*action.Configuration, error) {
+	cfg := new(action.Configuration)
+	if err := cfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// helmFlags are the flags which arkade passes to helm, for every
+// command it supports.
+type helmFlags struct {
+	kubeContext string
+	namespace   string
+
+	install          bool
+	version          string
+	wait             bool
+	timeout          time.Duration
+	dependencyUpdate bool
+	dryRun           string
+	output           string
+	values           values.Options
+
+	postRenderer     string
+	postRendererArgs []string
+
+	registryConfig string
+	caFile         string
+	certFile       string
+	keyFile        string
+
+	username      string
+	passwordStdin bool
+	forceUpdate   bool
+
+	untar       bool
+	untarDir    string
+	destination string
+	filter      string
+}
+
+func parseHelmFlags(args []string) (*helmFlags, []string, error) {
+	f := &helmFlags{}
+
+	fs := pflag.NewFlagSet("helm", pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	fs.StringVar(&f.kubeContext, "kube-context", "", "")
+	fs.StringVarP(&f.namespace, "namespace", "n", "", "")
+	fs.BoolVarP(&f.install, "install", "i", false, "")
+	fs.StringVar(&f.version, "version", "", "")
+	fs.BoolVar(&f.wait, "wait", false, "")
+	fs.DurationVar(&f.timeout, "timeout", 5*time.Minute, "")
+	fs.BoolVar(&f.dependencyUpdate, "dependency-update", false, "")
+	fs.StringVar(&f.dryRun, "dry-run", "none", "")
+	fs.Lookup("dry-run").NoOptDefVal = "client"
+	fs.StringVarP(&f.output, "output", "o", "", "")
+	fs.StringArrayVarP(&f.values.ValueFiles, "values", "f", nil, "")
+	fs.StringArrayVar(&f.values.Values, "set", nil, "")
+	fs.StringArrayVar(&f.values.StringValues, "set-string", nil, "")
+	fs.StringArrayVar(&f.values.FileValues, "set-file", nil, "")
+	fs.StringVar(&f.postRenderer, "post-renderer", "", "")
+	fs.StringArrayVar(&f.postRendererArgs, "post-renderer-args", nil, "")
+	fs.StringVar(&f.registryConfig, "registry-config", "", "")
+	fs.StringVar(&f.caFile, "ca-file", "", "")
+	fs.StringVar(&f.certFile, "cert-file", "", "")
+	fs.StringVar(&f.keyFile, "key-file", "", "")
+	fs.StringVar(&f.username, "username", "", "")
+	fs.BoolVar(&f.passwordStdin, "password-stdin", false, "")
+	fs.BoolVar(&f.forceUpdate, "force-update", false, "")
+	fs.BoolVar(&f.untar, "untar", false, "")
+	fs.StringVar(&f.untarDir, "untardir", ".", "")
+	fs.StringVarP(&f.destination, "destination", "d", ".", "")
+	fs.StringVar(&f.filter, "filter", "", "")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	return f, fs.Args(), nil
+}
+
+// Run runs a helm task with the Helm SDK, for the subset of helm's
+// commands and flags used by arkade. Relative values files are read
+// from the task's working directory, and KUBECONFIG from its
+// environment.
+func Run(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	flags, args, err := parseHelmFlags(task.Args)
+	if err != nil {
+		return execute.ExecResult{}, err
+	}
+
+	var stdout bytes.Buffer
+	var out io.Writer = &stdout
+	if task.StreamStdio {
+		out = io.MultiWriter(&stdout, os.Stdout)
+	}
+
+	settings := cli.New()
+	if kubeconfig := lookupEnv(task.Env, "KUBECONFIG"); len(kubeconfig) > 0 {
+		settings.KubeConfig = kubeconfig
+	}
+	if len(flags.kubeContext) > 0 {
+		settings.KubeContext = flags.kubeContext
+	}
+	if len(flags.namespace) > 0 {
+		settings.SetNamespace(flags.namespace)
+	}
+	if len(flags.registryConfig) > 0 {
+		settings.RegistryConfig = flags.registryConfig
+	}
+
+	for i, file := range flags.values.ValueFiles {
+		if len(task.Cwd) > 0 && !filepath.IsAbs(file) && !isURL(file) {
+			flags.values.ValueFiles[i] = filepath.Join(task.Cwd, file)
+		}
+	}
+
+	r := &runner{settings: settings, flags: flags, out: out, stdin: task.Stdin}
+
+	command := strings.Join(args, " ")
+	switch {
+	case len(args) == 4 && args[0] == "repo" && args[1] == "add":
+		err = r.repoAdd(ctx, args[2], args[3])
+	case len(args) == 2 && args[0] == "repo" && args[1] == "update":
+		err = r.repoUpdate()
+	case len(args) == 2 && (args[0] == "fetch" || args[0] == "pull"):
+		err = r.pull(args[1])
+	case len(args) == 3 && (args[0] == "upgrade" || args[0] == "install"):
+		flags.install = flags.install || args[0] == "install"
+		err = r.upgrade(ctx, args[1], args[2])
+	case len(args) == 2 && args[0] == "uninstall":
+		err = r.uninstall(args[1])
+	case len(args) == 1 && args[0] == "list":
+		err = r.list()
+	case len(args) == 3 && args[0] == "show" && args[1] == "values":
+		err = r.showValues(args[2])
+	case len(args) == 3 && args[0] == "get" && args[1] == "values":
+		err = r.getValues(args[2])
+	default:
+		err = fmt.Errorf("helm %s is not supported by arkade", command)
+	}
+
+	return execute.ExecResult{Stdout: stdout.String()}, err
+}
+
+func lookupEnv(env []string, key string) string {
+	value := ""
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+type runner struct {
+	settings *cli.EnvSettings
+	flags    *helmFlags
+	out      io.Writer
+	stdin    io.Reader
+}
+
+func (r *runner) configuration() (*action.Configuration, error) {
+	cfg, err := newConfiguration(r.settings, r.settings.Namespace())
+	if err != nil {
+		return nil, err
+	}
+
+	registryClient, err := r.registryClient()
+	if err != nil {
+		return nil, err
+	}
+	cfg.RegistryClient = registryClient
+
+	return cfg, nil
+}
+
+func (r *runner) registryClient() (*registry.Client, error) {
+	if len(r.flags.certFile) > 0 || len(r.flags.keyFile) > 0 || len(r.flags.caFile) > 0 {
+		return registry.NewRegistryClientWithTLS(r.out, r.flags.certFile, r.flags.keyFile, r.flags.caFile,
+			false, r.settings.RegistryConfig, false)
+	}
+
+	return registry.NewClient(
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(r.out),
+		registry.ClientOptCredentialsFile(r.settings.RegistryConfig),
+	)
+}
+
+func (r *runner) chartPathOptions() action.ChartPathOptions {
+	return action.ChartPathOptions{
+		Version:  r.flags.version,
+		CaFile:   r.flags.caFile,
+		CertFile: r.flags.certFile,
+		KeyFile:  r.flags.keyFile,
+	}
+}
+
+// repoAdd adds, or with --force-update replaces, a chart repository in
+// helm's repositories.yaml, which is locked as it may be shared with
+// other installs.
+func (r *runner) repoAdd(ctx context.Context, name, url string) error {
+	repoFile := r.settings.RepositoryConfig
+	if err := os.MkdirAll(filepath.Dir(repoFile), 0755); err != nil {
+		return err
+	}
+
+	lock := flock.New(strings.TrimSuffix(repoFile, filepath.Ext(repoFile)) + ".lock")
+	lockCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if _, err := lock.TryLockContext(lockCtx, time.Second); err != nil {
+		return fmt.Errorf("unable to lock %s: %w", repoFile, err)
+	}
+	defer lock.Unlock()
+
+	f, err := repo.LoadFile(repoFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if f == nil {
+		f = repo.NewFile()
+	}
+
+	entry := repo.Entry{
+		Name:     name,
+		URL:      url,
+		Username: r.flags.username,
+		CertFile: r.flags.certFile,
+		KeyFile:  r.flags.keyFile,
+		CAFile:   r.flags.caFile,
+	}
+	if r.flags.passwordStdin && r.stdin != nil {
+		password, err := io.ReadAll(r.stdin)
+		if err != nil {
+			return err
+		}
+		entry.Password = strings.TrimRight(string(password), "\r\n")
+	}
+
+	if !r.flags.forceUpdate && f.Has(name) {
+		if *f.Get(name) != entry {
+			return fmt.Errorf("repository name (%s) already exists, please specify a different name", name)
+		}
+		fmt.Fprintf(r.out, "%q already exists with the same configuration, skipping\n", name)
+		return nil
+	}
+
+	chartRepo, err := repo.NewChartRepository(&entry, getter.All(r.settings))
+	if err != nil {
+		return err
+	}
+	chartRepo.CachePath = r.settings.RepositoryCache
+	if _, err := chartRepo.DownloadIndexFile(); err != nil {
+		return fmt.Errorf("looks like %q is not a valid chart repository or cannot be reached: %w", url, err)
+	}
+
+	f.Update(&entry)
+	if err := f.WriteFile(repoFile, 0600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(r.out, "%q has been added to your repositories\n", name)
+	return nil
+}
+
+// repoUpdate downloads the index of each chart repository.
+func (r *runner) repoUpdate() error {
+	f, err := repo.LoadFile(r.settings.RepositoryConfig)
+	if err != nil {
+		return fmt.Errorf("no repositories found: %w", err)
+	}
+
+	var failed []string
+	for _, entry := range f.Repositories {
+		chartRepo, err := repo.NewChartRepository(entry, getter.All(r.settings))
+		if err != nil {
+			return err
+		}
+		chartRepo.CachePath = r.settings.RepositoryCache
+
+		if _, err := chartRepo.DownloadIndexFile(); err != nil {
+			fmt.Fprintf(r.out, "...Unable to get an update from the %q chart repository (%s):\n\t%s\n", entry.Name, entry.URL, err)
+			failed = append(failed, entry.URL)
+			continue
+		}
+		fmt.Fprintf(r.out, "...Successfully got an update from the %q chart repository\n", entry.Name)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to update the repositories: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (r *runner) pull(chartRef string) error {
+	cfg, err := r.configuration()
+	if err != nil {
+		return err
+	}
+
+	pull := action.NewPullWithOpts(action.WithConfig(cfg))
+	pull.Settings = r.settings
+	pull.ChartPathOptions = r.chartPathOptions()
+	pull.SetRegistryClient(cfg.RegistryClient)
+	pull.Untar = r.flags.untar
+	pull.UntarDir = r.flags.untarDir
+	pull.DestDir = r.flags.destination
+
+	output, err := pull.Run(chartRef)
+	fmt.Fprint(r.out, output)
+	return err
+}
+
+// upgrade installs or upgrades a release, as "helm upgrade --install".
+func (r *runner) upgrade(ctx context.Context, name, chartRef string) error {
+	cfg, err := r.configuration()
+	if err != nil {
+		return err
+	}
+
+	install := false
+	if r.flags.install {
+		history := action.NewHistory(cfg)
+		history.Max = 1
+		versions, err := history.Run(name)
+		if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return err
+		}
+		install = err != nil || releaseUninstalled(versions)
+	}
+
+	pathOptions := r.chartPathOptions()
+	var postRenderer postrender.PostRenderer
+	if len(r.flags.postRenderer) > 0 {
+		if postRenderer, err = postrender.NewExec(r.flags.postRenderer, r.flags.postRendererArgs...); err != nil {
+			return err
+		}
+	}
+
+	var rel *release.Release
+	if install {
+		client := action.NewInstall(cfg)
+		client.ChartPathOptions = pathOptions
+		client.SetRegistryClient(cfg.RegistryClient)
+		client.ReleaseName = name
+		client.Namespace = r.settings.Namespace()
+		client.Wait = r.flags.wait
+		client.Timeout = r.flags.timeout
+		client.DryRunOption = r.flags.dryRun
+		client.PostRenderer = postRenderer
+
+		ch, err := r.loadChart(&client.ChartPathOptions, chartRef)
+		if err != nil {
+			return err
+		}
+		vals, err := r.flags.values.MergeValues(getter.All(r.settings))
+		if err != nil {
+			return err
+		}
+		if rel, err = client.RunWithContext(ctx, ch, vals); err != nil {
+			return err
+		}
+	} else {
+		client := action.NewUpgrade(cfg)
+		client.ChartPathOptions = pathOptions
+		client.SetRegistryClient(cfg.RegistryClient)
+		client.Namespace = r.settings.Namespace()
+		client.Install = r.flags.install
+		client.Wait = r.flags.wait
+		client.Timeout = r.flags.timeout
+		client.DryRunOption = r.flags.dryRun
+		client.PostRenderer = postRenderer
+
+		ch, err := r.loadChart(&client.ChartPathOptions, chartRef)
+		if err != nil {
+			return err
+		}
+		vals, err := r.flags.values.MergeValues(getter.All(r.settings))
+		if err != nil {
+			return err
+		}
+		if rel, err = client.RunWithContext(ctx, name, ch, vals); err != nil {
+			return err
+		}
+	}
+
+	if r.flags.output == "json" {
+		return json.NewEncoder(r.out).Encode(rel)
+	}
+	printRelease(r.out, rel)
+	return nil
+}
+
+// loadChart finds a chart locally, in a repository or registry, and
+// loads it, fetching missing dependencies with --dependency-update.
+func (r *runner) loadChart(pathOptions *action.ChartPathOptions, chartRef string) (*chart.Chart, error) {
+	path, err := pathOptions.LocateChart(chartRef, r.settings)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if deps := ch.Metadata.Dependencies; deps != nil {
+		if err := action.CheckDependencies(ch, deps); err != nil {
+			if !r.flags.dependencyUpdate {
+				return nil, fmt.Errorf("the dependencies of %s are missing: %w", chartRef, err)
+			}
+
+			manager := &downloader.Manager{
+				Out:              r.out,
+				ChartPath:        path,
+				Getters:          getter.All(r.settings),
+				RepositoryConfig: r.settings.RepositoryConfig,
+				RepositoryCache:  r.settings.RepositoryCache,
+			}
+			if err := manager.Update(); err != nil {
+				return nil, err
+			}
+			if ch, err = loader.Load(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ch, nil
+}
+
+func releaseUninstalled(versions []*release.Release) bool {
+	return len(versions) > 0 && versions[len(versions)-1].Info.Status == release.StatusUninstalled
+}
+
+func printRelease(out io.Writer, rel *release.Release) {
+	fmt.Fprintf(out, "NAME: %s\n", rel.Name)
+	fmt.Fprintf(out, "NAMESPACE: %s\n", rel.Namespace)
+	if rel.Info != nil {
+		fmt.Fprintf(out, "STATUS: %s\n", rel.Info.Status)
+	}
+	fmt.Fprintf(out, "REVISION: %d\n", rel.Version)
+	if rel.Info != nil && len(strings.TrimSpace(rel.Info.Notes)) > 0 {
+		fmt.Fprintf(out, "NOTES:\n%s\n", strings.TrimSpace(rel.Info.Notes))
+	}
+}
+
+func (r *runner) uninstall(name string) error {
+	cfg, err := r.configuration()
+	if err != nil {
+		return err
+	}
+
+	client := action.NewUninstall(cfg)
+	client.Wait = r.flags.wait
+	client.Timeout = r.flags.timeout
+
+	res, err := client.Run(name)
+	if err != nil {
+		return err
+	}
+	if res != nil && len(res.Info) > 0 {
+		fmt.Fprintln(r.out, res.Info)
+	}
+	fmt.Fprintf(r.out, "release %q uninstalled\n", name)
+	return nil
+}
+
+// listedRelease is a release as printed by "helm list --output json".
+type listedRelease struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Revision   int    `json:"revision"`
+	Status     string `json:"status"`
+	Chart      string `json:"chart"`
+	AppVersion string `json:"app_version"`
+}
+
+func (r *runner) list() error {
+	cfg, err := r.configuration()
+	if err != nil {
+		return err
+	}
+
+	client := action.NewList(cfg)
+	client.Filter = r.flags.filter
+	client.SetStateMask()
+
+	releases, err := client.Run()
+	if err != nil {
+		return err
+	}
+
+	listed := []listedRelease{}
+	for _, rel := range releases {
+		l := listedRelease{Name: rel.Name, Namespace: rel.Namespace, Revision: rel.Version}
+		if rel.Info != nil {
+			l.Status = rel.Info.Status.String()
+		}
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			l.Chart = rel.Chart.Metadata.Name + "-" + rel.Chart.Metadata.Version
+			l.AppVersion = rel.Chart.Metadata.AppVersion
+		}
+		listed = append(listed, l)
+	}
+
+	return json.NewEncoder(r.out).Encode(listed)
+}
+
+func (r *runner) showValues(chartRef string) error {
+	cfg, err := r.configuration()
+	if err != nil {
+		return err
+	}
+
+	client := action.NewShowWithConfig(action.ShowValues, cfg)
+	client.ChartPathOptions = r.chartPathOptions()
+	client.SetRegistryClient(cfg.RegistryClient)
+
+	path, err := client.ChartPathOptions.LocateChart(chartRef, r.settings)
+	if err != nil {
+		return err
+	}
+
+	output, err := client.Run(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(r.out, output)
+	return nil
+}
+
+func (r *runner) getValues(name string) error {
+	cfg, err := r.configuration()
+	if err != nil {
+		return err
+	}
+
+	vals, err := action.NewGetValues(cfg).Run(name)
+	if err != nil {
+		return err
+	}
+	if vals == nil {
+		vals = map[string]interface{}{}
+	}
+	return json.NewEncoder(r.out).Encode(vals)
+}
@@ -0,0 +1,164 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/alexellis/arkade/pkg/k8s"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// fakeConfiguration runs helm commands against releases kept in memory,
+// and a fake cluster.
+func fakeConfiguration(t *testing.T) *action.Configuration {
+	t.Helper()
+
+	t.Setenv("HELM_CONFIG_HOME", t.TempDir())
+	t.Setenv("HELM_CACHE_HOME", t.TempDir())
+	t.Setenv("HELM_DATA_HOME", t.TempDir())
+
+	cfg := &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+
+	prev := newConfiguration
+	newConfiguration = func(*cli.EnvSettings, string) (*action.Configuration, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { newConfiguration = prev })
+
+	return cfg
+}
+
+func writeTestChart(t *testing.T) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "nginx")
+	files := map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: nginx\nversion: 0.1.0\n",
+		"values.yaml":              "replicas: 1\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: nginx\ndata:\n  replicas: {{ .Values.replicas | quote }}\n",
+	}
+	for name, data := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func runHelm(t *testing.T, args ...string) execute.ExecResult {
+	t.Helper()
+
+	res, err := k8s.TaskExecutor{}.Execute(context.Background(), execute.ExecTask{Command: "helm", Args: args})
+	if err != nil {
+		t.Fatalf("helm %s: %s", strings.Join(args, " "), err)
+	}
+	return res
+}
+
+func Test_Run_UpgradeInstallsThenUpgrades(t *testing.T) {
+	cfg := fakeConfiguration(t)
+	chart := writeTestChart(t)
+
+	res := runHelm(t, "upgrade", "--install", "nginx", chart, "--namespace", "default", "--set", "replicas=2")
+	if !strings.Contains(res.Stdout, "REVISION: 1") {
+		t.Errorf("want revision 1, got: %s", res.Stdout)
+	}
+
+	runHelm(t, "upgrade", "--install", "nginx", chart, "--namespace", "default", "--set", "replicas=3")
+
+	rel, err := cfg.Releases.Last("nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Version != 2 {
+		t.Errorf("want revision 2, got %d", rel.Version)
+	}
+	if !strings.Contains(rel.Manifest, `replicas: "3"`) {
+		t.Errorf("want replicas set to 3, got: %s", rel.Manifest)
+	}
+}
+
+func Test_Run_DryRunOutputsValues(t *testing.T) {
+	cfg := fakeConfiguration(t)
+	chart := writeTestChart(t)
+
+	res := runHelm(t, "upgrade", "--install", "nginx", chart, "--namespace", "default", "--set", "replicas=2",
+		"--dry-run", "--output=json")
+
+	var rendered struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &rendered); err != nil {
+		t.Fatalf("want a release as JSON: %s", err)
+	}
+	if got := rendered.Config["replicas"]; got != float64(2) {
+		t.Errorf("want replicas 2, got %v", got)
+	}
+
+	if _, err := cfg.Releases.Last("nginx"); err == nil {
+		t.Errorf("want no release stored by a dry-run")
+	}
+}
+
+func Test_Run_ListAndGetValues(t *testing.T) {
+	fakeConfiguration(t)
+	chart := writeTestChart(t)
+
+	runHelm(t, "upgrade", "--install", "nginx", chart, "--namespace", "default", "--set", "replicas=2")
+
+	res := runHelm(t, "list", "--namespace", "default", "--filter", "^nginx$", "--output", "json")
+	var releases []listedRelease
+	if err := json.Unmarshal([]byte(res.Stdout), &releases); err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[0].Chart != "nginx-0.1.0" {
+		t.Errorf("want nginx-0.1.0 listed, got %+v", releases)
+	}
+
+	res = runHelm(t, "get", "values", "nginx", "--namespace", "default", "--output", "json")
+	if strings.TrimSpace(res.Stdout) != `{"replicas":2}` {
+		t.Errorf("want the values set, got %s", res.Stdout)
+	}
+}
+
+func Test_Run_Uninstall(t *testing.T) {
+	cfg := fakeConfiguration(t)
+	chart := writeTestChart(t)
+
+	runHelm(t, "upgrade", "--install", "nginx", chart, "--namespace", "default")
+
+	if err := Uninstall("nginx", "default", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Releases.Deployed("nginx"); err == nil {
+		t.Errorf("want the release removed")
+	}
+}
+
+func Test_Run_Unsupported(t *testing.T) {
+	_, err := Run(context.Background(), execute.ExecTask{Command: "helm", Args: []string{"rollback", "nginx"}})
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("want an error for an unsupported command, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"gopkg.in/yaml.v3"
+)
+
+// containerKeys hold the containers of a pod spec, in a Pod, a
+// Deployment's template, a CronJob's jobTemplate, or a custom resource.
+var containerKeys = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+// ValidateMirror checks a mirror registry, such as
+// "registry.example.com:5000" or "harbor.example.com/mirror".
+func ValidateMirror(mirror string) error {
+	if strings.Contains(mirror, "://") {
+		return fmt.Errorf("invalid mirror registry %s, give the registry without a scheme", mirror)
+	}
+	if _, err := name.NewRepository(strings.TrimSuffix(mirror, "/") + "/image"); err != nil {
+		return fmt.Errorf("invalid mirror registry %s: %w", mirror, err)
+	}
+	return nil
+}
+
+// MirrorRef returns the reference for image within the mirror registry.
+// The repository is kept and the registry replaced, so that
+// "ghcr.io/openfaas/gateway:0.27.0" becomes
+// "registry.example.com/openfaas/gateway:0.27.0". Images already in the
+// mirror are returned as they are.
+func MirrorRef(image, mirror string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse image %s: %w", image, err)
+	}
+
+	mirror = strings.TrimSuffix(mirror, "/")
+	if strings.HasPrefix(ref.Context().Name(), mirror+"/") {
+		return image, nil
+	}
+
+	target := mirror + "/" + ref.Context().RepositoryStr()
+	switch r := ref.(type) {
+	case name.Digest:
+		target += "@" + r.DigestStr()
+	case name.Tag:
+		target += ":" + r.TagStr()
+	}
+	return target, nil
+}
+
+// ManifestImages returns the container images used in a stream of
+// Kubernetes manifests, sorted and without duplicates.
+func ManifestImages(manifests []byte) ([]string, error) {
+	docs, err := decodeManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	for _, doc := range docs {
+		walkContainerImages(doc, func(image *yaml.Node) {
+			found[image.Value] = true
+		})
+	}
+
+	images := make([]string, 0, len(found))
+	for image := range found {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// RewriteImages replaces the container images in a stream of Kubernetes
+// manifests with those in replacements, keyed by the original image.
+func RewriteImages(manifests []byte, replacements map[string]string) ([]byte, error) {
+	docs, err := decodeManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	for _, doc := range docs {
+		walkContainerImages(doc, func(image *yaml.Node) {
+			if replacement, ok := replacements[image.Value]; ok {
+				image.Value = replacement
+			}
+		})
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// Mirror copies each image, with all of its platforms, to the mirror
+// registry with credentials from the Docker config. It returns the
+// mirrored reference for each image. insecure allows a registry served
+// over plain HTTP.
+func Mirror(ctx context.Context, images []string, mirror string, insecure bool, progress io.Writer) (map[string]string, error) {
+	opts := []crane.Option{crane.WithContext(ctx)}
+	if insecure {
+		opts = append(opts, crane.Insecure)
+	}
+
+	mirrored := map[string]string{}
+	for _, image := range images {
+		target, err := MirrorRef(image, mirror)
+		if err != nil {
+			return nil, err
+		}
+		if target == image {
+			continue
+		}
+
+		fmt.Fprintf(progress, "Mirroring %s => %s\n", image, target)
+		if err := crane.Copy(image, target, opts...); err != nil {
+			return nil, fmt.Errorf("unable to mirror %s to %s: %w", image, target, err)
+		}
+		mirrored[image] = target
+	}
+
+	return mirrored, nil
+}
+
+func decodeManifests(manifests []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+
+	dec := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		doc := &yaml.Node{}
+		err := dec.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse manifests: %w", err)
+		}
+
+		// Skip documents with only a comment, such as those helm
+		// renders for a template which is turned off
+		if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// walkContainerImages calls fn with the image of each container found
+// at any depth within node.
+func walkContainerImages(node *yaml.Node, fn func(image *yaml.Node)) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if containerKeys[key.Value] && value.Kind == yaml.SequenceNode {
+				for _, container := range value.Content {
+					if image := mappingValue(container, "image"); image != nil && image.Kind == yaml.ScalarNode {
+						fn(image)
+					}
+				}
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		walkContainerImages(child, fn)
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
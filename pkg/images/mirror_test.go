@@ -0,0 +1,175 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+)
+
+const testManifests = `---
+# Source: openfaas/templates/gateway-dep.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: gateway
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: wait
+          image: busybox:1.36
+      containers:
+        - name: gateway
+          image: ghcr.io/openfaas/gateway:0.27.0
+        - name: provider
+          image: ghcr.io/openfaas/faas-netes:0.18.0
+---
+# Source: openfaas/templates/ingress.yaml
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: ghcr.io/openfaas/gateway:0.27.0
+`
+
+func Test_MirrorRef(t *testing.T) {
+	cases := []struct {
+		image string
+		want  string
+	}{
+		{"ghcr.io/openfaas/gateway:0.27.0", "registry.local:5000/openfaas/gateway:0.27.0"},
+		{"nginx", "registry.local:5000/library/nginx:latest"},
+		{"quay.io/jetstack/cert-manager-controller@sha256:" + strings.Repeat("a", 64),
+			"registry.local:5000/jetstack/cert-manager-controller@sha256:" + strings.Repeat("a", 64)},
+		{"registry.local:5000/openfaas/gateway:0.27.0", "registry.local:5000/openfaas/gateway:0.27.0"},
+	}
+
+	for _, c := range cases {
+		got, err := MirrorRef(c.image, "registry.local:5000/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("%s: want %s, got %s", c.image, c.want, got)
+		}
+	}
+}
+
+func Test_ValidateMirror(t *testing.T) {
+	if err := ValidateMirror("harbor.example.com/mirror"); err != nil {
+		t.Errorf("want valid mirror, got %s", err)
+	}
+	if err := ValidateMirror("https://harbor.example.com"); err == nil {
+		t.Errorf("want error for a URL")
+	}
+}
+
+func Test_ManifestImages(t *testing.T) {
+	got, err := ManifestImages([]byte(testManifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"busybox:1.36", "ghcr.io/openfaas/faas-netes:0.18.0", "ghcr.io/openfaas/gateway:0.27.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func Test_RewriteImages(t *testing.T) {
+	out, err := RewriteImages([]byte(testManifests), map[string]string{
+		"ghcr.io/openfaas/gateway:0.27.0": "registry.local/openfaas/gateway:0.27.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ManifestImages(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"busybox:1.36", "ghcr.io/openfaas/faas-netes:0.18.0", "registry.local/openfaas/gateway:0.27.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if !strings.Contains(string(out), "# Source: openfaas/templates/gateway-dep.yaml") {
+		t.Errorf("want comments kept, got:\n%s", out)
+	}
+}
+
+func Test_Mirror(t *testing.T) {
+	upstream := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer upstream.Close()
+	mirror := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer mirror.Close()
+
+	upstreamHost := strings.TrimPrefix(upstream.URL, "http://")
+	mirrorHost := strings.TrimPrefix(mirror.URL, "http://")
+
+	img, err := mutate.Config(empty.Image, v1.Config{Labels: map[string]string{"app": "gateway"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := upstreamHost + "/openfaas/gateway:0.27.0"
+	if err := crane.Push(img, image); err != nil {
+		t.Fatal(err)
+	}
+
+	progress := &bytes.Buffer{}
+	mirrored, err := Mirror(context.Background(), []string{image}, mirrorHost+"/mirror", true, progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := mirrorHost + "/mirror/openfaas/gateway:0.27.0"
+	if mirrored[image] != want {
+		t.Fatalf("want %s mirrored to %s, got %v", image, want, mirrored)
+	}
+
+	wantDigest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := crane.Digest(want)
+	if err != nil {
+		t.Fatalf("want image in mirror: %s", err)
+	}
+	if got != wantDigest.String() {
+		t.Errorf("want digest %s, got %s", wantDigest, got)
+	}
+
+	if !strings.Contains(progress.String(), "Mirroring "+image) {
+		t.Errorf("want progress, got %q", progress.String())
+	}
+}
+
+func Test_Mirror_Missing(t *testing.T) {
+	upstream := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer upstream.Close()
+
+	host := strings.TrimPrefix(upstream.URL, "http://")
+	_, err := Mirror(context.Background(), []string{host + "/openfaas/missing:0.1.0"}, host+"/mirror", true, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "unable to mirror") {
+		t.Errorf("want unable to mirror error, got %v", err)
+	}
+}
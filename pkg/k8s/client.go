@@ -0,0 +1,358 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexellis/arkade/pkg/types"
+	execute "github.com/alexellis/go-execute/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Client is the part of the Kubernetes API used by arkade's apps.
+// Every method is idempotent, so an install can be run again.
+//
+// KubernetesClient is the implementation which talks to a cluster, with
+// client-go, so kubectl isn't needed on the PATH.
+type Client interface {
+	// CreateNamespace creates a namespace, unless it already exists.
+	CreateNamespace(ctx context.Context, name string) error
+
+	// ApplySecret creates a secret, or updates it when it exists. Each
+	// value is read from its source first, see ResolveSecret. A Generated
+	// secret which exists is left as it is.
+	ApplySecret(ctx context.Context, secret types.K8sSecret) error
+
+	// Apply creates or updates the objects in a manifest with
+	// server-side apply.
+	Apply(ctx context.Context, manifest []byte) error
+
+	// Capabilities returns the API versions served by the cluster.
+	Capabilities(ctx context.Context) (Capabilities, error)
+
+	// NodeArchitecture returns the architecture of the first node.
+	NodeArchitecture(ctx context.Context) (string, error)
+
+	// WaitForRollout waits for a resource such as "deploy/gateway" to
+	// become ready, or for a Job such as "job/migrate" to complete.
+	WaitForRollout(ctx context.Context, namespace, resource string, timeout time.Duration) error
+
+	// Workloads returns the Deployments, StatefulSets, DaemonSets and
+	// Jobs in a namespace, i.e. "deployment/gateway".
+	Workloads(ctx context.Context, namespace string) ([]string, error)
+
+	// ServerVersion returns the Kubernetes version, i.e. "v1.29.1+k3s1".
+	ServerVersion(ctx context.Context) (string, error)
+
+	// Exists is true when a resource such as "ingressclass/nginx" or
+	// "crd/certificates.cert-manager.io" exists. A resource without a
+	// name, such as "ingressclass", exists when there is at least one.
+	Exists(ctx context.Context, namespace, resource string) (bool, error)
+
+	// Get returns the fields of a resource selected by a JSONPath
+	// expression, i.e. "{.data.password}" for "secret/basic-auth".
+	Get(ctx context.Context, namespace, resource, jsonPath string) (string, error)
+}
+
+// FieldManager identifies arkade as the owner of fields it applies.
+const FieldManager = "arkade"
+
+var (
+	client   Client = &KubernetesClient{}
+	clientMu sync.RWMutex
+)
+
+// SetClient replaces the Client used by this package, passing nil
+// restores a KubernetesClient.
+func SetClient(c Client) {
+	clientMu.Lock()
+	defer clientMu.Unlock()
+
+	if c == nil {
+		c = &KubernetesClient{}
+	}
+	client = c
+}
+
+// GetClient returns the current Client.
+func GetClient() Client {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+
+	return client
+}
+
+// KubernetesClient implements Client with client-go. Each call is
+// described as the equivalent kubectl command, and run through the
+// current Executor so that dry-runs, exports and install records apply
+// to it. The TaskExecutor calls the Kubernetes API in place of kubectl.
+//
+// Clientset and Dynamic may be set, i.e. to fakes for testing, otherwise
+// they're created from the kubeconfig and context selected for this
+// invocation.
+type KubernetesClient struct {
+	Clientset kubernetes.Interface
+	Dynamic   dynamic.Interface
+
+	mu          sync.Mutex
+	connections map[string]*connection
+}
+
+// run describes a call to the API as "kubectl args", and returns its
+// output, which fn writes in the same format as kubectl.
+func (c *KubernetesClient) run(ctx context.Context, stdin []byte, fn func(ctx context.Context, conn *connection) (string, error), args ...string) (execute.ExecResult, error) {
+	task := execute.ExecTask{
+		Command:     "kubectl",
+		Args:        args,
+		StreamStdio: false,
+	}
+	if stdin != nil {
+		task.Stdin = bytes.NewReader(stdin)
+	}
+
+	res, err := runInProcess(ctx, task, func(ctx context.Context) (execute.ExecResult, error) {
+		conn, err := c.connect()
+		if err != nil {
+			return execute.ExecResult{}, err
+		}
+
+		out, err := fn(ctx, conn)
+		return execute.ExecResult{Stdout: out}, err
+	})
+	if err != nil {
+		return res, err
+	}
+	if res.ExitCode != 0 {
+		return res, fmt.Errorf("kubectl %s: exit code %d, stderr: %s", verb(args), res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+	return res, nil
+}
+
+func (c *KubernetesClient) CreateNamespace(ctx context.Context, name string) error {
+	_, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if _, err := conn.clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{FieldManager: FieldManager}); err != nil {
+			return "", err
+		}
+		return "namespace/" + name + " created\n", nil
+	}, "create", "namespace", name)
+
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *KubernetesClient) ApplySecret(ctx context.Context, secret types.K8sSecret) error {
+	secret, err := ResolveSecret(ctx, secret, func(key string) (string, bool) {
+		return c.secretValue(ctx, secret.Namespace, secret.Name, key)
+	})
+	if err != nil {
+		return err
+	}
+
+	secretData, err := flattenSecretData(secret.SecretData)
+	if err != nil {
+		return err
+	}
+
+	obj, err := secretObject(secret)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"-n", secret.Namespace, "create", "secret", secret.Type, secret.Name}, secretData...)
+
+	_, err = c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		if _, err := conn.clientset.CoreV1().Secrets(secret.Namespace).Create(ctx, obj, metav1.CreateOptions{FieldManager: FieldManager}); err != nil {
+			return "", err
+		}
+		return "secret/" + secret.Name + " created\n", nil
+	}, args...)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	if secret.Generated {
+		return nil
+	}
+
+	manifest, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.run(ctx, manifest, func(ctx context.Context, conn *connection) (string, error) {
+		if _, err := conn.clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, obj, metav1.UpdateOptions{FieldManager: FieldManager}); err != nil {
+			return "", err
+		}
+		return "secret/" + secret.Name + " replaced\n", nil
+	}, "-n", secret.Namespace, "replace", "-f", "-")
+	return err
+}
+
+func (c *KubernetesClient) Apply(ctx context.Context, manifest []byte) error {
+	_, err := c.run(ctx, manifest, func(ctx context.Context, conn *connection) (string, error) {
+		return conn.apply(ctx, manifest)
+	}, "apply", "--server-side", "--force-conflicts", "--field-manager="+FieldManager, "-f", "-")
+	return err
+}
+
+func (c *KubernetesClient) Capabilities(ctx context.Context) (Capabilities, error) {
+	res, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		groups, err := conn.clientset.Discovery().ServerGroups()
+		if err != nil {
+			return "", err
+		}
+
+		var apis []string
+		for _, group := range groups.Groups {
+			for _, version := range group.Versions {
+				apis = append(apis, version.GroupVersion)
+			}
+		}
+		sort.Strings(apis)
+		return strings.Join(apis, "\n") + "\n", nil
+	}, "api-versions")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get cluster capabilities: %w", err)
+	}
+
+	return parseCapabilities(res.Stdout), nil
+}
+
+func (c *KubernetesClient) NodeArchitecture(ctx context.Context) (string, error) {
+	res, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		nodes, err := conn.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil || len(nodes.Items) == 0 {
+			return "", err
+		}
+		return nodes.Items[0].Status.NodeInfo.Architecture, nil
+	}, "get", "nodes", "--output", `jsonpath={range $.items[0]}{.status.nodeInfo.architecture}`)
+	if err != nil {
+		return "", fmt.Errorf("unable to get node architecture: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+func (c *KubernetesClient) WaitForRollout(ctx context.Context, namespace, resource string, timeout time.Duration) error {
+	wait := func(ctx context.Context, conn *connection) (string, error) {
+		return conn.waitForRollout(ctx, namespace, resource, timeout)
+	}
+
+	// "kubectl rollout status" doesn't support Jobs
+	if strings.HasPrefix(resource, "job/") || strings.HasPrefix(resource, "job.batch/") {
+		_, err := c.run(ctx, nil, wait, "wait", "--for=condition=complete", resource, "--namespace", namespace, "--timeout", timeout.String())
+		return err
+	}
+
+	_, err := c.run(ctx, nil, wait, "rollout", "status", resource, "--namespace", namespace, "--timeout", timeout.String())
+	return err
+}
+
+func (c *KubernetesClient) Workloads(ctx context.Context, namespace string) ([]string, error) {
+	res, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		return conn.workloads(ctx, namespace)
+	}, "get", "deployments,statefulsets,daemonsets,jobs", "--namespace", namespace, "--output=name")
+	if err != nil {
+		return nil, err
+	}
+
+	// i.e. "deployment.apps/gateway" becomes "deployment/gateway"
+	var workloads []string
+	for _, line := range strings.Fields(res.Stdout) {
+		kind, name, ok := strings.Cut(line, "/")
+		if !ok {
+			continue
+		}
+		kind, _, _ = strings.Cut(kind, ".")
+		workloads = append(workloads, kind+"/"+name)
+	}
+	return workloads, nil
+}
+
+func (c *KubernetesClient) ServerVersion(ctx context.Context) (string, error) {
+	res, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		info, err := conn.clientset.Discovery().ServerVersion()
+		if err != nil {
+			return "", err
+		}
+
+		out, err := json.Marshal(map[string]interface{}{"serverVersion": info})
+		return string(out), err
+	}, "version", "--output=json")
+	if err != nil {
+		return "", fmt.Errorf("unable to get the Kubernetes version: %w", err)
+	}
+
+	var version struct {
+		ServerVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &version); err != nil {
+		return "", fmt.Errorf("unable to parse kubectl version: %w", err)
+	}
+
+	return version.ServerVersion.GitVersion, nil
+}
+
+func (c *KubernetesClient) Exists(ctx context.Context, namespace, resource string) (bool, error) {
+	args := []string{"get", resource, "--ignore-not-found", "--output=name"}
+	if len(namespace) > 0 {
+		args = append(args, "--namespace", namespace)
+	}
+
+	res, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		return conn.names(ctx, namespace, resource)
+	}, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return len(strings.TrimSpace(res.Stdout)) > 0, nil
+}
+
+func (c *KubernetesClient) Get(ctx context.Context, namespace, resource, jsonPath string) (string, error) {
+	args := []string{"get", resource, "--output=jsonpath=" + jsonPath}
+	if len(namespace) > 0 {
+		args = append(args, "--namespace", namespace)
+	}
+
+	res, err := c.run(ctx, nil, func(ctx context.Context, conn *connection) (string, error) {
+		return conn.get(ctx, namespace, resource, jsonPath)
+	}, args...)
+	if err != nil {
+		return "", err
+	}
+	return res.Stdout, nil
+}
+
+// parseCapabilities reads the output of "kubectl api-versions", with
+// one group/version per line.
+func parseCapabilities(out string) Capabilities {
+	caps := Capabilities{}
+
+	lines := bufio.NewScanner(strings.NewReader(out))
+	for lines.Scan() {
+		if api := strings.TrimSpace(lines.Text()); len(api) > 0 {
+			caps[api] = true
+		}
+	}
+	return caps
+}
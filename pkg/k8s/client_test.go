@@ -0,0 +1,340 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexellis/arkade/pkg/types"
+	execute "github.com/alexellis/go-execute/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// scriptedExecutor returns results in order, and the last result for
+// any further tasks. Without results, each task is run by the
+// TaskExecutor.
+type scriptedExecutor struct {
+	tasks   []execute.ExecTask
+	stdin   []string
+	results []execute.ExecResult
+}
+
+func (s *scriptedExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	s.tasks = append(s.tasks, task)
+
+	stdin := ""
+	if task.Stdin != nil {
+		data, _ := io.ReadAll(task.Stdin)
+		stdin = string(data)
+		task.Stdin = strings.NewReader(stdin)
+	}
+	s.stdin = append(s.stdin, stdin)
+
+	if len(s.results) == 0 {
+		return TaskExecutor{}.Execute(ctx, task)
+	}
+
+	i := len(s.tasks) - 1
+	if i >= len(s.results) {
+		i = len(s.results) - 1
+	}
+	return s.results[i], nil
+}
+
+func (s *scriptedExecutor) commands() []string {
+	var out []string
+	for _, task := range s.tasks {
+		out = append(out, strings.Join(task.Args, " "))
+	}
+	return out
+}
+
+// newTestClient returns a KubernetesClient for fake clients holding
+// objects, which serve the resources used by arkade's apps.
+func newTestClient(objects ...runtime.Object) (*KubernetesClient, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "namespaces", SingularName: "namespace", Kind: "Namespace", ShortNames: []string{"ns"}},
+			{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}},
+			{Name: "configmaps", SingularName: "configmap", Namespaced: true, Kind: "ConfigMap", ShortNames: []string{"cm"}},
+			{Name: "secrets", SingularName: "secret", Namespaced: true, Kind: "Secret"},
+			{Name: "services", SingularName: "service", Namespaced: true, Kind: "Service", ShortNames: []string{"svc"}},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}},
+		}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "ingressclasses", SingularName: "ingressclass", Kind: "IngressClass"},
+		}},
+	}
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.29.1+k3s1"}
+
+	var copies []runtime.Object
+	for _, obj := range objects {
+		copies = append(copies, obj.DeepCopyObject())
+	}
+	dynamic := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, copies...)
+
+	return &KubernetesClient{Clientset: clientset, Dynamic: dynamic}, clientset, dynamic
+}
+
+func Test_KubernetesClient_ApplySecret_UpdatesExisting(t *testing.T) {
+	c, clientset, _ := newTestClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic-auth", Namespace: "openfaas"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	})
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	secret := types.NewGenericSecret("basic-auth", "openfaas", []types.SecretsData{
+		{Type: types.StringLiteralSecret, Key: "password", Value: "secret"},
+	})
+	if err := c.ApplySecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"-n openfaas create secret generic basic-auth --from-literal=password=secret",
+		"-n openfaas replace -f -",
+	}
+	if got := recorder.commands(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want commands:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if !strings.Contains(recorder.stdin[1], "kind: Secret") {
+		t.Errorf("want the secret passed to replace, got %q", recorder.stdin[1])
+	}
+
+	updated, err := clientset.CoreV1().Secrets("openfaas").Get(context.Background(), "basic-auth", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(updated.Data["password"]); got != "secret" {
+		t.Errorf("want the password updated, got %q", got)
+	}
+}
+
+func Test_KubernetesClient_ApplySecret_KeepsGeneratedSecret(t *testing.T) {
+	c, clientset, _ := newTestClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard-jwt", Namespace: "openfaas"},
+		Data:       map[string][]byte{"key": []byte("old-key")},
+	})
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	secret := types.NewGeneratedSecret("dashboard-jwt", "openfaas", []types.SecretsData{
+		{Type: types.StringLiteralSecret, Key: "key", Value: "new-key"},
+	})
+	if err := c.ApplySecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.tasks) != 1 {
+		t.Errorf("want the existing secret kept, got commands:\n%s", strings.Join(recorder.commands(), "\n"))
+	}
+
+	existing, err := clientset.CoreV1().Secrets("openfaas").Get(context.Background(), "dashboard-jwt", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(existing.Data["key"]); got != "old-key" {
+		t.Errorf("want the key kept, got %q", got)
+	}
+}
+
+func Test_KubernetesClient_ApplySecret_Error(t *testing.T) {
+	c, clientset, _ := newTestClient()
+	clientset.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "s", errors.New("denied"))
+	})
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	err := c.ApplySecret(context.Background(), types.NewGenericSecret("s", "default", nil))
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("want a Forbidden error, got %v", err)
+	}
+	if len(recorder.tasks) != 1 {
+		t.Errorf("want no update after a failed create, got %d commands", len(recorder.tasks))
+	}
+}
+
+func Test_KubernetesClient_CreateNamespace_Exists(t *testing.T) {
+	c, _, _ := newTestClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openfaas"}})
+
+	if err := c.CreateNamespace(context.Background(), "openfaas"); err != nil {
+		t.Errorf("want no error for an existing namespace, got %v", err)
+	}
+	if err := c.CreateNamespace(context.Background(), "openfaas-fn"); err != nil {
+		t.Errorf("want a new namespace created, got %v", err)
+	}
+}
+
+func Test_KubernetesClient_ApplyAndWait(t *testing.T) {
+	replicas := int32(1)
+	c, _, dynamic := newTestClient(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "openfaas", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	})
+
+	var applied []string
+	dynamic.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != apitypes.ApplyPatchType {
+			return false, nil, nil
+		}
+		applied = append(applied, patch.GetResource().Resource+" "+patch.GetNamespace()+"/"+patch.GetName())
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n---\n" +
+		"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: openfaas\n"
+	if err := c.Apply(context.Background(), []byte(manifest)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitForRollout(context.Background(), "openfaas", "deploy/gateway", 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"apply --server-side --force-conflicts --field-manager=arkade -f -",
+		"rollout status deploy/gateway --namespace openfaas --timeout 2m0s",
+	}
+	if got := recorder.commands(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want commands:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if recorder.stdin[0] != manifest {
+		t.Errorf("want manifest on stdin, got %q", recorder.stdin[0])
+	}
+
+	wantApplied := []string{"configmaps default/config", "namespaces /openfaas"}
+	if strings.Join(applied, "\n") != strings.Join(wantApplied, "\n") {
+		t.Errorf("want applied:\n%s\ngot:\n%s", strings.Join(wantApplied, "\n"), strings.Join(applied, "\n"))
+	}
+}
+
+func Test_KubernetesClient_DryRun(t *testing.T) {
+	c, clientset, _ := newTestClient(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: "arm64"}},
+	})
+
+	out := &bytes.Buffer{}
+	dryRun, err := NewDryRunExecutor(out, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetExecutor(dryRun)
+	defer SetExecutor(nil)
+
+	if arch, err := c.NodeArchitecture(context.Background()); err != nil || arch != "arm64" {
+		t.Errorf("want arm64 read from the cluster, got %s %v", arch, err)
+	}
+
+	if err := c.CreateNamespace(context.Background(), "openfaas"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "kubectl create namespace openfaas") {
+		t.Errorf("want the command printed, got %s", out.String())
+	}
+	if _, err := clientset.CoreV1().Namespaces().Get(context.Background(), "openfaas", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("want no namespace created by a dry-run, got %v", err)
+	}
+}
+
+func Test_parseCapabilities(t *testing.T) {
+	caps := parseCapabilities("apps/v1\nnetworking.k8s.io/v1\n\nv1\n")
+
+	for _, api := range []string{"apps/v1", "networking.k8s.io/v1", "v1"} {
+		if !caps[api] {
+			t.Errorf("want %s in capabilities", api)
+		}
+	}
+	if len(caps) != 3 {
+		t.Errorf("want 3 capabilities, got %d: %v", len(caps), caps)
+	}
+}
+
+func Test_FakeClient(t *testing.T) {
+	fake := NewFakeClient()
+	fake.Arch = "arm64"
+	SetClient(fake)
+	defer SetClient(nil)
+
+	if arch, err := GetNodeArchitecture(); err != nil || arch != "arm64" {
+		t.Errorf("want arm64, got %s %v", arch, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := CreateNamespace("openfaas"); err != nil {
+			t.Fatal(err)
+		}
+		if err := CreateSecret(types.NewGenericSecret("basic-auth", "openfaas", nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(fake.Namespaces) != 1 || len(fake.Secrets) != 1 {
+		t.Errorf("want one namespace and secret, got %v and %v", fake.Namespaces, fake.Secrets)
+	}
+}
+
+func Test_KubernetesClient_ServerVersionAndExists(t *testing.T) {
+	c, _, _ := newTestClient(&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}})
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	version, err := c.ServerVersion(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "v1.29.1+k3s1" {
+		t.Errorf("want server version v1.29.1+k3s1, got %s", version)
+	}
+
+	if found, err := c.Exists(context.Background(), "", "ingressclass/nginx"); err != nil || !found {
+		t.Errorf("want ingressclass/nginx found, got %v %v", found, err)
+	}
+	if found, err := c.Exists(context.Background(), "", "ingressclass"); err != nil || !found {
+		t.Errorf("want an ingressclass found, got %v %v", found, err)
+	}
+	if found, err := c.Exists(context.Background(), "openfaas", "svc/gateway"); err != nil || found {
+		t.Errorf("want svc/gateway not found, got %v %v", found, err)
+	}
+
+	if got := recorder.commands()[3]; got != "get svc/gateway --ignore-not-found --output=name --namespace openfaas" {
+		t.Errorf("unexpected command: %s", got)
+	}
+}
@@ -0,0 +1,430 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alexellis/arkade/pkg/config"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// rolloutInterval is how often WaitForRollout checks a resource.
+var rolloutInterval = 2 * time.Second
+
+// connection holds the clients for one kubeconfig and context.
+type connection struct {
+	clientset kubernetes.Interface
+	dynamic   dynamic.Interface
+
+	// mapper finds the resource for a kind, and expander also accepts
+	// short names such as "deploy" and "crd".
+	mapper   *restmapper.DeferredDiscoveryRESTMapper
+	expander meta.RESTMapper
+
+	// namespace is used for namespaced objects which don't set one.
+	namespace string
+}
+
+// connect returns the clients for the kubeconfig and context selected
+// for this invocation, see config.SetKubeconfig and
+// config.SetKubeContext.
+func (c *KubernetesClient) connect() (*connection, error) {
+	key := config.Kubeconfig() + "\x00" + config.KubeContext()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, ok := c.connections[key]; ok {
+		return conn, nil
+	}
+
+	conn := &connection{
+		clientset: c.Clientset,
+		dynamic:   c.Dynamic,
+		namespace: metav1.NamespaceDefault,
+	}
+
+	if conn.clientset == nil || conn.dynamic == nil {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		if kubeconfig := config.Kubeconfig(); len(kubeconfig) > 0 {
+			rules.Precedence = filepath.SplitList(kubeconfig)
+		}
+		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+			&clientcmd.ConfigOverrides{CurrentContext: config.KubeContext()})
+
+		restConfig, err := clientConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load kubeconfig: %w", err)
+		}
+		if namespace, _, err := clientConfig.Namespace(); err == nil && len(namespace) > 0 {
+			conn.namespace = namespace
+		}
+
+		if conn.clientset == nil {
+			if conn.clientset, err = kubernetes.NewForConfig(restConfig); err != nil {
+				return nil, err
+			}
+		}
+		if conn.dynamic == nil {
+			if conn.dynamic, err = dynamic.NewForConfig(restConfig); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	discovery := memory.NewMemCacheClient(conn.clientset.Discovery())
+	conn.mapper = restmapper.NewDeferredDiscoveryRESTMapper(discovery)
+	conn.expander = restmapper.NewShortcutExpander(conn.mapper, discovery, nil)
+
+	if c.connections == nil {
+		c.connections = map[string]*connection{}
+	}
+	c.connections[key] = conn
+
+	return conn, nil
+}
+
+// mapping finds the resource for a kind. The cached discovery is
+// refreshed once when the kind isn't found, i.e. for a CRD applied
+// since.
+func (conn *connection) mapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := conn.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		conn.mapper.Reset()
+		mapping, err = conn.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	return mapping, err
+}
+
+// resource returns the client for a resource given as "kubectl get"
+// takes it, i.e. "ingressclass/nginx" or "crd", and the name from it.
+func (conn *connection) resource(namespace, resource string) (dynamic.ResourceInterface, string, error) {
+	arg, name, _ := strings.Cut(resource, "/")
+
+	find := func() (schema.GroupVersionResource, error) {
+		gvr, gr := schema.ParseResourceArg(arg)
+		if gvr != nil {
+			if found, err := conn.expander.ResourceFor(*gvr); err == nil {
+				return found, nil
+			}
+		}
+		return conn.expander.ResourceFor(gr.WithVersion(""))
+	}
+
+	gvr, err := find()
+	if meta.IsNoMatchError(err) {
+		conn.mapper.Reset()
+		gvr, err = find()
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	gvk, err := conn.expander.KindFor(gvr)
+	if err != nil {
+		return nil, "", err
+	}
+	mapping, err := conn.mapping(gvk)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return conn.dynamic.Resource(mapping.Resource), name, nil
+	}
+	if len(namespace) == 0 {
+		namespace = conn.namespace
+	}
+	return conn.dynamic.Resource(mapping.Resource).Namespace(namespace), name, nil
+}
+
+// apply applies each object in a manifest with server-side apply, and
+// returns the output of "kubectl apply --server-side".
+func (conn *connection) apply(ctx context.Context, manifest []byte) (string, error) {
+	var objects []*unstructured.Unstructured
+
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		doc := map[string]interface{}{}
+		if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("unable to parse manifest: %w", err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: doc}
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, item.(*unstructured.Unstructured))
+			return nil
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	var out strings.Builder
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		mapping, err := conn.mapping(gvk)
+		if err != nil {
+			return out.String(), err
+		}
+
+		var client dynamic.ResourceInterface = conn.dynamic.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if len(obj.GetNamespace()) == 0 {
+				obj.SetNamespace(conn.namespace)
+			}
+			client = conn.dynamic.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+		}
+
+		if _, err := client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: FieldManager, Force: true}); err != nil {
+			return out.String(), err
+		}
+		fmt.Fprintf(&out, "%s/%s serverside-applied\n", kindName(gvk.GroupKind()), obj.GetName())
+	}
+
+	return out.String(), nil
+}
+
+// names returns the output of "kubectl get --output=name" for a
+// resource, which is empty when it's not found.
+func (conn *connection) names(ctx context.Context, namespace, resource string) (string, error) {
+	client, name, err := conn.resource(namespace, resource)
+	if err != nil {
+		return "", err
+	}
+
+	var items []unstructured.Unstructured
+	if len(name) > 0 {
+		obj, err := client.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		} else if err != nil {
+			return "", err
+		}
+		items = append(items, *obj)
+	} else {
+		list, err := client.List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return "", err
+		}
+		items = list.Items
+	}
+
+	var out strings.Builder
+	for _, item := range items {
+		fmt.Fprintf(&out, "%s/%s\n", kindName(item.GroupVersionKind().GroupKind()), item.GetName())
+	}
+	return out.String(), nil
+}
+
+// get returns the fields of a resource selected by a JSONPath
+// expression, as "kubectl get --output=jsonpath" does.
+func (conn *connection) get(ctx context.Context, namespace, resource, expr string) (string, error) {
+	client, name, err := conn.resource(namespace, resource)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	path := jsonpath.New("get")
+	path.AllowMissingKeys(true)
+	if err := path.Parse(expr); err != nil {
+		return "", fmt.Errorf("unable to parse jsonpath %s: %w", expr, err)
+	}
+
+	var out bytes.Buffer
+	if err := path.Execute(&out, obj.Object); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// workloads returns the output of "kubectl get
+// deployments,statefulsets,daemonsets,jobs --output=name".
+func (conn *connection) workloads(ctx context.Context, namespace string) (string, error) {
+	var out strings.Builder
+
+	deployments, err := conn.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, d := range deployments.Items {
+		fmt.Fprintf(&out, "deployment.apps/%s\n", d.Name)
+	}
+
+	statefulSets, err := conn.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, s := range statefulSets.Items {
+		fmt.Fprintf(&out, "statefulset.apps/%s\n", s.Name)
+	}
+
+	daemonSets, err := conn.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, d := range daemonSets.Items {
+		fmt.Fprintf(&out, "daemonset.apps/%s\n", d.Name)
+	}
+
+	jobs, err := conn.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, j := range jobs.Items {
+		fmt.Fprintf(&out, "job.batch/%s\n", j.Name)
+	}
+
+	return out.String(), nil
+}
+
+// waitForRollout polls a Deployment, StatefulSet or DaemonSet until its
+// rollout is complete, or a Job until it completes.
+func (conn *connection) waitForRollout(ctx context.Context, namespace, resource string, timeout time.Duration) (string, error) {
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok || len(name) == 0 {
+		return "", fmt.Errorf("resource %s needs a name, i.e. deploy/gateway", resource)
+	}
+	kind, _, _ = strings.Cut(kind, ".")
+
+	apps := conn.clientset.AppsV1()
+
+	var done func(ctx context.Context) (bool, error)
+	switch kind {
+	case "deploy", "deployment", "deployments":
+		done = func(ctx context.Context) (bool, error) {
+			d, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+			return err == nil && deploymentReady(d), err
+		}
+	case "sts", "statefulset", "statefulsets":
+		done = func(ctx context.Context) (bool, error) {
+			s, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+			return err == nil && statefulSetReady(s), err
+		}
+	case "ds", "daemonset", "daemonsets":
+		done = func(ctx context.Context) (bool, error) {
+			d, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+			return err == nil && daemonSetReady(d), err
+		}
+	case "job", "jobs":
+		done = func(ctx context.Context) (bool, error) {
+			j, err := conn.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return jobComplete(j)
+		}
+	default:
+		return "", fmt.Errorf("unable to wait for %s, only Deployments, StatefulSets, DaemonSets and Jobs are supported", resource)
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, rolloutInterval, timeout, true, done); err != nil {
+		if wait.Interrupted(err) {
+			return "", fmt.Errorf("timed out waiting for %s in %s after %s", resource, namespace, timeout)
+		}
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %q successfully rolled out\n", kind, name), nil
+}
+
+// deploymentReady is true when every replica runs the latest
+// ReplicaSet and is available, as for "kubectl rollout status".
+func deploymentReady(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas >= replicas &&
+		d.Status.Replicas <= d.Status.UpdatedReplicas &&
+		d.Status.AvailableReplicas >= d.Status.UpdatedReplicas
+}
+
+// statefulSetReady is true when every replica is updated and ready.
+func statefulSetReady(s *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+
+	return s.Status.ObservedGeneration >= s.Generation &&
+		s.Status.ReadyReplicas >= replicas &&
+		s.Status.UpdatedReplicas >= replicas &&
+		s.Status.UpdateRevision == s.Status.CurrentRevision
+}
+
+// daemonSetReady is true when the updated pod is available on every
+// node it's scheduled to.
+func daemonSetReady(d *appsv1.DaemonSet) bool {
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedNumberScheduled >= d.Status.DesiredNumberScheduled &&
+		d.Status.NumberAvailable >= d.Status.DesiredNumberScheduled
+}
+
+// jobComplete is true when a Job has completed, and an error when it
+// has failed.
+func jobComplete(j *batchv1.Job) (bool, error) {
+	for _, condition := range j.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, fmt.Errorf("job %s failed: %s", j.Name, condition.Message)
+		}
+	}
+	return false, nil
+}
+
+// kindName formats a kind as kubectl does for "--output=name", i.e.
+// "deployment.apps".
+func kindName(gk schema.GroupKind) string {
+	kind := strings.ToLower(gk.Kind)
+	if len(gk.Group) == 0 {
+		return kind
+	}
+	return kind + "." + gk.Group
+}
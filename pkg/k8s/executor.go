@@ -0,0 +1,459 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/alexellis/arkade/pkg/config"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// Executor runs the kubectl, helm and other CLI commands used by
+// "arkade install".
+type Executor interface {
+	Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error)
+}
+
+// TaskExecutor runs each task on the local machine, in-process when its
+// command was given to RegisterCommand, or for the kubectl commands run
+// by the KubernetesClient.
+type TaskExecutor struct {
+}
+
+func (TaskExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	if p, ok := ctx.Value(inProcessKey{}).(inProcess); ok && slices.Equal(p.args, task.Args) {
+		return p.fn(ctx)
+	}
+
+	commandsMu.RLock()
+	fn, ok := commands[filepath.Base(task.Command)]
+	commandsMu.RUnlock()
+
+	if ok {
+		return fn(ctx, task)
+	}
+	return task.Execute(ctx)
+}
+
+// CommandFunc runs a task in-process, in place of its command.
+type CommandFunc func(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error)
+
+var (
+	commands   = map[string]CommandFunc{}
+	commandsMu sync.RWMutex
+)
+
+// RegisterCommand runs each task for the named command with fn, rather
+// than as a process. Other executors still see the task itself, so a
+// dry-run prints the same command whether or not it runs in-process.
+func RegisterCommand(name string, fn CommandFunc) {
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+
+	commands[name] = fn
+}
+
+var (
+	executor   Executor = TaskExecutor{}
+	executorMu sync.RWMutex
+)
+
+// SetExecutor replaces the Executor used by this package and by
+// pkg/helm, passing nil restores the TaskExecutor.
+func SetExecutor(e Executor) {
+	executorMu.Lock()
+	defer executorMu.Unlock()
+
+	if e == nil {
+		e = TaskExecutor{}
+	}
+	executor = e
+}
+
+// GetExecutor returns the current Executor.
+func GetExecutor() Executor {
+	executorMu.RLock()
+	defer executorMu.RUnlock()
+
+	return executor
+}
+
+// IsDryRun is true when commands are being rendered rather than run,
+// i.e. by a DryRunExecutor.
+func IsDryRun() bool {
+	e, ok := GetExecutor().(interface{ DryRun() bool })
+	return ok && e.DryRun()
+}
+
+// Run executes a task with the current Executor, against the kubeconfig
+// and context selected for this invocation, see config.SetKubeconfig and
+// config.SetKubeContext.
+func Run(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	return GetExecutor().Execute(ctx, withTarget(task))
+}
+
+// inProcessKey holds the inProcess call for a task in its context.
+type inProcessKey struct{}
+
+// inProcess calls the Kubernetes API in place of the kubectl command
+// given by args.
+type inProcess struct {
+	args []string
+	fn   func(ctx context.Context) (execute.ExecResult, error)
+}
+
+// runInProcess runs a kubectl task with the current Executor, which
+// sees the same command as Run. When the task reaches the TaskExecutor,
+// fn is called instead of kubectl.
+func runInProcess(ctx context.Context, task execute.ExecTask, fn func(ctx context.Context) (execute.ExecResult, error)) (execute.ExecResult, error) {
+	task = withTarget(task)
+	ctx = context.WithValue(ctx, inProcessKey{}, inProcess{args: task.Args, fn: fn})
+
+	return GetExecutor().Execute(ctx, task)
+}
+
+// contextFlags select a kubeconfig context for each command which talks
+// to the cluster. Every such CLI run by an app must be listed, or it
+// would install into the current context for each of --context.
+var contextFlags = map[string]string{
+	"kubectl":  "--context",
+	"helm":     "--kube-context",
+	"istioctl": "--context",
+	"linkerd":  "--context",
+	"linkerd2": "--context",
+}
+
+// withTarget passes the selected kubeconfig to a task in its
+// environment, and the selected context as a flag to each of
+// contextFlags.
+func withTarget(task execute.ExecTask) execute.ExecTask {
+	if kubeconfig := config.Kubeconfig(); len(kubeconfig) > 0 {
+		task.Env = append(slices.Clone(task.Env), "KUBECONFIG="+kubeconfig)
+	}
+
+	if name := config.KubeContext(); len(name) > 0 {
+		if flag, ok := contextFlags[filepath.Base(task.Command)]; ok {
+			task.Args = append([]string{flag + "=" + name}, task.Args...)
+		}
+	}
+
+	return task
+}
+
+// DryRunExecutor prints, or writes to Dir, each command which would
+// change the cluster instead of running it, along with any manifests
+// and values files it reads. Secret literals are redacted.
+//
+// Read-only commands such as "kubectl get" and local helm commands such
+// as "helm repo add" and "helm fetch" still run, so that the rendered
+// commands match what a real install would do.
+type DryRunExecutor struct {
+	Out io.Writer
+	Dir string
+
+	mu    sync.Mutex
+	steps int
+}
+
+// NewDryRunExecutor creates a DryRunExecutor, when dir is non-empty it
+// is created and each step is written there instead of to out.
+func NewDryRunExecutor(out io.Writer, dir string) (*DryRunExecutor, error) {
+	if len(dir) > 0 {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("unable to create output directory: %w", err)
+		}
+	}
+
+	return &DryRunExecutor{
+		Out: out,
+		Dir: dir,
+	}, nil
+}
+
+// DryRun is always true, see IsDryRun.
+func (d *DryRunExecutor) DryRun() bool {
+	return true
+}
+
+// Steps returns the number of commands recorded so far.
+func (d *DryRunExecutor) Steps() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.steps
+}
+
+func (d *DryRunExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	if IsReadOnly(task.Command, task.Args) {
+		return TaskExecutor{}.Execute(ctx, task)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.steps++
+
+	docs, err := attachments(task)
+	if err != nil {
+		return execute.ExecResult{}, err
+	}
+
+	command := formatCommand(task.Command, task.Args)
+
+	if len(d.Dir) == 0 {
+		fmt.Fprintf(d.Out, "# [dry-run] step %d\n%s\n", d.steps, command)
+		for _, doc := range docs {
+			fmt.Fprintf(d.Out, "---\n# Source: %s\n%s\n", doc.name, strings.TrimRight(doc.content, "\n"))
+		}
+		fmt.Fprintln(d.Out)
+
+		return execute.ExecResult{}, nil
+	}
+
+	prefix := fmt.Sprintf("%02d-%s", d.steps, stepName(task.Command, task.Args))
+	if err := os.WriteFile(filepath.Join(d.Dir, prefix+".sh"), []byte(command+"\n"), 0600); err != nil {
+		return execute.ExecResult{}, err
+	}
+	for i, doc := range docs {
+		name := fmt.Sprintf("%s-%d-%s", prefix, i+1, filepath.Base(doc.name))
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			name += ".yaml"
+		}
+		if err := os.WriteFile(filepath.Join(d.Dir, name), []byte(doc.content), 0600); err != nil {
+			return execute.ExecResult{}, err
+		}
+	}
+	fmt.Fprintf(d.Out, "[dry-run] wrote step %d to %s\n", d.steps, filepath.Join(d.Dir, prefix+".sh"))
+
+	return execute.ExecResult{}, nil
+}
+
+var readOnlyVerbs = map[string][]string{
+	"kubectl": {"api-resources", "api-versions", "auth", "cluster-info", "config", "describe", "explain", "get", "version"},
+	"helm":    {"dep", "dependency", "env", "fetch", "get", "history", "lint", "list", "pull", "repo", "search", "show", "status", "template", "version"},
+	"sops":    {"decrypt"},
+}
+
+// flagsWithValues take a separate value, which must be skipped when
+// looking for the verb.
+var flagsWithValues = map[string]bool{
+	"-n":             true,
+	"--namespace":    true,
+	"--kubeconfig":   true,
+	"--context":      true,
+	"--kube-context": true,
+}
+
+// IsReadOnly is true for commands which don't change the cluster, such
+// as "kubectl get", and helm commands which only change local state.
+func IsReadOnly(command string, args []string) bool {
+	verbs, ok := readOnlyVerbs[filepath.Base(command)]
+	if !ok {
+		return false
+	}
+
+	// i.e. "kubectl create secret --dry-run=client -o yaml"
+	for _, arg := range args {
+		if arg == "--dry-run=client" {
+			return true
+		}
+	}
+
+	v := verb(args)
+	for _, r := range verbs {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
+
+func verb(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if flagsWithValues[args[i]] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			return args[i]
+		}
+	}
+	return ""
+}
+
+func stepName(command string, args []string) string {
+	name := filepath.Base(command)
+	if v := verb(args); len(v) > 0 {
+		name += "-" + v
+	}
+	return name
+}
+
+type document struct {
+	name    string
+	content string
+}
+
+// attachments reads the manifests and values files a task would send
+// to the cluster. Values files within the chart, i.e. under task.Cwd,
+// are the chart's defaults and are not included.
+func attachments(task execute.ExecTask) ([]document, error) {
+	var docs []document
+
+	// A password given on stdin is never shown
+	if task.Stdin != nil && !slices.Contains(task.Args, "--password-stdin") {
+		data, err := io.ReadAll(task.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, document{name: "stdin", content: redactManifest(string(data))})
+	}
+
+	for i := 0; i < len(task.Args)-1; i++ {
+		switch task.Args[i] {
+		case "-f", "--filename", "--values":
+		default:
+			continue
+		}
+
+		file := task.Args[i+1]
+		if strings.Contains(file, "://") {
+			continue
+		}
+		if len(task.Cwd) > 0 && strings.HasPrefix(file, task.Cwd+string(os.PathSeparator)) {
+			continue
+		}
+
+		paths := []string{file}
+		if info, err := os.Stat(file); err != nil {
+			continue
+		} else if info.IsDir() {
+			paths = nil
+			filepath.WalkDir(file, func(p string, e os.DirEntry, err error) error {
+				if err == nil && !e.IsDir() {
+					paths = append(paths, p)
+				}
+				return nil
+			})
+		}
+
+		for _, p := range paths {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, document{name: p, content: redactManifest(string(data))})
+		}
+	}
+
+	return docs, nil
+}
+
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential)`)
+
+var secretKind = regexp.MustCompile(`(?m)^kind:\s*Secret\s*$`)
+
+// redactManifest replaces the values under data and stringData in any
+// Secret within a multi-document manifest.
+func redactManifest(manifest string) string {
+	docs := strings.Split(manifest, "\n---")
+	for i, doc := range docs {
+		if !secretKind.MatchString(doc) {
+			continue
+		}
+
+		lines := strings.Split(doc, "\n")
+		blockIndent := -1
+		for j, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+
+			if blockIndent >= 0 {
+				if len(trimmed) > 0 && indent <= blockIndent {
+					blockIndent = -1
+				} else if k, _, ok := strings.Cut(line, ":"); ok {
+					lines[j] = k + ": REDACTED"
+					continue
+				}
+			}
+
+			if trimmed == "data:" || trimmed == "stringData:" {
+				blockIndent = indent
+			}
+		}
+		docs[i] = strings.Join(lines, "\n")
+	}
+	return strings.Join(docs, "\n---")
+}
+
+// formatCommand renders a command line which can be pasted into a
+// shell, with secret literals and sensitive --set values redacted.
+func formatCommand(command string, args []string) string {
+	parts := []string{filepath.Base(command)}
+	for _, arg := range RedactArgs(args) {
+		parts = append(parts, shellQuote(arg))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// RedactArgs returns a copy of a command's arguments with the values of
+// --from-literal, and of --set and --set-string when their key looks
+// like a secret, redacted.
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case strings.HasPrefix(arg, "--from-literal="):
+			kv := strings.TrimPrefix(arg, "--from-literal=")
+			if k, _, ok := strings.Cut(kv, "="); ok {
+				arg = "--from-literal=" + k + "=" + Redacted
+			}
+		case (arg == "--set" || arg == "--set-string") && i+1 < len(args):
+			redacted[i] = arg
+			i++
+			arg = RedactSet(args[i])
+		case strings.HasPrefix(arg, "--set="), strings.HasPrefix(arg, "--set-string="):
+			flag, kv, _ := strings.Cut(arg, "=")
+			arg = flag + "=" + RedactSet(kv)
+		}
+
+		redacted[i] = arg
+	}
+
+	return redacted
+}
+
+// Redacted replaces the value of a secret in rendered commands and
+// install records.
+const Redacted = "REDACTED"
+
+// RedactSet redacts the value of a helm --set "key=value" when its key
+// looks like a secret, i.e. "basicAuth.password".
+func RedactSet(kv string) string {
+	k, _, ok := strings.Cut(kv, "=")
+	if ok && sensitiveKey.MatchString(k) {
+		return k + "=" + Redacted
+	}
+	return kv
+}
+
+func shellQuote(s string) string {
+	if len(s) > 0 && !strings.ContainsAny(s, " \t\n'\"$`\\|&;<>(){}*?[]!#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
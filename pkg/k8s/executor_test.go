@@ -0,0 +1,229 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/types"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+func useDryRun(t *testing.T, dir string) (*DryRunExecutor, *bytes.Buffer) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	d, err := NewDryRunExecutor(out, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetExecutor(d)
+	t.Cleanup(func() { SetExecutor(nil) })
+
+	return d, out
+}
+
+func Test_DryRun_CreateSecretIsRedacted(t *testing.T) {
+	d, out := useDryRun(t, "")
+
+	secret := types.NewGenericSecret("basic-auth", "openfaas", []types.SecretsData{
+		{Type: types.StringLiteralSecret, Key: "basic-auth-user", Value: "admin"},
+		{Type: types.StringLiteralSecret, Key: "basic-auth-password", Value: "hunter2"},
+	})
+
+	if err := CreateSecret(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	want := "kubectl -n openfaas create secret generic basic-auth --from-literal=basic-auth-user=REDACTED --from-literal=basic-auth-password=REDACTED"
+	if !strings.Contains(got, want) {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, got)
+	}
+	if strings.Contains(got, "hunter2") {
+		t.Fatalf("secret value was printed:\n%s", got)
+	}
+	if d.Steps() != 1 {
+		t.Fatalf("want 1 step, got %d", d.Steps())
+	}
+}
+
+func Test_RedactArgs(t *testing.T) {
+	args := []string{"openfaas", "--set", "basicAuth.password=hunter2", "--set=gateway.replicas=2",
+		"--set-string=registry.token=abc", "--set-string", "image.tag=1.0", "--from-literal=user=admin"}
+
+	want := "openfaas --set basicAuth.password=REDACTED --set=gateway.replicas=2 " +
+		"--set-string=registry.token=REDACTED --set-string image.tag=1.0 --from-literal=user=REDACTED"
+	if got := strings.Join(RedactArgs(args), " "); got != want {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, got)
+	}
+	if args[2] != "basicAuth.password=hunter2" {
+		t.Fatalf("want the arguments unchanged, got %v", args)
+	}
+}
+
+func Test_DryRun_ApplyIncludesManifest(t *testing.T) {
+	_, out := useDryRun(t, "")
+
+	manifest := `apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+stringData:
+  token: abc123
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  level: debug
+`
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Kubectl("apply", "-f", file); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"kubectl apply -f " + file, "# Source: " + file, "token: REDACTED", "level: debug"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "abc123") {
+		t.Fatalf("secret value was printed:\n%s", got)
+	}
+}
+
+func Test_DryRun_PasswordStdinIsNotPrinted(t *testing.T) {
+	_, out := useDryRun(t, "")
+
+	task := execute.ExecTask{
+		Command: "helm",
+		Args:    []string{"registry", "login", "registry.example.com", "--username", "ci", "--password-stdin"},
+		Stdin:   strings.NewReader("hunter2"),
+	}
+	if _, err := Run(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "--password-stdin") {
+		t.Errorf("want the command in:\n%s", got)
+	}
+	if strings.Contains(got, "hunter2") {
+		t.Fatalf("password was printed:\n%s", got)
+	}
+}
+
+func Test_DryRun_WritesOutputDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "plan")
+	useDryRun(t, dir)
+
+	res, err := Run(context.Background(), execute.ExecTask{
+		Command: "/home/user/.arkade/bin/helm",
+		Args:    []string{"upgrade", "--install", "openfaas", "openfaas/openfaas", "--set", "gateway.replicas=2", "--set", "basicAuthPassword=hunter2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ExitCode != 0 {
+		t.Fatalf("want exit code 0, got %d", res.ExitCode)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "01-helm-upgrade.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "helm upgrade --install openfaas openfaas/openfaas --set gateway.replicas=2 --set basicAuthPassword=REDACTED\n"
+	if string(data) != want {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, string(data))
+	}
+}
+
+func Test_IsReadOnly(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    bool
+	}{
+		{"kubectl", []string{"get", "nodes"}, true},
+		{"kubectl", []string{"-n", "openfaas", "get", "secret"}, true},
+		{"kubectl", []string{"api-versions"}, true},
+		{"kubectl", []string{"apply", "-f", "app.yaml"}, false},
+		{"kubectl", []string{"-n", "get", "create", "secret"}, false},
+		{"kubectl", []string{"create", "secret", "generic", "s", "--dry-run=client", "--output=yaml"}, true},
+		{"/usr/local/bin/helm", []string{"repo", "add", "openfaas", "https://openfaas.github.io/faas-netes/"}, true},
+		{"helm", []string{"fetch", "openfaas/openfaas"}, true},
+		{"helm", []string{"get", "values", "openfaas", "--namespace", "openfaas"}, true},
+		{"helm", []string{"upgrade", "--install", "openfaas"}, false},
+		{"istioctl", []string{"install"}, false},
+	}
+
+	for _, tc := range tests {
+		if got := IsReadOnly(tc.command, tc.args); got != tc.want {
+			t.Errorf("IsReadOnly(%s %v) want %v, got %v", tc.command, tc.args, tc.want, got)
+		}
+	}
+}
+
+func Test_Run_SelectsContext(t *testing.T) {
+	_, out := useDryRun(t, "")
+
+	config.SetKubeContext("edge-1")
+	defer config.SetKubeContext("")
+
+	if _, err := Run(context.Background(), execute.ExecTask{Command: "kubectl", Args: []string{"apply", "-f", "https://example.com/app.yaml"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(context.Background(), execute.ExecTask{Command: "helm", Args: []string{"upgrade", "--install", "app", "repo/app"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"kubectl --context=edge-1 apply", "helm --kube-context=edge-1 upgrade"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("want %q in:\n%s", want, out.String())
+		}
+	}
+
+	if got := CurrentContext(""); got != "edge-1" {
+		t.Errorf("want current context edge-1, got %s", got)
+	}
+}
+
+func Test_withTarget_ClusterCLIs(t *testing.T) {
+	config.SetKubeContext("edge-1")
+	defer config.SetKubeContext("")
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "kubectl", want: "--context=edge-1"},
+		{command: "/home/user/.arkade/bin/helm", want: "--kube-context=edge-1"},
+		{command: "/home/user/.arkade/bin/istioctl", want: "--context=edge-1"},
+		{command: "/home/user/.arkade/bin/linkerd2", want: "--context=edge-1"},
+		{command: "linkerd", want: "--context=edge-1"},
+	}
+	for _, tc := range tests {
+		task := withTarget(execute.ExecTask{Command: tc.command, Args: []string{"install"}})
+		if len(task.Args) != 2 || task.Args[0] != tc.want {
+			t.Errorf("%s: want %s before the args, got %v", tc.command, tc.want, task.Args)
+		}
+	}
+
+	task := withTarget(execute.ExecTask{Command: "docker", Args: []string{"run"}})
+	if len(task.Args) != 1 {
+		t.Errorf("docker: want no context flag, got %v", task.Args)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexellis/arkade/pkg/types"
+)
+
+// FakeClient is an in-memory Client for tests, which needs no cluster
+// or kubectl. Use SetClient to install it.
+type FakeClient struct {
+	Arch    string
+	Caps    Capabilities
+	Version string
+
+	// Resources which exist, as "kind/name" or "namespace/kind/name".
+	Resources map[string]bool
+
+	// Fields are returned by Get, keyed by the resource as for
+	// Resources, then the JSONPath, i.e. "openfaas/secret/basic-auth
+	// {.data.basic-auth-password}".
+	Fields map[string]string
+
+	// Err, when set, is returned by every method.
+	Err error
+
+	mu         sync.Mutex
+	Namespaces []string
+	Secrets    map[string]types.K8sSecret
+	Manifests  [][]byte
+	Rollouts   []string
+}
+
+// NewFakeClient returns a FakeClient for a cluster of amd64 nodes.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		Arch:      "amd64",
+		Caps:      Capabilities{"v1": true},
+		Version:   "v1.30.0",
+		Resources: map[string]bool{},
+		Fields:    map[string]string{},
+		Secrets:   map[string]types.K8sSecret{},
+	}
+}
+
+func (f *FakeClient) CreateNamespace(ctx context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+	for _, ns := range f.Namespaces {
+		if ns == name {
+			return nil
+		}
+	}
+	f.Namespaces = append(f.Namespaces, name)
+	return nil
+}
+
+func (f *FakeClient) ApplySecret(ctx context.Context, secret types.K8sSecret) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	key := secret.Namespace + "/" + secret.Name
+	if _, ok := f.Secrets[key]; ok && secret.Generated {
+		return nil
+	}
+
+	secret, err := ResolveSecret(ctx, secret, func(k string) (string, bool) {
+		for _, data := range f.Secrets[key].SecretData {
+			if data.Key == k {
+				return data.Value, true
+			}
+		}
+		return "", false
+	})
+	if err != nil {
+		return err
+	}
+
+	f.Secrets[key] = secret
+	return nil
+}
+
+func (f *FakeClient) Apply(ctx context.Context, manifest []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Manifests = append(f.Manifests, manifest)
+	return nil
+}
+
+func (f *FakeClient) Capabilities(ctx context.Context) (Capabilities, error) {
+	return f.Caps, f.Err
+}
+
+func (f *FakeClient) NodeArchitecture(ctx context.Context) (string, error) {
+	return f.Arch, f.Err
+}
+
+func (f *FakeClient) WaitForRollout(ctx context.Context, namespace, resource string, timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Rollouts = append(f.Rollouts, namespace+"/"+resource)
+	return nil
+}
+
+func (f *FakeClient) Workloads(ctx context.Context, namespace string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	var workloads []string
+	for r := range f.Resources {
+		for _, kind := range []string{"deployment", "statefulset", "daemonset", "job"} {
+			if name, ok := strings.CutPrefix(r, namespace+"/"+kind+"/"); ok {
+				workloads = append(workloads, kind+"/"+name)
+			}
+		}
+	}
+	sort.Strings(workloads)
+	return workloads, nil
+}
+
+func (f *FakeClient) ServerVersion(ctx context.Context) (string, error) {
+	return f.Version, f.Err
+}
+
+func (f *FakeClient) Exists(ctx context.Context, namespace, resource string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return false, f.Err
+	}
+
+	key := resource
+	if len(namespace) > 0 {
+		key = namespace + "/" + resource
+	}
+	if f.Resources[key] {
+		return true, nil
+	}
+
+	// A kind without a name matches any resource of that kind
+	if !strings.Contains(resource, "/") {
+		for r := range f.Resources {
+			if strings.HasPrefix(r, key+"/") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (f *FakeClient) Get(ctx context.Context, namespace, resource, jsonPath string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return "", f.Err
+	}
+
+	key := resource
+	if len(namespace) > 0 {
+		key = namespace + "/" + resource
+	}
+	return f.Fields[key+" "+jsonPath], nil
+}
@@ -4,11 +4,9 @@
 package k8s
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/alexellis/arkade/pkg/types"
 
@@ -18,33 +16,34 @@ import (
 // Capabilities is an index of the support API versions on the server
 type Capabilities map[string]bool
 
-func GetNodeArchitecture() string {
-	res, _ := KubectlTask("get", "nodes", `--output`, `jsonpath={range $.items[0]}{.status.nodeInfo.architecture}`)
+// GetNodeArchitecture returns the architecture of the first node.
+func GetNodeArchitecture() (string, error) {
+	arch, err := GetClient().NodeArchitecture(context.Background())
+	if err == nil && len(arch) == 0 {
+		err = fmt.Errorf("unable to get node architecture: no nodes were found")
+	}
 
-	arch := strings.TrimSpace(string(res.Stdout))
+	// A dry-run may be rendered without access to a cluster
+	if err != nil && IsDryRun() {
+		arch = "amd64"
+		fmt.Printf("[dry-run] %s, assuming: %s\n", err, arch)
+		return arch, nil
+	}
 
-	return arch
+	return arch, err
 }
 
 // GetCapabilities returns the supported API versions on the server
 func GetCapabilities() (Capabilities, error) {
-	caps := Capabilities{}
-
-	result, err := KubectlTask("api-versions")
-	if err != nil {
-		return caps, fmt.Errorf("can not retreive cluster capabilities: %w", err)
-	}
+	caps, err := GetClient().Capabilities(context.Background())
 
-	apis := strings.Split(result.Stdout, "")
-	lines := bufio.NewScanner(strings.NewReader(result.Stdout))
-	for lines.Scan() {
-		caps[lines.Text()] = true
+	// A dry-run may be rendered without access to a cluster
+	if err != nil && IsDryRun() {
+		fmt.Printf("[dry-run] %s, assuming no optional APIs\n", err)
+		return Capabilities{}, nil
 	}
 
-	for _, api := range apis {
-		caps[api] = true
-	}
-	return caps, nil
+	return caps, err
 }
 
 func KubectlTaskStdin(reader io.Reader, parts ...string) (execute.ExecResult, error) {
@@ -55,7 +54,7 @@ func KubectlTaskStdin(reader io.Reader, parts ...string) (execute.ExecResult, er
 		Stdin:       reader,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := Run(context.Background(), task)
 
 	return res, err
 }
@@ -66,7 +65,7 @@ func KubectlTask(parts ...string) (execute.ExecResult, error) {
 		StreamStdio: false,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := Run(context.Background(), task)
 
 	return res, err
 }
@@ -78,7 +77,7 @@ func Kubectl(parts ...string) error {
 		StreamStdio: true,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := Run(context.Background(), task)
 
 	if err != nil {
 		return err
@@ -100,7 +99,7 @@ func KubectlIn(stdin io.Reader, parts ...string) error {
 		Stdin:       stdin,
 	}
 
-	res, err := task.Execute(context.Background())
+	res, err := Run(context.Background(), task)
 
 	if err != nil {
 		return err
@@ -115,36 +114,16 @@ func KubectlIn(stdin io.Reader, parts ...string) error {
 }
 
 func CreateNamespace(namespace string) error {
-	nsRes, nsErr := KubectlTask("create", "namespace", namespace)
-	if nsErr != nil {
-		return nsErr
-	}
-	if nsRes.ExitCode != 0 {
-		fmt.Printf("[Warning] unable to create namespace %s, may already exist: %s", namespace, nsRes.Stderr)
+	if err := GetClient().CreateNamespace(context.Background(), namespace); err != nil {
+		fmt.Printf("[Warning] unable to create namespace %s: %s\n", namespace, err)
 	}
 
 	return nil
 }
 
+// CreateSecret creates a secret, or updates it when it already exists.
 func CreateSecret(secret types.K8sSecret) error {
-	secretData, err := flattenSecretData(secret.SecretData)
-	if err != nil {
-		return err
-	}
-
-	args := []string{"-n", secret.Namespace, "create", "secret", secret.Type, secret.Name}
-	args = append(args, secretData...)
-
-	res, secretErr := KubectlTask(args...)
-
-	if secretErr != nil {
-		return secretErr
-	}
-	if res.ExitCode != 0 {
-		fmt.Printf("[Warning] unable to create secret %s, may already exist: %s", secret.Name, res.Stderr)
-	}
-
-	return nil
+	return GetClient().ApplySecret(context.Background(), secret)
 }
 
 func flattenSecretData(data []types.SecretsData) ([]string, error) {
@@ -159,7 +138,7 @@ func flattenSecretData(data []types.SecretsData) ([]string, error) {
 			output = append(output, fmt.Sprintf("--from-file=%s=%s", value.Key, value.Value))
 		default:
 
-			return nil, fmt.Errorf("could not create secret value of type %s. Please use one of [%s, %s]", value.Type, types.StringLiteralSecret, types.FromFileSecret)
+			return nil, fmt.Errorf("could not create secret value of type %s, see ResolveSecret", value.Type)
 
 		}
 	}
@@ -0,0 +1,319 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexellis/arkade/pkg"
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/state"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+// RecordingExecutor runs tasks with another Executor and records the
+// helm releases, namespaces, secrets and manifests which they create
+// successfully.
+type RecordingExecutor struct {
+	Executor Executor
+	Record   *state.Record
+
+	// ManifestDir receives copies of manifests applied from local
+	// files or stdin, which may be temporary.
+	ManifestDir string
+
+	mu sync.Mutex
+}
+
+// NewRecordingExecutor records into record, copying manifests into
+// manifestDir.
+func NewRecordingExecutor(record *state.Record, manifestDir string) *RecordingExecutor {
+	return &RecordingExecutor{
+		Executor:    TaskExecutor{},
+		Record:      record,
+		ManifestDir: manifestDir,
+	}
+}
+
+func (r *RecordingExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	var stdin []byte
+	if task.Stdin != nil {
+		data, err := io.ReadAll(task.Stdin)
+		if err != nil {
+			return execute.ExecResult{}, err
+		}
+		stdin = data
+		task.Stdin = bytes.NewReader(data)
+	}
+
+	res, err := r.Executor.Execute(ctx, task)
+	if err != nil || res.ExitCode != 0 {
+		return res, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch filepath.Base(task.Command) {
+	case "helm":
+		r.recordHelm(task.Args)
+	case "kubectl":
+		if err := r.recordKubectl(task.Args, stdin, res.Stdout); err != nil {
+			return res, fmt.Errorf("unable to record install: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+func (r *RecordingExecutor) recordHelm(args []string) {
+	positional, flags := SplitArgs(args)
+	if len(positional) < 2 {
+		return
+	}
+
+	switch positional[0] {
+	case "upgrade", "install":
+		rel := state.Release{Name: positional[1], Namespace: flags["--namespace"]}
+		if len(rel.Namespace) == 0 {
+			rel.Namespace = flags["-n"]
+		}
+		r.Record.Merge(&state.Record{Releases: []state.Release{rel}})
+	}
+}
+
+func (r *RecordingExecutor) recordKubectl(args []string, stdin []byte, stdout string) error {
+	positional, flags := SplitArgs(args)
+	if len(positional) == 0 {
+		return nil
+	}
+
+	namespace := flags["-n"]
+	if len(namespace) == 0 {
+		namespace = flags["--namespace"]
+	}
+
+	switch positional[0] {
+	case "create":
+		if len(positional) >= 3 && (positional[1] == "namespace" || positional[1] == "ns") {
+			r.Record.Merge(&state.Record{Namespaces: []string{positional[2]}})
+		}
+		if len(positional) >= 4 && positional[1] == "secret" {
+			r.Record.Merge(&state.Record{Secrets: []state.Resource{{Name: positional[3], Namespace: namespace}}})
+		}
+
+	case "apply":
+		for i := 0; i < len(args)-1; i++ {
+			var manifest state.Manifest
+			var err error
+
+			switch args[i] {
+			case "-f", "--filename":
+				manifest, err = r.saveManifest(args[i+1], stdin)
+			case "-k", "--kustomize":
+				manifest, err = r.saveKustomization(args[i+1])
+			default:
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			manifest.Namespace = namespace
+			r.Record.Merge(&state.Record{Manifests: []state.Manifest{manifest}})
+		}
+
+		// i.e. "namespace/openfaas created"
+		for _, line := range strings.Split(stdout, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 && fields[1] == "created" && strings.HasPrefix(fields[0], "namespace/") {
+				r.Record.Merge(&state.Record{Namespaces: []string{strings.TrimPrefix(fields[0], "namespace/")}})
+			}
+		}
+	}
+
+	return nil
+}
+
+// saveManifest copies a manifest, keeping the original source when a
+// URL can't be fetched.
+func (r *RecordingExecutor) saveManifest(source string, stdin []byte) (state.Manifest, error) {
+	data, err := ReadManifest(source, stdin)
+	if err != nil {
+		if strings.Contains(source, "://") {
+			return state.Manifest{Source: source}, nil
+		}
+		return state.Manifest{}, err
+	}
+
+	file, err := r.copyManifest(data)
+	return state.Manifest{Source: file}, err
+}
+
+// ReadManifest reads what "kubectl apply -f source" would apply, from
+// stdin when source is "-", a URL, a file or a directory.
+func ReadManifest(source string, stdin []byte) ([]byte, error) {
+	switch {
+	case source == "-":
+		return stdin, nil
+	case strings.Contains(source, "://"):
+		return fetchManifest(source)
+	default:
+		return readManifests(source)
+	}
+}
+
+// saveKustomization renders a kustomization, keeping the original
+// source when it can't be rendered.
+func (r *RecordingExecutor) saveKustomization(source string) (state.Manifest, error) {
+	res, err := r.Executor.Execute(context.Background(), execute.ExecTask{
+		Command: "kubectl",
+		Args:    []string{"kustomize", source},
+	})
+	if err != nil || res.ExitCode != 0 {
+		return state.Manifest{Source: source, Kustomize: true}, nil
+	}
+
+	file, err := r.copyManifest([]byte(res.Stdout))
+	return state.Manifest{Source: file}, err
+}
+
+func fetchManifest(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", pkg.UserAgent())
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d for %s", res.StatusCode, url)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// copyManifest names each copy by its content, as the record is merged
+// with those of earlier installs, which still refer to their copies.
+func (r *RecordingExecutor) copyManifest(data []byte) (string, error) {
+	if err := os.MkdirAll(r.ManifestDir, 0700); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	file := filepath.Join(r.ManifestDir, hex.EncodeToString(sum[:8])+".yaml")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// readManifests reads a file, or all files in a directory joined as a
+// multi-document manifest, as used with "kubectl apply -R -f".
+func readManifests(source string) ([]byte, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return os.ReadFile(source)
+	}
+
+	var docs [][]byte
+	err = filepath.WalkDir(source, func(p string, e os.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, data)
+		return nil
+	})
+
+	return bytes.Join(docs, []byte("\n---\n")), err
+}
+
+// SplitArgs separates positional arguments from flags which take a
+// value, such as "-n NAMESPACE" or "--namespace=NAMESPACE".
+func SplitArgs(args []string) ([]string, map[string]string) {
+	var positional []string
+	flags := map[string]string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if k, v, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(k, "-") {
+			flags[k] = v
+			continue
+		}
+		if flagsWithValues[arg] && i+1 < len(args) {
+			flags[arg] = args[i+1]
+			i++
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+
+	return positional, flags
+}
+
+// CurrentContext returns the current context of a kubeconfig file, or
+// state.DefaultContext when it cannot be read.
+func CurrentContext(kubeconfig string) string {
+	if name := config.KubeContext(); len(name) > 0 {
+		return name
+	}
+
+	args := []string{"config", "current-context"}
+	if len(kubeconfig) > 0 {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+
+	res, err := KubectlTask(args...)
+	if err != nil || res.ExitCode != 0 || len(strings.TrimSpace(res.Stdout)) == 0 {
+		return state.DefaultContext
+	}
+
+	return strings.TrimSpace(res.Stdout)
+}
+
+// CurrentCluster returns the current context of a kubeconfig file along
+// with the API server of its cluster, which is empty when it cannot be
+// read.
+func CurrentCluster(kubeconfig string) state.Cluster {
+	cluster := state.Cluster{Context: CurrentContext(kubeconfig)}
+
+	args := []string{"config", "view", "--minify", "--context", cluster.Context,
+		"--output", "jsonpath={.clusters[0].cluster.server}"}
+	if len(kubeconfig) > 0 {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+
+	res, err := KubectlTask(args...)
+	if err == nil && res.ExitCode == 0 {
+		cluster.Server = strings.TrimSpace(res.Stdout)
+	}
+	return cluster
+}
@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/state"
+	execute "github.com/alexellis/go-execute/v2"
+)
+
+type fakeExecutor struct {
+	tasks  []execute.ExecTask
+	result execute.ExecResult
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	f.tasks = append(f.tasks, task)
+	return f.result, nil
+}
+
+func Test_RecordingExecutor(t *testing.T) {
+	fake := &fakeExecutor{result: execute.ExecResult{Stdout: "namespace/openfaas-fn created\nsecret/creds created\n"}}
+	record := &state.Record{App: "openfaas"}
+	manifestDir := t.TempDir()
+
+	r := NewRecordingExecutor(record, manifestDir)
+	r.Executor = fake
+
+	tasks := []execute.ExecTask{
+		{Command: "kubectl", Args: []string{"create", "namespace", "openfaas"}},
+		{Command: "kubectl", Args: []string{"-n", "openfaas", "create", "secret", "generic", "basic-auth", "--from-literal=basic-auth-password=secret"}},
+		{Command: "kubectl", Args: []string{"apply", "-f", "-"}, Stdin: strings.NewReader("kind: Secret\n")},
+		{Command: "/home/user/.arkade/bin/helm", Args: []string{"upgrade", "--install", "openfaas", "openfaas/openfaas", "--namespace", "openfaas"}},
+		{Command: "kubectl", Args: []string{"get", "nodes"}},
+	}
+	for _, task := range tasks {
+		if _, err := r.Execute(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(fake.tasks) != len(tasks) {
+		t.Fatalf("want %d tasks run, got %d", len(tasks), len(fake.tasks))
+	}
+
+	want := []string{"openfaas", "openfaas-fn"}
+	if strings.Join(record.Namespaces, ",") != strings.Join(want, ",") {
+		t.Fatalf("want namespaces %v, got %v", want, record.Namespaces)
+	}
+	if len(record.Secrets) != 1 || record.Secrets[0] != (state.Resource{Name: "basic-auth", Namespace: "openfaas"}) {
+		t.Fatalf("unexpected secrets: %v", record.Secrets)
+	}
+	if len(record.Releases) != 1 || record.Releases[0] != (state.Release{Name: "openfaas", Namespace: "openfaas"}) {
+		t.Fatalf("unexpected releases: %v", record.Releases)
+	}
+	if len(record.Manifests) != 1 {
+		t.Fatalf("want 1 manifest, got %v", record.Manifests)
+	}
+
+	data, err := os.ReadFile(record.Manifests[0].Source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "kind: Secret\n" || filepath.Dir(record.Manifests[0].Source) != manifestDir {
+		t.Fatalf("unexpected manifest copy %s: %q", record.Manifests[0].Source, string(data))
+	}
+}
+
+func Test_RecordingExecutor_SkipsFailures(t *testing.T) {
+	fake := &fakeExecutor{result: execute.ExecResult{ExitCode: 1, Stderr: "AlreadyExists"}}
+	record := &state.Record{}
+
+	r := NewRecordingExecutor(record, t.TempDir())
+	r.Executor = fake
+
+	if _, err := r.Execute(context.Background(), execute.ExecTask{Command: "kubectl", Args: []string{"create", "namespace", "openfaas"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !record.Empty() {
+		t.Fatalf("want empty record, got %+v", record)
+	}
+}
+
+func Test_RecordingExecutor_KeepsEarlierManifests(t *testing.T) {
+	manifestDir := t.TempDir()
+
+	var sources []string
+	for _, manifest := range []string{"kind: Namespace\n", "kind: Deployment\n"} {
+		record := &state.Record{}
+		r := NewRecordingExecutor(record, manifestDir)
+		r.Executor = &fakeExecutor{}
+
+		task := execute.ExecTask{Command: "kubectl", Args: []string{"apply", "-f", "-"}, Stdin: strings.NewReader(manifest)}
+		if _, err := r.Execute(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, record.Manifests[0].Source)
+	}
+
+	if sources[0] == sources[1] {
+		t.Fatalf("want a copy for each install, got %s twice", sources[0])
+	}
+	data, err := os.ReadFile(sources[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "kind: Namespace\n" {
+		t.Errorf("want the first install's manifest kept, got %q", string(data))
+	}
+}
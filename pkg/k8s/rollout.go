@@ -0,0 +1,220 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	execute "github.com/alexellis/go-execute/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// workloadKinds are waited for by WaitForRollouts, a manifest with one
+// of them and no namespace is applied to the default namespace.
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"Job":         true,
+}
+
+// RolloutExecutor runs tasks with another Executor, and remembers the
+// namespaces of the manifests applied with kubectl and the charts
+// installed with helm, so that the workloads in them can be waited for
+// with WaitForRollouts.
+type RolloutExecutor struct {
+	Executor Executor
+
+	mu         sync.Mutex
+	namespaces map[string]bool
+}
+
+// NewRolloutExecutor tracks the manifests applied by tasks run with e.
+func NewRolloutExecutor(e Executor) *RolloutExecutor {
+	return &RolloutExecutor{
+		Executor:   e,
+		namespaces: map[string]bool{},
+	}
+}
+
+func (r *RolloutExecutor) Execute(ctx context.Context, task execute.ExecTask) (execute.ExecResult, error) {
+	var stdin []byte
+	if task.Stdin != nil {
+		data, err := io.ReadAll(task.Stdin)
+		if err != nil {
+			return execute.ExecResult{}, err
+		}
+		stdin = data
+		task.Stdin = bytes.NewReader(data)
+	}
+
+	res, err := r.Executor.Execute(ctx, task)
+	if err != nil || res.ExitCode != 0 {
+		return res, err
+	}
+
+	positional, flags := SplitArgs(task.Args)
+	if len(positional) == 0 {
+		return res, nil
+	}
+
+	switch filepath.Base(task.Command) {
+	case "kubectl":
+		if positional[0] == "apply" {
+			r.trackApply(ctx, task.Args, stdin)
+		}
+	case "helm":
+		if positional[0] == "upgrade" || positional[0] == "install" {
+			r.track(namespaceFlag(flags))
+		}
+	}
+
+	return res, nil
+}
+
+func (r *RolloutExecutor) track(namespaces ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ns := range namespaces {
+		r.namespaces[ns] = true
+	}
+}
+
+// namespaceFlag returns the value of -n or --namespace, or "default".
+func namespaceFlag(flags map[string]string) string {
+	if ns := flags["-n"]; len(ns) > 0 {
+		return ns
+	}
+	if ns := flags["--namespace"]; len(ns) > 0 {
+		return ns
+	}
+	return "default"
+}
+
+// Namespaces returns the namespaces which manifests were applied to.
+func (r *RolloutExecutor) Namespaces() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	namespaces := make([]string, 0, len(r.namespaces))
+	for ns := range r.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// trackApply reads the namespaces from each manifest in an apply. A
+// manifest which can't be read is skipped, as it was applied already.
+func (r *RolloutExecutor) trackApply(ctx context.Context, args []string, stdin []byte) {
+	_, flags := SplitArgs(args)
+	namespace := namespaceFlag(flags)
+
+	for i := 0; i < len(args)-1; i++ {
+		var data []byte
+		var err error
+
+		switch args[i] {
+		case "-f", "--filename":
+			data, err = ReadManifest(args[i+1], stdin)
+		case "-k", "--kustomize":
+			var res execute.ExecResult
+			res, err = r.Executor.Execute(ctx, execute.ExecTask{Command: "kubectl", Args: []string{"kustomize", args[i+1]}})
+			data = []byte(res.Stdout)
+		default:
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		r.track(manifestNamespaces(data, namespace)...)
+	}
+}
+
+// manifestNamespaces returns the namespace of each object in a
+// manifest, using defaultNamespace for workloads which don't give one.
+func manifestNamespaces(manifest []byte, defaultNamespace string) []string {
+	var namespaces []string
+	dec := yaml.NewDecoder(bytes.NewReader(manifest))
+	for {
+		var doc struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		switch {
+		case len(doc.Metadata.Namespace) > 0:
+			namespaces = append(namespaces, doc.Metadata.Namespace)
+		case workloadKinds[doc.Kind]:
+			namespaces = append(namespaces, defaultNamespace)
+		}
+	}
+
+	return namespaces
+}
+
+// WaitForRollouts waits for the Deployments, StatefulSets and
+// DaemonSets in each namespace to roll out, and for Jobs to complete,
+// within timeout overall. Progress is written to out.
+func WaitForRollouts(ctx context.Context, namespaces []string, timeout time.Duration, out io.Writer) error {
+	deadline := time.Now().Add(timeout)
+
+	type workload struct {
+		namespace string
+		resource  string
+	}
+
+	var workloads []workload
+	for _, ns := range namespaces {
+		resources, err := GetClient().Workloads(ctx, ns)
+		if err != nil {
+			return fmt.Errorf("unable to list workloads in %s: %w", ns, err)
+		}
+		for _, resource := range resources {
+			workloads = append(workloads, workload{namespace: ns, resource: resource})
+		}
+	}
+
+	if len(workloads) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "Waiting up to %s for %d workload(s) to be ready\n", timeout, len(workloads))
+	for i, w := range workloads {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("timed out after %s waiting for %s in %s", timeout, w.resource, w.namespace)
+		}
+
+		// kubectl waits forever for a timeout of 0s
+		remaining = max(remaining.Round(time.Second), time.Second)
+
+		fmt.Fprintf(out, "[%d/%d] Waiting for %s in %s\n", i+1, len(workloads), w.resource, w.namespace)
+		if err := GetClient().WaitForRollout(ctx, w.namespace, w.resource, remaining); err != nil {
+			return fmt.Errorf("%s in %s is not ready: %w", w.resource, w.namespace, err)
+		}
+	}
+	fmt.Fprintf(out, "%d workload(s) are ready\n", len(workloads))
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	execute "github.com/alexellis/go-execute/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_RolloutExecutor_TracksNamespaces(t *testing.T) {
+	fake := &fakeExecutor{}
+	r := NewRolloutExecutor(fake)
+
+	manifest := `apiVersion: v1
+kind: Namespace
+metadata:
+  name: argocd
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: argocd-server
+  namespace: argocd
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: speaker
+`
+
+	tasks := []execute.ExecTask{
+		{Command: "kubectl", Args: []string{"--context=edge-1", "apply", "-n", "metallb-system", "-f", "-"}, Stdin: strings.NewReader(manifest)},
+		{Command: "/home/user/.arkade/bin/helm", Args: []string{"upgrade", "--install", "openfaas", "openfaas/openfaas", "--namespace", "openfaas"}},
+		{Command: "kubectl", Args: []string{"get", "nodes", "-n", "kube-system"}},
+	}
+	for _, task := range tasks {
+		if _, err := r.Execute(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"argocd", "metallb-system", "openfaas"}
+	if got := r.Namespaces(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want namespaces %v, got %v", want, got)
+	}
+}
+
+func Test_RolloutExecutor_SkipsFailures(t *testing.T) {
+	fake := &fakeExecutor{result: execute.ExecResult{ExitCode: 1}}
+	r := NewRolloutExecutor(fake)
+
+	task := execute.ExecTask{Command: "kubectl", Args: []string{"apply", "-f", "-"}, Stdin: strings.NewReader("kind: Deployment\n")}
+	if _, err := r.Execute(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := r.Namespaces(); len(got) != 0 {
+		t.Errorf("want no namespaces for a failed apply, got %v", got)
+	}
+}
+
+func Test_WaitForRollouts(t *testing.T) {
+	fake := NewFakeClient()
+	fake.Resources["argocd/deployment/argocd-server"] = true
+	fake.Resources["argocd/statefulset/argocd-application-controller"] = true
+	fake.Resources["argocd/secret/argocd-secret"] = true
+	fake.Resources["tekton/job/migrate"] = true
+	SetClient(fake)
+	defer SetClient(nil)
+
+	out := &bytes.Buffer{}
+	if err := WaitForRollouts(context.Background(), []string{"argocd", "tekton"}, time.Minute, out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"argocd/deployment/argocd-server",
+		"argocd/statefulset/argocd-application-controller",
+		"tekton/job/migrate",
+	}
+	if !reflect.DeepEqual(fake.Rollouts, want) {
+		t.Errorf("want rollouts %v, got %v", want, fake.Rollouts)
+	}
+	for _, line := range []string{"[1/3] Waiting for deployment/argocd-server in argocd", "3 workload(s) are ready"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("want %q in output:\n%s", line, out.String())
+		}
+	}
+}
+
+func Test_WaitForRollouts_Error(t *testing.T) {
+	fake := NewFakeClient()
+	fake.Err = errors.New("timed out waiting for the condition")
+	SetClient(fake)
+	defer SetClient(nil)
+
+	err := WaitForRollouts(context.Background(), []string{"argocd"}, time.Minute, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "argocd") {
+		t.Errorf("want an error for argocd, got %v", err)
+	}
+}
+
+func Test_KubernetesClient_WorkloadsAndJobs(t *testing.T) {
+	c, _, _ := newTestClient(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "openfaas"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "openfaas"}},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "openfaas"},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}},
+		},
+	)
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	got, err := c.Workloads(context.Background(), "openfaas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"deployment/gateway", "statefulset/nats", "job/migrate"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want workloads %v, got %v", want, got)
+	}
+
+	if err := c.WaitForRollout(context.Background(), "openfaas", "job/migrate", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCommands := []string{
+		"get deployments,statefulsets,daemonsets,jobs --namespace openfaas --output=name",
+		"wait --for=condition=complete job/migrate --namespace openfaas --timeout 1m0s",
+	}
+	if got := recorder.commands(); !reflect.DeepEqual(got, wantCommands) {
+		t.Errorf("want commands %v, got %v", wantCommands, got)
+	}
+}
+
+func Test_KubernetesClient_WaitForRollout_JobFailed(t *testing.T) {
+	c, _, _ := newTestClient(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "openfaas"},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+		}},
+	})
+
+	err := c.WaitForRollout(context.Background(), "openfaas", "job/migrate", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "BackoffLimitExceeded") {
+		t.Errorf("want the job's failure, got %v", err)
+	}
+}
+
+func Test_deploymentReady(t *testing.T) {
+	replicas := int32(2)
+	tests := []struct {
+		name   string
+		status appsv1.DeploymentStatus
+		want   bool
+	}{
+		{"rolled out", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}, true},
+		{"not observed", appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}, false},
+		{"old replicas", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}, false},
+		{"unavailable", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     tc.status,
+			}
+			if got := deploymentReady(d); got != tc.want {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/alexellis/arkade/pkg/config"
+	"github.com/alexellis/arkade/pkg/types"
+	execute "github.com/alexellis/go-execute/v2"
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// secretStdin is read once, for every FromStdinSecret value.
+	secretStdin io.Reader = os.Stdin
+
+	stdinOnce  sync.Once
+	stdinValue string
+	stdinErr   error
+)
+
+// ResolveSecret reads each of a secret's values from its source, so
+// that only literals and files remain. existing returns the value of a
+// key in the secret when it already exists, so that generated values
+// are kept, and may be nil.
+func ResolveSecret(ctx context.Context, secret types.K8sSecret, existing func(key string) (string, bool)) (types.K8sSecret, error) {
+	resolved := secret
+	resolved.SecretData = make([]types.SecretsData, 0, len(secret.SecretData))
+
+	for _, data := range secret.SecretData {
+		if data.Type == types.StringLiteralSecret || data.Type == types.FromFileSecret {
+			resolved.SecretData = append(resolved.SecretData, data)
+			continue
+		}
+
+		value, err := resolveSecretValue(ctx, data, existing)
+		if err != nil {
+			return resolved, fmt.Errorf("unable to read %s for secret %s: %w", data.Key, secret.Name, err)
+		}
+		resolved.SecretData = append(resolved.SecretData, types.SecretsData{
+			Type:  types.StringLiteralSecret,
+			Key:   data.Key,
+			Value: value,
+		})
+	}
+
+	return resolved, nil
+}
+
+func resolveSecretValue(ctx context.Context, data types.SecretsData, existing func(key string) (string, bool)) (string, error) {
+	switch data.Type {
+	case types.FromEnvSecret:
+		value, ok := os.LookupEnv(data.Value)
+		if !ok || len(value) == 0 {
+			return "", fmt.Errorf("environment variable %s is not set", data.Value)
+		}
+		return value, nil
+
+	case types.FromStdinSecret:
+		stdinOnce.Do(func() {
+			var b []byte
+			b, stdinErr = io.ReadAll(secretStdin)
+			stdinValue = strings.TrimRight(string(b), "\r\n")
+		})
+		if stdinErr == nil && len(stdinValue) == 0 {
+			return "", fmt.Errorf("no value was given on stdin")
+		}
+		return stdinValue, stdinErr
+
+	case types.FromSOPSSecret:
+		return decryptSOPS(ctx, data.Value)
+
+	case types.GeneratedSecret:
+		if existing != nil {
+			if value, ok := existing(data.Key); ok {
+				return value, nil
+			}
+		}
+
+		policy := types.DefaultPasswordPolicy
+		if data.Policy != nil {
+			policy = *data.Policy
+		}
+		return password.Generate(policy.Length, policy.Digits, policy.Symbols, policy.NoUpper, policy.AllowRepeat)
+	}
+
+	return "", fmt.Errorf("unknown secret source %q, use one of [%s]", data.Type, strings.Join([]string{
+		types.StringLiteralSecret, types.FromFileSecret, types.FromEnvSecret,
+		types.FromStdinSecret, types.FromSOPSSecret, types.GeneratedSecret,
+	}, ", "))
+}
+
+// decryptSOPS decrypts a file with sops, which finds its own keys, i.e.
+// an age key in SOPS_AGE_KEY_FILE.
+func decryptSOPS(ctx context.Context, file string) (string, error) {
+	sops, err := sopsPath()
+	if err != nil {
+		return "", err
+	}
+
+	res, err := Run(ctx, execute.ExecTask{Command: sops, Args: []string{"decrypt", file}})
+	if err != nil {
+		return "", err
+	}
+	if res.ExitCode != 0 {
+		return "", fmt.Errorf("unable to decrypt %s with sops: %s", file, strings.TrimSpace(res.Stderr))
+	}
+
+	return res.Stdout, nil
+}
+
+// sopsPath finds sops in the PATH, or where "arkade get sops" puts it.
+func sopsPath() (string, error) {
+	if p, err := exec.LookPath("sops"); err == nil {
+		return p, nil
+	}
+
+	p := filepath.Join(config.GetPaths().Bin, "sops")
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+
+	return "", fmt.Errorf("sops is needed to decrypt secrets, run \"arkade get sops\" to get it")
+}
+
+// secretValue returns the decoded value of a key in an existing secret.
+func (c *KubernetesClient) secretValue(ctx context.Context, namespace, name, key string) (string, bool) {
+	jsonPath := fmt.Sprintf("{.data.%s}", strings.ReplaceAll(key, ".", `\.`))
+
+	encoded, err := c.Get(ctx, namespace, "secret/"+name, jsonPath)
+	if err != nil || len(encoded) == 0 {
+		return "", false
+	}
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
+
+// secretObject builds the Secret created by "kubectl create secret
+// generic" from a resolved secret's literals and files.
+func secretObject(secret types.K8sSecret) (*corev1.Secret, error) {
+	if secret.Type != types.KubernetesGenericSecret {
+		return nil, fmt.Errorf("unsupported type %q for secret %s", secret.Type, secret.Name)
+	}
+
+	data := map[string][]byte{}
+	for _, value := range secret.SecretData {
+		switch value.Type {
+		case types.StringLiteralSecret:
+			data[value.Key] = []byte(value.Value)
+
+		case types.FromFileSecret:
+			b, err := os.ReadFile(value.Value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s for secret %s: %w", value.Key, secret.Name, err)
+			}
+			data[value.Key] = b
+
+		default:
+			return nil, fmt.Errorf("could not create secret value of type %s, see ResolveSecret", value.Type)
+		}
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
@@ -0,0 +1,219 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alexellis/arkade/pkg/types"
+	execute "github.com/alexellis/go-execute/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fakeStdin(t *testing.T, value string) {
+	t.Helper()
+
+	original := secretStdin
+	secretStdin = strings.NewReader(value)
+	stdinOnce = sync.Once{}
+	t.Cleanup(func() {
+		secretStdin = original
+		stdinOnce = sync.Once{}
+	})
+}
+
+func Test_ResolveSecret_Sources(t *testing.T) {
+	t.Setenv("OF_PASSWORD", "from-env")
+	fakeStdin(t, "from-stdin\n")
+
+	secret := types.NewGenericSecret("creds", "openfaas", []types.SecretsData{
+		{Type: types.StringLiteralSecret, Key: "user", Value: "admin"},
+		{Type: types.FromFileSecret, Key: "license", Value: "/tmp/LICENSE"},
+		{Type: types.FromEnvSecret, Key: "env", Value: "OF_PASSWORD"},
+		{Type: types.FromStdinSecret, Key: "stdin"},
+		{Type: types.FromStdinSecret, Key: "stdin-again"},
+		{Type: types.GeneratedSecret, Key: "generated", Policy: &types.PasswordPolicy{Length: 40, Digits: 5}},
+	})
+
+	got, err := ResolveSecret(context.Background(), secret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"user":        "admin",
+		"license":     "/tmp/LICENSE",
+		"env":         "from-env",
+		"stdin":       "from-stdin",
+		"stdin-again": "from-stdin",
+	}
+	for _, data := range got.SecretData {
+		wantType := types.StringLiteralSecret
+		if data.Key == "license" {
+			wantType = types.FromFileSecret
+		}
+		if data.Type != wantType {
+			t.Errorf("want %s to be %s, got %s", data.Key, wantType, data.Type)
+		}
+
+		if data.Key == "generated" {
+			if len(data.Value) != 40 {
+				t.Errorf("want a 40 character password, got %q", data.Value)
+			}
+			continue
+		}
+		if data.Value != want[data.Key] {
+			t.Errorf("want %s to be %q, got %q", data.Key, want[data.Key], data.Value)
+		}
+	}
+}
+
+func Test_ResolveSecret_Errors(t *testing.T) {
+	fakeStdin(t, "")
+
+	tests := []struct {
+		data types.SecretsData
+		want string
+	}{
+		{types.SecretsData{Type: types.FromEnvSecret, Key: "a", Value: "ARKADE_TEST_UNSET"}, "ARKADE_TEST_UNSET is not set"},
+		{types.SecretsData{Type: types.FromStdinSecret, Key: "a"}, "no value was given on stdin"},
+		{types.SecretsData{Type: "vault", Key: "a"}, "unknown secret source"},
+	}
+
+	for _, tc := range tests {
+		secret := types.NewGenericSecret("creds", "default", []types.SecretsData{tc.data})
+		_, err := ResolveSecret(context.Background(), secret, nil)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("want error containing %q, got %v", tc.want, err)
+		}
+	}
+}
+
+func Test_FakeClient_ApplySecret_KeepsGenerated(t *testing.T) {
+	fake := NewFakeClient()
+	secret := types.NewGenericSecret("basic-auth", "openfaas", []types.SecretsData{
+		{Type: types.GeneratedSecret, Key: "basic-auth-password"},
+	})
+
+	if err := fake.ApplySecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	first := fake.Secrets["openfaas/basic-auth"].SecretData[0].Value
+
+	if err := fake.ApplySecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Secrets["openfaas/basic-auth"].SecretData[0].Value; got != first || len(first) != 25 {
+		t.Errorf("want the generated password %q kept, got %q", first, got)
+	}
+}
+
+func Test_KubernetesClient_ApplySecret_KeepsGenerated(t *testing.T) {
+	c, clientset, _ := newTestClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic-auth", Namespace: "openfaas"},
+		Data:       map[string][]byte{"basic-auth-password": []byte("hunter2")},
+	})
+	recorder := &scriptedExecutor{}
+	SetExecutor(recorder)
+	defer SetExecutor(nil)
+
+	secret := types.NewGenericSecret("basic-auth", "openfaas", []types.SecretsData{
+		{Type: types.GeneratedSecret, Key: "basic-auth-password"},
+	})
+	if err := c.ApplySecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"get secret/basic-auth --output=jsonpath={.data.basic-auth-password} --namespace openfaas",
+		"-n openfaas create secret generic basic-auth --from-literal=basic-auth-password=hunter2",
+		"-n openfaas replace -f -",
+	}
+	if got := recorder.commands(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want commands:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	updated, err := clientset.CoreV1().Secrets("openfaas").Get(context.Background(), "basic-auth", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(updated.Data["basic-auth-password"]); got != "hunter2" {
+		t.Errorf("want the generated password kept, got %q", got)
+	}
+}
+
+func Test_ResolveSecret_SOPS(t *testing.T) {
+	// A stand-in for sops, the commands are run by the scriptedExecutor
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sops"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	fake := &scriptedExecutor{results: []execute.ExecResult{{Stdout: "s3cr3t"}}}
+	SetExecutor(fake)
+	defer SetExecutor(nil)
+
+	secret := types.NewGenericSecret("creds", "default", []types.SecretsData{
+		{Type: types.FromSOPSSecret, Key: "password", Value: "password.enc"},
+	})
+	got, err := ResolveSecret(context.Background(), secret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.SecretData[0].Value != "s3cr3t" {
+		t.Errorf("want decrypted value, got %q", got.SecretData[0].Value)
+	}
+	if task := fake.tasks[0]; task.Command != filepath.Join(dir, "sops") || strings.Join(task.Args, " ") != "decrypt password.enc" {
+		t.Errorf("unexpected command %s %v", task.Command, task.Args)
+	}
+}
+
+// Test_ResolveSecret_SOPSWithAge encrypts a file with a local age key,
+// when sops and age-keygen are installed.
+func Test_ResolveSecret_SOPSWithAge(t *testing.T) {
+	for _, bin := range []string{"sops", "age-keygen"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s is not installed", bin)
+		}
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.txt")
+	out, err := exec.Command("age-keygen", "-o", keyFile).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+	_, recipient, _ := strings.Cut(strings.TrimSpace(string(out)), "Public key: ")
+
+	plain := filepath.Join(dir, "password.txt")
+	if err := os.WriteFile(plain, []byte("s3cr3t"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := exec.Command("sops", "encrypt", "--age", recipient, "--input-type", "binary", "--output-type", "binary", plain).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "password.enc")
+	if err := os.WriteFile(file, encrypted, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SOPS_AGE_KEY_FILE", keyFile)
+	secret := types.NewGenericSecret("creds", "default", []types.SecretsData{
+		{Type: types.FromSOPSSecret, Key: "password", Value: file},
+	})
+	got, err := ResolveSecret(context.Background(), secret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SecretData[0].Value != "s3cr3t" {
+		t.Errorf("want decrypted value, got %q", got.SecretData[0].Value)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright (c) arkade author(s) 2026. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+// Package state stores a record of what each "arkade install" created,
+// so that apps can be listed and uninstalled later.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexellis/arkade/pkg/config"
+)
+
+// DefaultContext is used when the kubeconfig has no current context.
+const DefaultContext = "default"
+
+// ChartPrefix is added to the release name of a chart installed with
+// "arkade install chart" to give the App of its record, i.e.
+// "chart/openfaas", so that it can't clash with an arkade app.
+const ChartPrefix = "chart/"
+
+// Cluster identifies where an app was installed: a kubeconfig context
+// and the API server of its cluster, as context names such as "default"
+// are used by many k3s and kind clusters.
+type Cluster struct {
+	Context string
+	Server  string
+}
+
+// Record describes what an app created in one cluster.
+type Record struct {
+	App     string `yaml:"app"`
+	Context string `yaml:"context"`
+	Server  string `yaml:"server,omitempty"`
+
+	// Installer is the arkade app used to install App, when it differs,
+	// i.e. "chart" for a chart recorded under its release name.
+	Installer string `yaml:"installer,omitempty"`
+
+	InstalledAt time.Time `yaml:"installed_at"`
+
+	// Flags are the flags explicitly set on the install command.
+	Flags map[string][]string `yaml:"flags,omitempty"`
+
+	Releases   []Release  `yaml:"releases,omitempty"`
+	Namespaces []string   `yaml:"namespaces,omitempty"`
+	Secrets    []Resource `yaml:"secrets,omitempty"`
+	Manifests  []Manifest `yaml:"manifests,omitempty"`
+}
+
+// Release is a helm release.
+type Release struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// Resource is a namespaced Kubernetes object.
+type Resource struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// Manifest is a file or URL applied with kubectl. Where possible the
+// manifest is copied into the state directory when it is applied, so
+// Source stays valid and matches what was installed.
+type Manifest struct {
+	Source    string `yaml:"source"`
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Kustomize is set when Source is a kustomization for "kubectl -k".
+	Kustomize bool `yaml:"kustomize,omitempty"`
+}
+
+// Cluster returns the Cluster the record is for.
+func (r *Record) Cluster() Cluster {
+	return Cluster{Context: r.Context, Server: r.Server}
+}
+
+// Empty is true when nothing was recorded.
+func (r *Record) Empty() bool {
+	return len(r.Releases) == 0 && len(r.Namespaces) == 0 &&
+		len(r.Secrets) == 0 && len(r.Manifests) == 0
+}
+
+// Merge adds anything from other which is not already recorded, and
+// takes the newer flags and install time.
+func (r *Record) Merge(other *Record) {
+	for _, rel := range other.Releases {
+		if !slices.Contains(r.Releases, rel) {
+			r.Releases = append(r.Releases, rel)
+		}
+	}
+	for _, ns := range other.Namespaces {
+		if !slices.Contains(r.Namespaces, ns) {
+			r.Namespaces = append(r.Namespaces, ns)
+		}
+	}
+	for _, s := range other.Secrets {
+		if !slices.Contains(r.Secrets, s) {
+			r.Secrets = append(r.Secrets, s)
+		}
+	}
+	for _, m := range other.Manifests {
+		if !slices.Contains(r.Manifests, m) {
+			r.Manifests = append(r.Manifests, m)
+		}
+	}
+
+	if other.Flags != nil {
+		r.Flags = other.Flags
+	}
+	if len(other.Installer) > 0 {
+		r.Installer = other.Installer
+	}
+	if other.InstalledAt.After(r.InstalledAt) {
+		r.InstalledAt = other.InstalledAt
+	}
+}
+
+// Store reads and writes records under a directory, with one directory
+// per kubeconfig context and server.
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a Store in arkade's state directory.
+func NewStore() *Store {
+	return &Store{Dir: config.GetPaths().State}
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// clusterDir returns a directory for a cluster. The context name may
+// contain characters such as ":" and "/", i.e. for EKS ARNs, and is
+// followed by a hash of the server's URL.
+func (s *Store) clusterDir(cluster Cluster) string {
+	name := cluster.Context
+	if len(name) == 0 {
+		name = DefaultContext
+	}
+	name = unsafeChars.ReplaceAllString(name, "_")
+
+	if len(cluster.Server) > 0 {
+		sum := sha256.Sum256([]byte(cluster.Server))
+		name += "-" + hex.EncodeToString(sum[:])[:12]
+	}
+	return filepath.Join(s.Dir, name)
+}
+
+// appPath returns the path for an app's files without an extension,
+// with records for charts in their own directory.
+func (s *Store) appPath(cluster Cluster, app string) string {
+	dir := s.clusterDir(cluster)
+	if release, ok := strings.CutPrefix(app, ChartPrefix); ok {
+		dir = filepath.Join(dir, strings.TrimSuffix(ChartPrefix, "/"))
+		app = release
+	}
+	return filepath.Join(dir, unsafeChars.ReplaceAllString(app, "_"))
+}
+
+func (s *Store) recordFile(cluster Cluster, app string) string {
+	return s.appPath(cluster, app) + ".yaml"
+}
+
+// ManifestDir is where copies of applied manifests are kept for an app.
+func (s *Store) ManifestDir(cluster Cluster, app string) string {
+	return s.appPath(cluster, app) + "-manifests"
+}
+
+// Get returns the record for an app, or an error wrapping
+// os.ErrNotExist when there isn't one.
+func (s *Store) Get(cluster Cluster, app string) (*Record, error) {
+	data, err := os.ReadFile(s.recordFile(cluster, app))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no install record for %s: %w", app, err)
+		}
+		return nil, err
+	}
+
+	record := &Record{}
+	if err := yaml.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("unable to parse install record for %s: %w", app, err)
+	}
+
+	return record, nil
+}
+
+// Save writes a record, merging it with any existing record so that
+// re-running an install keeps what the first run created.
+func (s *Store) Save(record *Record) error {
+	file := s.recordFile(record.Cluster(), record.App)
+
+	if existing, err := s.Get(record.Cluster(), record.App); err == nil {
+		existing.Merge(record)
+		record = existing
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0600)
+}
+
+// Delete removes a record and any manifests copied for it.
+func (s *Store) Delete(cluster Cluster, app string) error {
+	if err := os.RemoveAll(s.ManifestDir(cluster, app)); err != nil {
+		return err
+	}
+
+	err := os.Remove(s.recordFile(cluster, app))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns the records for a cluster, including those for charts,
+// sorted by app name.
+func (s *Store) List(cluster Cluster) ([]Record, error) {
+	dir := s.clusterDir(cluster)
+
+	var records []Record
+	for _, d := range []string{dir, filepath.Join(dir, strings.TrimSuffix(ChartPrefix, "/"))} {
+		found, err := readRecords(d)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, found...)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].App < records[j].App
+	})
+
+	return records, nil
+}
+
+// readRecords reads each record in a directory.
+func readRecords(dir string) ([]Record, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var record Record
+		if err := yaml.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", entry.Name(), err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
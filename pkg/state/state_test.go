@@ -0,0 +1,150 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_Store_SaveMergesExistingRecord(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	first := &Record{
+		App:         "openfaas",
+		Context:     "kind-kind",
+		InstalledAt: time.Now().Add(-time.Hour).UTC(),
+		Releases:    []Release{{Name: "openfaas", Namespace: "openfaas"}},
+		Namespaces:  []string{"openfaas", "openfaas-fn"},
+		Flags:       map[string][]string{"gateways": {"1"}},
+	}
+	if err := store.Save(first); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second install won't create the namespaces again
+	second := &Record{
+		App:         "openfaas",
+		Context:     "kind-kind",
+		InstalledAt: time.Now().UTC(),
+		Releases:    []Release{{Name: "openfaas", Namespace: "openfaas"}},
+		Secrets:     []Resource{{Name: "basic-auth", Namespace: "openfaas"}},
+		Flags:       map[string][]string{"gateways": {"2"}},
+	}
+	if err := store.Save(second); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Get(Cluster{Context: "kind-kind"}, "openfaas")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Releases) != 1 || len(got.Namespaces) != 2 || len(got.Secrets) != 1 {
+		t.Fatalf("unexpected merged record: %+v", got)
+	}
+	if got.Flags["gateways"][0] != "2" {
+		t.Fatalf("want latest flags, got %v", got.Flags)
+	}
+}
+
+func Test_Store_ListIsPerContext(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	for _, r := range []*Record{
+		{App: "openfaas", Context: "arn:aws:eks:eu-west-1:123:cluster/prod", Namespaces: []string{"openfaas"}},
+		{App: "cert-manager", Context: "arn:aws:eks:eu-west-1:123:cluster/prod", Namespaces: []string{"cert-manager"}},
+		{App: "minio", Context: "kind-kind", Namespaces: []string{"minio"}},
+	} {
+		if err := store.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.List(Cluster{Context: "arn:aws:eks:eu-west-1:123:cluster/prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].App != "cert-manager" || got[1].App != "openfaas" {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+
+	none, err := store.List(Cluster{Context: "missing"})
+	if err != nil || len(none) != 0 {
+		t.Fatalf("want no records and no error, got %v, %v", none, err)
+	}
+}
+
+func Test_Store_Delete(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	if err := store.Save(&Record{App: "minio", Namespaces: []string{"minio"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(Cluster{}, "minio"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(Cluster{}, "minio"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("want os.ErrNotExist, got %v", err)
+	}
+}
+
+func Test_Store_ListIsPerServer(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	// k3s names the context "default" for every cluster
+	for _, r := range []*Record{
+		{App: "openfaas", Context: "default", Server: "https://192.168.1.10:6443", Namespaces: []string{"openfaas"}},
+		{App: "minio", Context: "default", Server: "https://192.168.1.20:6443", Namespaces: []string{"minio"}},
+	} {
+		if err := store.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.List(Cluster{Context: "default", Server: "https://192.168.1.20:6443"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].App != "minio" {
+		t.Fatalf("want only minio, got %+v", got)
+	}
+}
+
+func Test_Store_ChartRecordsAreSeparate(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+	cluster := Cluster{Context: "kind-kind"}
+
+	for _, r := range []*Record{
+		{App: "openfaas", Context: "kind-kind", Namespaces: []string{"openfaas"}},
+		{App: ChartPrefix + "openfaas", Installer: "chart", Context: "kind-kind", Releases: []Release{{Name: "openfaas", Namespace: "default"}}},
+	} {
+		if err := store.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app, err := store.Get(cluster, "openfaas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Releases) != 0 {
+		t.Fatalf("want the app's record unchanged by the chart, got %+v", app)
+	}
+
+	got, err := store.List(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].App != "chart/openfaas" || got[1].App != "openfaas" {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+
+	if err := store.Delete(cluster, ChartPrefix+"openfaas"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(cluster, "openfaas"); err != nil {
+		t.Fatalf("want the app's record kept, got %v", err)
+	}
+}
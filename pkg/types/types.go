@@ -1,7 +1,10 @@
 package types
 
 import (
+	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/alexellis/arkade/pkg/config"
 )
@@ -14,6 +17,47 @@ type InstallerOptions struct {
 	Helm            *HelmConfig
 	Verbose         bool
 	Secrets         []K8sSecret
+
+	// Requirements are checked before anything is installed.
+	Requirements *Requirements
+}
+
+// Requirements describe what an app needs from the cluster, so that an
+// install can fail before it starts rather than part of the way through.
+type Requirements struct {
+	// KubernetesVersion is the minimum version, i.e. "1.19".
+	KubernetesVersion string
+
+	// APIs are group/versions which must be served, i.e.
+	// "networking.k8s.io/v1".
+	APIs []string
+
+	// Architectures lists the supported node architectures, i.e.
+	// "amd64", any are allowed when empty.
+	Architectures []string
+
+	// CRDs must be installed, i.e. "certificates.cert-manager.io".
+	CRDs []string
+
+	Apps []Prerequisite
+}
+
+// Prerequisite is an app which must already be installed.
+type Prerequisite struct {
+	// Name of the app, i.e. "cert-manager".
+	Name string
+
+	// Resources show that the app is installed, in the form accepted
+	// by "kubectl get", i.e. "crd/certificates.cert-manager.io", or
+	// "ingressclass" for any IngressClass.
+	Resources []string
+
+	// Namespace of the resources, when they are namespaced.
+	Namespace string
+
+	// Hint tells the user how to install the app, by default
+	// "arkade install NAME".
+	Hint string
 }
 
 type K8sSecret struct {
@@ -21,14 +65,35 @@ type K8sSecret struct {
 	Name       string
 	SecretData []SecretsData
 	Namespace  string
+
+	// Generated is set when the values were made by arkade, such as a
+	// key pair, rather than given by the user. An existing secret is then
+	// kept as it is, so that a re-install doesn't replace its keys.
+	Generated bool
 }
 
 type SecretsData struct {
-	Type  string // file or literal
+	Type  string // literal, file, env, stdin, sops or generated
 	Key   string
 	Value string
+
+	// Policy is used for a GeneratedSecret, DefaultPasswordPolicy when
+	// nil.
+	Policy *PasswordPolicy
+}
+
+// PasswordPolicy describes a random password, see GeneratedSecret.
+type PasswordPolicy struct {
+	Length      int
+	Digits      int
+	Symbols     int
+	NoUpper     bool
+	AllowRepeat bool
 }
 
+// DefaultPasswordPolicy gives 25 characters, 10 of them digits.
+var DefaultPasswordPolicy = PasswordPolicy{Length: 25, Digits: 10, AllowRepeat: true}
+
 type HelmConfig struct {
 	Repo        *HelmRepo
 	Helm3       bool
@@ -43,6 +108,18 @@ type HelmRepo struct {
 	Name    string
 	URL     string
 	Version string
+	Auth    HelmRepoAuth
+}
+
+// HelmRepoAuth holds the credentials and TLS files for a private chart
+// repository. OCI registries use the credentials from "arkade oci login"
+// instead of Username and Password.
+type HelmRepoAuth struct {
+	Username string
+	Password string
+	CAFile   string
+	CertFile string
+	KeyFile  string
 }
 
 type InstallerOutput struct {
@@ -78,6 +155,11 @@ func (o *InstallerOptions) WithHelmURL(s string) *InstallerOptions {
 	return o
 }
 
+func (o *InstallerOptions) WithHelmRepoAuth(auth HelmRepoAuth) *InstallerOptions {
+	o.Helm.Repo.Auth = auth
+	return o
+}
+
 func (o *InstallerOptions) WithHelmUpdateRepo(update bool) *InstallerOptions {
 	o.Helm.UpdateRepo = update
 	return o
@@ -104,6 +186,11 @@ func (o *InstallerOptions) WithSecret(secret K8sSecret) *InstallerOptions {
 	return o
 }
 
+func (o *InstallerOptions) WithRequirements(r Requirements) *InstallerOptions {
+	o.Requirements = &r
+	return o
+}
+
 func (o *InstallerOptions) WithInstallNamespace(b bool) *InstallerOptions {
 	o.CreateNamespace = b
 	return o
@@ -136,6 +223,77 @@ func NewGenericSecret(name, namespace string, secretData []SecretsData) K8sSecre
 	}
 }
 
+// NewGeneratedSecret returns a generic secret whose values were made by
+// arkade, which is kept when it already exists.
+func NewGeneratedSecret(name, namespace string, secretData []SecretsData) K8sSecret {
+	secret := NewGenericSecret(name, namespace, secretData)
+	secret.Generated = true
+	return secret
+}
+
 const KubernetesGenericSecret = "generic"
 const StringLiteralSecret = "string-literal"
 const FromFileSecret = "from-file"
+
+// FromEnvSecret reads the environment variable named by Value.
+const FromEnvSecret = "from-env"
+
+// FromStdinSecret reads stdin, without a trailing newline.
+const FromStdinSecret = "from-stdin"
+
+// FromSOPSSecret decrypts the file named by Value with sops, using the
+// age or other keys which sops is configured with, i.e.
+// SOPS_AGE_KEY_FILE.
+const FromSOPSSecret = "from-sops"
+
+// GeneratedSecret is a random password made with Policy. When the
+// secret already exists, its value is kept rather than generated again.
+const GeneratedSecret = "generated"
+
+// ParseSecretSource reads a value for key given on the command line as
+// one of:
+//
+//	env:NAME          the environment variable NAME
+//	stdin             stdin, also "-"
+//	sops:FILE         FILE decrypted with sops
+//	file:FILE         the contents of FILE
+//	generate          a random password, "generate:32" for 32 characters
+//	literal:VALUE     VALUE as given
+func ParseSecretSource(key, source string) (SecretsData, error) {
+	kind, value, _ := strings.Cut(source, ":")
+	data := SecretsData{Key: key, Value: value}
+
+	switch kind {
+	case "env":
+		data.Type = FromEnvSecret
+	case "stdin", "-":
+		data.Type, data.Value = FromStdinSecret, ""
+		if len(value) > 0 {
+			return data, fmt.Errorf("stdin takes no value, got %q", source)
+		}
+	case "sops":
+		data.Type = FromSOPSSecret
+	case "file":
+		data.Type = FromFileSecret
+	case "literal":
+		data.Type = StringLiteralSecret
+	case "generate":
+		policy := DefaultPasswordPolicy
+		if len(value) > 0 {
+			length, err := strconv.Atoi(value)
+			if err != nil || length < policy.Digits {
+				return data, fmt.Errorf("length for generate must be a number of at least %d, got %q", policy.Digits, value)
+			}
+			policy.Length = length
+		}
+		data.Type, data.Value, data.Policy = GeneratedSecret, "", &policy
+		return data, nil
+	default:
+		return data, fmt.Errorf("unknown secret source %q, use one of env:NAME, stdin, sops:FILE, file:FILE, generate or literal:VALUE", source)
+	}
+
+	if len(data.Value) == 0 && data.Type != FromStdinSecret {
+		return data, fmt.Errorf("secret source %q needs a value, i.e. %s:VALUE", source, kind)
+	}
+	return data, nil
+}
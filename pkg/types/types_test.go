@@ -0,0 +1,56 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ParseSecretSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   SecretsData
+	}{
+		{"env:OF_PASSWORD", SecretsData{Type: FromEnvSecret, Key: "password", Value: "OF_PASSWORD"}},
+		{"stdin", SecretsData{Type: FromStdinSecret, Key: "password"}},
+		{"-", SecretsData{Type: FromStdinSecret, Key: "password"}},
+		{"sops:./secrets/password.enc", SecretsData{Type: FromSOPSSecret, Key: "password", Value: "./secrets/password.enc"}},
+		{"file:password.txt", SecretsData{Type: FromFileSecret, Key: "password", Value: "password.txt"}},
+		{"literal:a:b", SecretsData{Type: StringLiteralSecret, Key: "password", Value: "a:b"}},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseSecretSource("password", tc.source)
+		if err != nil {
+			t.Errorf("%s: %v", tc.source, err)
+			continue
+		}
+		if got.Type != tc.want.Type || got.Key != tc.want.Key || got.Value != tc.want.Value {
+			t.Errorf("%s: want %+v, got %+v", tc.source, tc.want, got)
+		}
+	}
+}
+
+func Test_ParseSecretSource_Generate(t *testing.T) {
+	got, err := ParseSecretSource("password", "generate:40")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != GeneratedSecret || got.Policy == nil || got.Policy.Length != 40 || got.Policy.Digits != DefaultPasswordPolicy.Digits {
+		t.Errorf("want a generated 40 character password, got %+v", got)
+	}
+}
+
+func Test_ParseSecretSource_Invalid(t *testing.T) {
+	for source, want := range map[string]string{
+		"vault:secret/of": "unknown secret source",
+		"env:":            "needs a value",
+		"stdin:x":         "stdin takes no value",
+		"generate:5":      "at least 10",
+		"generate:lots":   "at least 10",
+	} {
+		_, err := ParseSecretSource("password", source)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: want error containing %q, got %v", source, want, err)
+		}
+	}
+}